@@ -14,11 +14,14 @@ import (
 )
 
 type Swagger struct {
+	Swagger             string                         `json:"swagger"`
 	Info                openapi3.Info                  `json:"info"`
 	ExternalDocs        *openapi3.ExternalDocs         `json:"externalDocs,omitempty"`
 	Schemes             []string                       `json:"schemes,omitempty"`
 	Host                string                         `json:"host,omitempty"`
 	BasePath            string                         `json:"basePath,omitempty"`
+	Consumes            []string                       `json:"consumes,omitempty"`
+	Produces            []string                       `json:"produces,omitempty"`
 	Paths               map[string]*PathItem           `json:"paths,omitempty"`
 	Definitions         map[string]*openapi3.SchemaRef `json:"definitions,omitempty,noref"`
 	Parameters          map[string]*Parameter          `json:"parameters,omitempty,noref"`
@@ -133,28 +136,36 @@ type Operation struct {
 	Consumes     []string               `json:"consumes,omitempty"`
 	Produces     []string               `json:"produces,omitempty"`
 	Security     *SecurityRequirements  `json:"security,omitempty"`
+	Deprecated   bool                   `json:"deprecated,omitempty"`
 }
 
 type Parameters []*Parameter
 
 type Parameter struct {
-	Ref          string              `json:"$ref,omitempty"`
-	In           string              `json:"in,omitempty"`
-	Name         string              `json:"name,omitempty"`
-	Description  string              `json:"description,omitempty"`
-	Required     bool                `json:"required,omitempty"`
-	UniqueItems  bool                `json:"uniqueItems,omitempty"`
-	ExclusiveMin bool                `json:"exclusiveMinimum,omitempty"`
-	ExclusiveMax bool                `json:"exclusiveMaximum,omitempty"`
-	Schema       *openapi3.SchemaRef `json:"schema,omitempty"`
-	Type         string              `json:"type,omitempty"`
-	Format       string              `json:"format,omitempty"`
-	Enum         []interface{}       `json:"enum,omitempty"`
-	Minimum      *float64            `json:"minimum,omitempty"`
-	Maximum      *float64            `json:"maximum,omitempty"`
-	MinLength    uint64              `json:"minLength,omitempty"`
-	MaxLength    *uint64             `json:"maxLength,omitempty"`
-	Pattern      string              `json:"pattern,omitempty"`
+	Ref              string              `json:"$ref,omitempty"`
+	In               string              `json:"in,omitempty"`
+	Name             string              `json:"name,omitempty"`
+	Description      string              `json:"description,omitempty"`
+	Required         bool                `json:"required,omitempty"`
+	UniqueItems      bool                `json:"uniqueItems,omitempty"`
+	ExclusiveMin     bool                `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMax     bool                `json:"exclusiveMaximum,omitempty"`
+	Schema           *openapi3.SchemaRef `json:"schema,omitempty"`
+	Type             string              `json:"type,omitempty"`
+	Format           string              `json:"format,omitempty"`
+	Enum             []interface{}       `json:"enum,omitempty"`
+	Minimum          *float64            `json:"minimum,omitempty"`
+	Maximum          *float64            `json:"maximum,omitempty"`
+	MinLength        uint64              `json:"minLength,omitempty"`
+	MaxLength        *uint64             `json:"maxLength,omitempty"`
+	Pattern          string              `json:"pattern,omitempty"`
+	Items            *openapi3.SchemaRef `json:"items,omitempty"`
+	CollectionFormat string              `json:"collectionFormat,omitempty"`
+	AllowEmptyValue  bool                `json:"allowEmptyValue,omitempty"`
+	MultipleOf       *float64            `json:"multipleOf,omitempty"`
+	MinItems         uint64              `json:"minItems,omitempty"`
+	MaxItems         *uint64             `json:"maxItems,omitempty"`
+	Default          interface{}         `json:"default,omitempty"`
 }
 
 type Response struct {
@@ -166,22 +177,29 @@ type Response struct {
 }
 
 type Header struct {
-	Ref         string `json:"$ref,omitempty"`
-	Description string `json:"description,omitempty"`
-	Type        string `json:"type,omitempty"`
+	Ref              string              `json:"$ref,omitempty"`
+	Description      string              `json:"description,omitempty"`
+	Type             string              `json:"type,omitempty"`
+	Format           string              `json:"format,omitempty"`
+	Items            *openapi3.SchemaRef `json:"items,omitempty"`
+	CollectionFormat string              `json:"collectionFormat,omitempty"`
+	Default          interface{}         `json:"default,omitempty"`
+	Enum             []interface{}       `json:"enum,omitempty"`
 }
 
 type SecurityRequirements []map[string][]string
 
+// SecurityScheme is a security scheme of a Swagger 2.0 document.
+// Scopes maps names of scopes to their descriptions.
 type SecurityScheme struct {
-	Ref              string        `json:"$ref,omitempty"`
-	Description      string        `json:"description,omitempty"`
-	Type             string        `json:"type,omitempty"`
-	In               string        `json:"in,omitempty"`
-	Name             string        `json:"name,omitempty"`
-	Flow             string        `json:"flow,omitempty"`
-	AuthorizationURL string        `json:"authorizationUrl,omitempty"`
-	TokenURL         string        `json:"tokenUrl,omitempty"`
-	Scopes           []string      `json:"scopes,omitempty"`
-	Tags             openapi3.Tags `json:"tags,omitempty"`
+	Ref              string            `json:"$ref,omitempty"`
+	Description      string            `json:"description,omitempty"`
+	Type             string            `json:"type,omitempty"`
+	In               string            `json:"in,omitempty"`
+	Name             string            `json:"name,omitempty"`
+	Flow             string            `json:"flow,omitempty"`
+	AuthorizationURL string            `json:"authorizationUrl,omitempty"`
+	TokenURL         string            `json:"tokenUrl,omitempty"`
+	Scopes           map[string]string `json:"scopes,omitempty"`
+	Tags             openapi3.Tags     `json:"tags,omitempty"`
 }
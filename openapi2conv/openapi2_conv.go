@@ -1,10 +1,12 @@
-// Package openapi2conv converts an OpenAPI v2 specification to v3.
+// Package openapi2conv converts an OpenAPI v2 specification to v3 and back.
 package openapi2conv
 
 import (
 	"errors"
 	"fmt"
 	"net/url"
+	"sort"
+	"strings"
 
 	"github.com/getkin/kin-openapi/openapi2"
 	"github.com/getkin/kin-openapi/openapi3"
@@ -23,7 +25,7 @@ func ToV3Swagger(swagger *openapi2.Swagger) (*openapi3.Swagger, error) {
 		schemes := swagger.Schemes
 		if len(schemes) == 0 {
 			schemes = []string{
-				"https://",
+				"https",
 			}
 		}
 		basePath := swagger.BasePath
@@ -43,7 +45,7 @@ func ToV3Swagger(swagger *openapi2.Swagger) (*openapi3.Swagger, error) {
 		for path, pathItem := range paths {
 			r, err := ToV3PathItem(swagger, pathItem)
 			if err != nil {
-				return nil, err
+				return nil, fmt.Errorf("Path '%s' can't be converted: %v", path, err)
 			}
 			resultPaths[path] = r
 		}
@@ -53,9 +55,14 @@ func ToV3Swagger(swagger *openapi2.Swagger) (*openapi3.Swagger, error) {
 		result.Components.Parameters = make(map[string]*openapi3.ParameterRef)
 		result.Components.RequestBodies = make(map[string]*openapi3.RequestBodyRef)
 		for k, parameter := range parameters {
-			resultParameter, resultRequestBody, err := ToV3Parameter(parameter)
+			if parameter.In == "formData" {
+				// Every operation has at most one request body,
+				// so formData parameters are inlined into operations.
+				continue
+			}
+			resultParameter, resultRequestBody, err := toV3Parameter(parameter, swagger.Consumes)
 			if err != nil {
-				return nil, err
+				return nil, fmt.Errorf("Parameter '%s' can't be converted: %v", k, err)
 			}
 			if resultParameter != nil {
 				result.Components.Parameters[k] = resultParameter
@@ -68,14 +75,20 @@ func ToV3Swagger(swagger *openapi2.Swagger) (*openapi3.Swagger, error) {
 	if responses := swagger.Responses; responses != nil {
 		result.Components.Responses = make(map[string]*openapi3.ResponseRef, len(responses))
 		for k, response := range responses {
-			r, err := ToV3Response(response)
+			r, err := toV3Response(response, swagger.Produces)
 			if err != nil {
-				return nil, err
+				return nil, fmt.Errorf("Response '%s' can't be converted: %v", k, err)
 			}
 			result.Components.Responses[k] = r
 		}
 	}
-	result.Components.Schemas = swagger.Definitions
+	if m := swagger.Definitions; m != nil {
+		resultSchemas := make(map[string]*openapi3.SchemaRef, len(m))
+		for k, v := range m {
+			resultSchemas[k] = ToV3SchemaRef(v)
+		}
+		result.Components.Schemas = resultSchemas
+	}
 	if m := swagger.SecurityDefinitions; m != nil {
 		resultSecuritySchemes := make(map[string]*openapi3.SecuritySchemeRef)
 		for k, v := range m {
@@ -96,18 +109,21 @@ func ToV3PathItem(swagger *openapi2.Swagger, pathItem *openapi2.PathItem) (*open
 	for method, operation := range pathItem.Operations() {
 		resultOperation, err := ToV3Operation(swagger, pathItem, operation)
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("Operation '%s' can't be converted: %v", method, err)
 		}
 		result.SetOperation(method, resultOperation)
 	}
 	for _, parameter := range pathItem.Parameters {
-		v3Parameter, v3RequestBody, err := ToV3Parameter(parameter)
+		switch resolveV2Parameter(swagger, parameter).In {
+		case "body", "formData":
+			// OpenAPI 3 has no path-level request bodies,
+			// so ToV3Operation adds these to every operation.
+			continue
+		}
+		v3Parameter, _, err := ToV3Parameter(parameter)
 		if err != nil {
 			return nil, err
 		}
-		if v3RequestBody != nil {
-			return nil, errors.New("PathItem shouldn't have a body parameter")
-		}
 		result.Parameters = append(result.Parameters, v3Parameter)
 	}
 	return result, nil
@@ -122,13 +138,51 @@ func ToV3Operation(swagger *openapi2.Swagger, pathItem *openapi2.PathItem, opera
 		Summary:     operation.Summary,
 		Description: operation.Description,
 		Tags:        operation.Tags,
+		Deprecated:  operation.Deprecated,
 	}
 	if v := operation.Security; v != nil {
 		resultSecurity := ToV3SecurityRequirements(*v)
 		result.Security = &resultSecurity
 	}
-	for _, parameter := range operation.Parameters {
-		v3Parameter, v3RequestBody, err := ToV3Parameter(parameter)
+
+	// Operation-level "consumes" and "produces" override the global ones.
+	consumes := operation.Consumes
+	if consumes == nil {
+		consumes = swagger.Consumes
+	}
+	produces := operation.Produces
+	if produces == nil {
+		produces = swagger.Produces
+	}
+
+	// Path-level body and formData parameters apply to the operation
+	// unless the operation overrides them.
+	parameters := operation.Parameters
+	if pathItem != nil {
+		for _, parameter := range pathItem.Parameters {
+			resolved := resolveV2Parameter(swagger, parameter)
+			switch resolved.In {
+			case "body", "formData":
+				if getV2Parameter(swagger, operation.Parameters, resolved.In, resolved.Name) == nil {
+					parameters = append(parameters, parameter)
+				}
+			}
+		}
+	}
+
+	var formDataParameters []*openapi2.Parameter
+	for _, parameter := range parameters {
+		if resolved := resolveV2Parameter(swagger, parameter); resolved.In == "formData" {
+			formDataParameters = append(formDataParameters, resolved)
+			continue
+		} else if resolved.In == "body" && parameter.Ref != "" {
+			// Global body parameters are converted to request bodies.
+			result.RequestBody = &openapi3.RequestBodyRef{
+				Ref: strings.Replace(parameter.Ref, "#/parameters/", "#/components/requestBodies/", 1),
+			}
+			continue
+		}
+		v3Parameter, v3RequestBody, err := toV3Parameter(parameter, consumes)
 		if err != nil {
 			return nil, err
 		}
@@ -138,10 +192,20 @@ func ToV3Operation(swagger *openapi2.Swagger, pathItem *openapi2.PathItem, opera
 			result.Parameters = append(result.Parameters, v3Parameter)
 		}
 	}
+	if len(formDataParameters) > 0 {
+		if result.RequestBody != nil {
+			return nil, errors.New("Operation can't have both body and formData parameters")
+		}
+		v3RequestBody, err := ToV3RequestBodyFormData(formDataParameters, consumes)
+		if err != nil {
+			return nil, err
+		}
+		result.RequestBody = v3RequestBody
+	}
 	if responses := operation.Responses; responses != nil {
 		resultResponses := make(openapi3.Responses, len(responses))
 		for k, response := range responses {
-			result, err := ToV3Response(response)
+			result, err := toV3Response(response, produces)
 			if err != nil {
 				return nil, err
 			}
@@ -152,68 +216,270 @@ func ToV3Operation(swagger *openapi2.Swagger, pathItem *openapi2.PathItem, opera
 	return result, nil
 }
 
+// resolveV2Parameter returns the parameter that the reference points to.
+// The function returns the argument if it's not a reference to a global parameter.
+func resolveV2Parameter(swagger *openapi2.Swagger, parameter *openapi2.Parameter) *openapi2.Parameter {
+	const prefix = "#/parameters/"
+	if ref := parameter.Ref; strings.HasPrefix(ref, prefix) {
+		if resolved := swagger.Parameters[ref[len(prefix):]]; resolved != nil {
+			return resolved
+		}
+	}
+	return parameter
+}
+
+func getV2Parameter(swagger *openapi2.Swagger, parameters openapi2.Parameters, in string, name string) *openapi2.Parameter {
+	for _, parameter := range parameters {
+		if resolved := resolveV2Parameter(swagger, parameter); resolved.In == in && resolved.Name == name {
+			return resolved
+		}
+	}
+	return nil
+}
+
 func ToV3Parameter(parameter *openapi2.Parameter) (*openapi3.ParameterRef, *openapi3.RequestBodyRef, error) {
+	return toV3Parameter(parameter, nil)
+}
+
+func toV3Parameter(parameter *openapi2.Parameter, consumes []string) (*openapi3.ParameterRef, *openapi3.RequestBodyRef, error) {
 	if parameter == nil {
 		return nil, nil, nil
 	}
+	if ref := parameter.Ref; len(ref) > 0 {
+		return &openapi3.ParameterRef{
+			Ref: toV3Ref(ref),
+		}, nil, nil
+	}
 	in := parameter.In
-	if in == "body" {
+	switch in {
+	case "body":
 		result := &openapi3.RequestBody{
 			Description: parameter.Description,
 			Required:    parameter.Required,
 		}
 		if schemaRef := parameter.Schema; schemaRef != nil {
-			// Assume it's JSON
-			result.WithJSONSchemaRef(schemaRef)
+			schemaRef = ToV3SchemaRef(schemaRef)
+			if len(consumes) == 0 {
+				// Assume it's JSON
+				result.WithJSONSchemaRef(schemaRef)
+			} else {
+				result.Content = openapi3.NewContent()
+				for _, mediaType := range consumes {
+					result.Content[mediaType] = openapi3.NewMediaType().WithSchemaRef(schemaRef)
+				}
+			}
 		}
 		return nil, &openapi3.RequestBodyRef{
 			Value: result,
 		}, nil
+	case "formData":
+		return nil, nil, fmt.Errorf("Parameter '%s' in formData must be converted with ToV3RequestBodyFormData", parameter.Name)
 	}
 	result := &openapi3.Parameter{
-		In:          in,
-		Name:        parameter.Name,
-		Description: parameter.Description,
-		Required:    parameter.Required,
-		Schema:      parameter.Schema,
+		In:              in,
+		Name:            parameter.Name,
+		Description:     parameter.Description,
+		Required:        parameter.Required,
+		AllowEmptyValue: parameter.AllowEmptyValue,
+		Schema:          ToV3SchemaRef(parameter.Schema),
 	}
 	if parameter.Type != "" {
-		result.Schema = &openapi3.SchemaRef{
-			Value: &openapi3.Schema{
-				Type:         parameter.Type,
-				Format:       parameter.Format,
-				Enum:         parameter.Enum,
-				Min:          parameter.Minimum,
-				Max:          parameter.Maximum,
-				ExclusiveMin: parameter.ExclusiveMin,
-				ExclusiveMax: parameter.ExclusiveMax,
-				MinLength:    parameter.MinLength,
-				MaxLength:    parameter.MaxLength,
-			},
+		result.Schema = toV3ParameterSchema(parameter).NewRef()
+	}
+	if parameter.Type == "array" {
+		style, explode, err := toV3CollectionFormat(in, parameter.CollectionFormat)
+		if err != nil {
+			return nil, nil, err
 		}
+		result.Style = style
+		result.Explode = explode
 	}
 	return &openapi3.ParameterRef{
 		Value: result,
 	}, nil, nil
 }
 
+// ToV3RequestBodyFormData converts OpenAPI 2 "formData" parameters to a request body.
+// Each parameter becomes a property of the body's schema.
+func ToV3RequestBodyFormData(parameters []*openapi2.Parameter, consumes []string) (*openapi3.RequestBodyRef, error) {
+	schema := openapi3.NewObjectSchema()
+	encoding := make(map[string]*openapi3.Encoding)
+	hasFile := false
+	for _, parameter := range parameters {
+		if parameter.Ref != "" {
+			return nil, fmt.Errorf("Parameter reference '%s' can't be resolved", parameter.Ref)
+		}
+		property := toV3ParameterSchema(parameter)
+		property.Description = parameter.Description
+		schema.WithProperty(parameter.Name, property)
+		if parameter.Required {
+			schema.Required = append(schema.Required, parameter.Name)
+		}
+		if parameter.Type == "file" {
+			hasFile = true
+		}
+		if parameter.Type == "array" {
+			style, explode, err := toV3CollectionFormat("formData", parameter.CollectionFormat)
+			if err != nil {
+				return nil, err
+			}
+			encoding[parameter.Name] = &openapi3.Encoding{
+				Style:   style,
+				Explode: *explode,
+			}
+		}
+	}
+
+	// Only form media types are allowed for formData parameters.
+	var mediaTypes []string
+	for _, mediaType := range consumes {
+		switch mediaType {
+		case "application/x-www-form-urlencoded", "multipart/form-data":
+			mediaTypes = append(mediaTypes, mediaType)
+		}
+	}
+	if len(mediaTypes) == 0 {
+		if hasFile {
+			mediaTypes = []string{"multipart/form-data"}
+		} else {
+			mediaTypes = []string{"application/x-www-form-urlencoded"}
+		}
+	}
+	result := &openapi3.RequestBody{
+		Required: len(schema.Required) > 0,
+		Content:  openapi3.NewContent(),
+	}
+	for _, mediaType := range mediaTypes {
+		v := openapi3.NewMediaType().WithSchema(schema)
+		if mediaType == "application/x-www-form-urlencoded" && len(encoding) > 0 {
+			v.Encoding = encoding
+		}
+		result.Content[mediaType] = v
+	}
+	return &openapi3.RequestBodyRef{
+		Value: result,
+	}, nil
+}
+
+// toV3ParameterSchema returns a schema that has constraints of a non-body parameter.
+func toV3ParameterSchema(parameter *openapi2.Parameter) *openapi3.Schema {
+	schema := &openapi3.Schema{
+		Type:         parameter.Type,
+		Format:       parameter.Format,
+		Enum:         parameter.Enum,
+		Default:      parameter.Default,
+		Min:          parameter.Minimum,
+		Max:          parameter.Maximum,
+		ExclusiveMin: parameter.ExclusiveMin,
+		ExclusiveMax: parameter.ExclusiveMax,
+		MultipleOf:   parameter.MultipleOf,
+		MinLength:    parameter.MinLength,
+		MaxLength:    parameter.MaxLength,
+		Pattern:      parameter.Pattern,
+		MinItems:     parameter.MinItems,
+		MaxItems:     parameter.MaxItems,
+		UniqueItems:  parameter.UniqueItems,
+		Items:        ToV3SchemaRef(parameter.Items),
+	}
+	if schema.Type == "file" {
+		schema.Type = "string"
+		schema.Format = "binary"
+	}
+	return schema
+}
+
+// toV3CollectionFormat returns the serialization style that corresponds to the collection format of an array.
+func toV3CollectionFormat(in string, collectionFormat string) (string, *bool, error) {
+	explode := false
+	switch collectionFormat {
+	case "", "csv":
+		switch in {
+		case "query", "formData":
+			return openapi3.SerializationForm, &explode, nil
+		default:
+			return openapi3.SerializationSimple, &explode, nil
+		}
+	case "ssv", "pipes":
+		// OpenAPI 3 delimits arrays with spaces and pipes only in queries and forms.
+		if in != "query" && in != "formData" {
+			return "", nil, fmt.Errorf("Collection format '%s' of parameters in '%s' is not supported by OpenAPI 3", collectionFormat, in)
+		}
+		if collectionFormat == "ssv" {
+			return openapi3.SerializationSpaceDelimited, &explode, nil
+		}
+		return openapi3.SerializationPipeDelimited, &explode, nil
+	case "multi":
+		explode = true
+		return openapi3.SerializationForm, &explode, nil
+	default:
+		return "", nil, fmt.Errorf("Collection format '%s' is not supported by OpenAPI 3", collectionFormat)
+	}
+}
+
 func ToV3Response(response *openapi2.Response) (*openapi3.ResponseRef, error) {
+	return toV3Response(response, nil)
+}
+
+func toV3Response(response *openapi2.Response, produces []string) (*openapi3.ResponseRef, error) {
 	if ref := response.Ref; len(ref) > 0 {
 		return &openapi3.ResponseRef{
-			Ref: ref,
+			Ref: toV3Ref(ref),
 		}, nil
 	}
 	result := &openapi3.Response{
 		Description: response.Description,
 	}
 	if schemaRef := response.Schema; schemaRef != nil {
-		result.WithJSONSchemaRef(schemaRef)
+		schemaRef = ToV3SchemaRef(schemaRef)
+		if len(produces) == 0 {
+			result.WithJSONSchemaRef(schemaRef)
+		} else {
+			result.Content = openapi3.NewContent()
+			for _, mediaType := range produces {
+				result.Content[mediaType] = openapi3.NewMediaType().WithSchemaRef(schemaRef)
+			}
+		}
+	}
+	for mediaType, example := range response.Examples {
+		if result.Content == nil {
+			result.Content = openapi3.NewContent()
+		}
+		v := result.Content[mediaType]
+		if v == nil {
+			v = openapi3.NewMediaType()
+			result.Content[mediaType] = v
+		}
+		v.Example = example
+	}
+	if headers := response.Headers; headers != nil {
+		result.Headers = make(map[string]*openapi3.HeaderRef, len(headers))
+		for k, header := range headers {
+			schema := &openapi3.Schema{
+				Type:    header.Type,
+				Format:  header.Format,
+				Default: header.Default,
+				Enum:    header.Enum,
+				Items:   ToV3SchemaRef(header.Items),
+			}
+			result.Headers[k] = &openapi3.HeaderRef{
+				Value: &openapi3.Header{
+					Description: header.Description,
+					Schema:      schema.NewRef(),
+				},
+			}
+		}
 	}
 	return &openapi3.ResponseRef{
 		Value: result,
 	}, nil
 }
 
+// ToV3SchemaRef returns a copy of the schema where references to OpenAPI 2 definitions
+// are replaced with references to OpenAPI 3 components.
+func ToV3SchemaRef(schema *openapi3.SchemaRef) *openapi3.SchemaRef {
+	return convertSchemaRef(schema, toV3Ref)
+}
+
 func ToV3SecurityRequirements(requirements openapi2.SecurityRequirements) openapi3.SecurityRequirements {
 	if requirements == nil {
 		return nil
@@ -244,9 +510,9 @@ func ToV3SecurityScheme(securityScheme *openapi2.SecurityScheme) (*openapi3.Secu
 		result.Type = "oauth2"
 		flows := &openapi3.OAuthFlows{}
 		result.Flows = flows
-		scopesMap := make(map[string]string)
-		for _, scope := range securityScheme.Scopes {
-			scopesMap[scope] = ""
+		scopesMap := make(map[string]string, len(securityScheme.Scopes))
+		for scope, description := range securityScheme.Scopes {
+			scopesMap[scope] = description
 		}
 		flow := &openapi3.OAuthFlow{
 			AuthorizationURL: securityScheme.AuthorizationURL,
@@ -260,6 +526,8 @@ func ToV3SecurityScheme(securityScheme *openapi2.SecurityScheme) (*openapi3.Secu
 			flows.AuthorizationCode = flow
 		case "password":
 			flows.Password = flow
+		case "application":
+			flows.ClientCredentials = flow
 		default:
 			return nil, fmt.Errorf("Unsupported flow '%s'", securityScheme.Flow)
 		}
@@ -272,47 +540,72 @@ func ToV3SecurityScheme(securityScheme *openapi2.SecurityScheme) (*openapi3.Secu
 
 func FromV3Swagger(swagger *openapi3.Swagger) (*openapi2.Swagger, error) {
 	result := &openapi2.Swagger{
-		Info: swagger.Info,
-		Tags: swagger.Components.Tags,
+		Swagger: "2.0",
+		Info:    swagger.Info,
+		Tags:    swagger.Components.Tags,
 	}
-	isHTTPS := false
-	isHTTP := false
-	servers := swagger.Servers
-	for i, server := range servers {
-		parsedURL, err := url.Parse(server.URL)
-		if err == nil {
-			// See which schemes seem to be supported
-			if parsedURL.Scheme == "https" {
-				isHTTPS = true
-			} else if parsedURL.Scheme == "http" {
-				isHTTP = true
+	if err := fromV3Servers(result, swagger.Servers); err != nil {
+		return nil, err
+	}
+	if paths := swagger.Paths; paths != nil {
+		resultPaths := make(map[string]*openapi2.PathItem, len(paths))
+		for path, pathItem := range paths {
+			if pathItem == nil {
+				continue
 			}
-			// The first server is assumed to provide the base path
-			if i == 0 {
-				result.Host = parsedURL.Host
-				result.BasePath = parsedURL.Path
+			r, err := FromV3PathItem(swagger, pathItem)
+			if err != nil {
+				return nil, fmt.Errorf("Path '%s' can't be converted: %v", path, err)
 			}
+			resultPaths[path] = r
 		}
+		result.Paths = resultPaths
 	}
-	if isHTTPS {
-		result.Schemes = append(result.Schemes, "https")
-	}
-	if isHTTP {
-		result.Schemes = append(result.Schemes, "http")
+	if m := swagger.Components.Schemas; m != nil {
+		resultDefinitions := make(map[string]*openapi3.SchemaRef, len(m))
+		for k, v := range m {
+			resultDefinitions[k] = FromV3SchemaRef(v)
+		}
+		result.Definitions = resultDefinitions
 	}
-	for path, pathItem := range swagger.Paths {
-		if pathItem == nil {
-			continue
+	if m := swagger.Components.Parameters; m != nil {
+		result.Parameters = make(map[string]*openapi2.Parameter, len(m))
+		for k, v := range m {
+			r, err := FromV3Parameter(v)
+			if err != nil {
+				return nil, fmt.Errorf("Parameter '%s' can't be converted: %v", k, err)
+			}
+			result.Parameters[k] = r
 		}
-		for method, operation := range pathItem.Operations() {
-			if operation == nil {
+	}
+	if m := swagger.Components.RequestBodies; m != nil {
+		for k, v := range m {
+			if formDataMediaType(v.Value) != nil {
+				// Form bodies become several formData parameters, so they are inlined
+				// into every operation that uses them.
 				continue
 			}
-			resultOperation, err := FromV3Operation(swagger, operation)
+			if _, exists := result.Parameters[k]; exists {
+				return nil, fmt.Errorf("Request body '%s' conflicts with a parameter of the same name", k)
+			}
+			r, err := FromV3RequestBody(swagger, nil, v)
 			if err != nil {
-				return nil, err
+				return nil, fmt.Errorf("Request body '%s' can't be converted: %v", k, err)
+			}
+			if result.Parameters == nil {
+				result.Parameters = make(map[string]*openapi2.Parameter, len(m))
+			}
+			result.Parameters[k] = r
+		}
+	}
+	if m := swagger.Components.Responses; m != nil {
+		result.Responses = make(map[string]*openapi2.Response, len(m))
+		for k, v := range m {
+			r, err := FromV3Response(v)
+			if err != nil {
+				return nil, fmt.Errorf("Response '%s' can't be converted: %v", k, err)
 			}
-			result.AddOperation(path, method, resultOperation)
+			result.Responses[k] = r
 		}
 	}
 	if m := swagger.Components.SecuritySchemes; m != nil {
@@ -330,6 +623,39 @@ func FromV3Swagger(swagger *openapi3.Swagger) (*openapi2.Swagger, error) {
 	return result, nil
 }
 
+// fromV3Servers sets host, base path and schemes of the OpenAPI 2 specification.
+// OpenAPI 2 has a single host and base path, so all servers must agree on them.
+func fromV3Servers(result *openapi2.Swagger, servers openapi3.Servers) error {
+	for i, server := range servers {
+		parsedURL, err := url.Parse(fromV3ServerURL(server))
+		if err != nil {
+			return fmt.Errorf("Server URL '%s' is invalid: %v", server.URL, err)
+		}
+		if i == 0 {
+			result.Host = parsedURL.Host
+			result.BasePath = parsedURL.Path
+		} else if parsedURL.Host != result.Host || parsedURL.Path != result.BasePath {
+			return fmt.Errorf("Servers '%s' and '%s' have different hosts or base paths, which is not supported by OpenAPI 2",
+				servers[0].URL, server.URL)
+		}
+		if scheme := parsedURL.Scheme; scheme != "" && !containsString(result.Schemes, scheme) {
+			result.Schemes = append(result.Schemes, scheme)
+		}
+	}
+	return nil
+}
+
+// fromV3ServerURL returns the server URL with variables replaced by their default values.
+func fromV3ServerURL(server *openapi3.Server) string {
+	u := server.URL
+	for name, variable := range server.Variables {
+		if variable != nil && variable.Default != nil {
+			u = strings.Replace(u, "{"+name+"}", fmt.Sprint(variable.Default), -1)
+		}
+	}
+	return u
+}
+
 func FromV3SecurityRequirements(requirements openapi3.SecurityRequirements) openapi2.SecurityRequirements {
 	if requirements == nil {
 		return nil
@@ -342,11 +668,18 @@ func FromV3SecurityRequirements(requirements openapi3.SecurityRequirements) open
 }
 
 func FromV3PathItem(swagger *openapi3.Swagger, pathItem *openapi3.PathItem) (*openapi2.PathItem, error) {
+	if len(pathItem.Servers) > 0 {
+		return nil, errors.New("Path servers are not supported by OpenAPI 2")
+	}
 	result := &openapi2.PathItem{}
 	for method, operation := range pathItem.Operations() {
+		switch method {
+		case "CONNECT", "TRACE":
+			return nil, fmt.Errorf("HTTP method '%s' is not supported by OpenAPI 2", method)
+		}
 		r, err := FromV3Operation(swagger, operation)
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("Operation '%s' can't be converted: %v", method, err)
 		}
 		result.SetOperation(method, r)
 	}
@@ -361,6 +694,9 @@ func FromV3PathItem(swagger *openapi3.Swagger, pathItem *openapi3.PathItem) (*op
 }
 
 func findNameForRequestBody(operation *openapi3.Operation) string {
+	if operation == nil {
+		return attemptedBodyParameterNames[0]
+	}
 nameSearch:
 	for _, name := range attemptedBodyParameterNames {
 		for _, parameterRef := range operation.Parameters {
@@ -378,11 +714,18 @@ func FromV3Operation(swagger *openapi3.Swagger, operation *openapi3.Operation) (
 	if operation == nil {
 		return nil, nil
 	}
+	if len(operation.Callbacks) > 0 {
+		return nil, errors.New("Callbacks are not supported by OpenAPI 2")
+	}
+	if v := operation.Servers; v != nil && len(*v) > 0 {
+		return nil, errors.New("Operation servers are not supported by OpenAPI 2")
+	}
 	result := &openapi2.Operation{
 		OperationID: operation.OperationID,
 		Summary:     operation.Summary,
 		Description: operation.Description,
 		Tags:        operation.Tags,
+		Deprecated:  operation.Deprecated,
 	}
 	if v := operation.Security; v != nil {
 		resultSecurity := FromV3SecurityRequirements(*v)
@@ -396,22 +739,43 @@ func FromV3Operation(swagger *openapi3.Swagger, operation *openapi3.Operation) (
 		result.Parameters = append(result.Parameters, r)
 	}
 	if v := operation.RequestBody; v != nil {
-		r, err := FromV3RequestBody(swagger, operation, v)
-		if err != nil {
-			return nil, err
+		if requestBody := v.Value; requestBody != nil {
+			result.Consumes = mediaTypes(requestBody.Content)
+		}
+		if mediaType := formDataMediaType(v.Value); mediaType != nil {
+			r, err := FromV3RequestBodyFormData(mediaType)
+			if err != nil {
+				return nil, err
+			}
+			result.Parameters = append(result.Parameters, r...)
+		} else {
+			r, err := FromV3RequestBody(swagger, operation, v)
+			if err != nil {
+				return nil, err
+			}
+			result.Parameters = append(result.Parameters, r)
 		}
-		result.Parameters = append(result.Parameters, r)
 	}
 	if responses := operation.Responses; responses != nil {
 		resultResponses := make(map[string]*openapi2.Response, len(responses))
 		result.Responses = resultResponses
+		var produces []string
 		for k, response := range responses {
 			r, err := FromV3Response(response)
 			if err != nil {
 				return nil, err
 			}
 			resultResponses[k] = r
+			if v := response.Value; v != nil {
+				for _, mediaType := range mediaTypes(v.Content) {
+					if !containsString(produces, mediaType) {
+						produces = append(produces, mediaType)
+					}
+				}
+			}
 		}
+		sort.Strings(produces)
+		result.Produces = produces
 	}
 	return result, nil
 }
@@ -419,7 +783,7 @@ func FromV3Operation(swagger *openapi3.Swagger, operation *openapi3.Operation) (
 func FromV3RequestBody(swagger *openapi3.Swagger, operation *openapi3.Operation, requestBodyRef *openapi3.RequestBodyRef) (*openapi2.Parameter, error) {
 	if ref := requestBodyRef.Ref; len(ref) > 0 {
 		return &openapi2.Parameter{
-			Ref: ref,
+			Ref: fromV3Ref(ref),
 		}, nil
 	}
 	requestBody := requestBodyRef.Value
@@ -438,10 +802,40 @@ func FromV3RequestBody(swagger *openapi3.Swagger, operation *openapi3.Operation,
 		Required:    requestBody.Required,
 	}
 
-	// Add JSON schema
-	mediaType := requestBody.GetMediaType("application/json")
-	if mediaType != nil {
-		result.Schema = mediaType.Schema
+	// Add schema, preferring JSON
+	if mediaType := preferredMediaType(requestBody.Content); mediaType != nil {
+		result.Schema = FromV3SchemaRef(mediaType.Schema)
+	}
+	return result, nil
+}
+
+// FromV3RequestBodyFormData converts a form request body to OpenAPI 2 "formData" parameters.
+// Every property of the body's schema becomes a parameter.
+func FromV3RequestBodyFormData(mediaType *openapi3.MediaType) (openapi2.Parameters, error) {
+	schemaRef := mediaType.Schema
+	if schemaRef == nil || schemaRef.Value == nil {
+		return nil, nil
+	}
+	schema := schemaRef.Value
+	names := make([]string, 0, len(schema.Properties))
+	for name := range schema.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	result := make(openapi2.Parameters, 0, len(names))
+	for _, name := range names {
+		property := schema.Properties[name].Value
+		if property == nil {
+			return nil, fmt.Errorf("Form property '%s' has an unresolved schema", name)
+		}
+		parameter := &openapi2.Parameter{
+			In:          "formData",
+			Name:        name,
+			Description: property.Description,
+			Required:    containsString(schema.Required, name),
+		}
+		fromV3ParameterSchema(parameter, property)
+		result = append(result, parameter)
 	}
 	return result, nil
 }
@@ -449,59 +843,152 @@ func FromV3RequestBody(swagger *openapi3.Swagger, operation *openapi3.Operation,
 func FromV3Parameter(ref *openapi3.ParameterRef) (*openapi2.Parameter, error) {
 	if v := ref.Ref; len(v) > 0 {
 		return &openapi2.Parameter{
-			Ref: v,
+			Ref: fromV3Ref(v),
 		}, nil
 	}
 	parameter := ref.Value
 	if parameter == nil {
 		return nil, nil
 	}
+	if parameter.In == openapi3.ParameterInCookie {
+		return nil, fmt.Errorf("Cookie parameter '%s' is not supported by OpenAPI 2", parameter.Name)
+	}
 	result := &openapi2.Parameter{
 		Description: parameter.Description,
 		In:          parameter.In,
 		Name:        parameter.Name,
 		Required:    parameter.Required,
-		Schema:      parameter.Schema,
-	}
-	if schemaRef := parameter.Schema; schemaRef != nil {
-		schema := schemaRef.Value
-		result.Type = schema.Type
-		result.Format = schema.Format
-		result.Enum = schema.Enum
-		result.Minimum = schema.Min
-		result.Maximum = schema.Min
-		result.ExclusiveMin = schema.ExclusiveMin
-		result.ExclusiveMax = schema.ExclusiveMax
-		result.MinLength = schema.MinLength
-		result.MaxLength = schema.MaxLength
-		result.Pattern = schema.Pattern
+	}
+	if schemaRef := parameter.Schema; schemaRef != nil && schemaRef.Value != nil {
+		fromV3ParameterSchema(result, schemaRef.Value)
 	}
 	return result, nil
 }
 
+// fromV3ParameterSchema copies the constraints of a schema into a non-body OpenAPI 2 parameter.
+func fromV3ParameterSchema(result *openapi2.Parameter, schema *openapi3.Schema) {
+	result.Type = schema.Type
+	result.Format = schema.Format
+	if result.In == "formData" && schema.Type == "string" && schema.Format == "binary" {
+		result.Type = "file"
+		result.Format = ""
+	}
+	result.Enum = schema.Enum
+	result.Minimum = schema.Min
+	result.Maximum = schema.Max
+	result.ExclusiveMin = schema.ExclusiveMin
+	result.ExclusiveMax = schema.ExclusiveMax
+	result.MinLength = schema.MinLength
+	result.MaxLength = schema.MaxLength
+	result.Pattern = schema.Pattern
+	result.UniqueItems = schema.UniqueItems
+	if items := schema.Items; items != nil && items.Value != nil {
+		// Items of non-body parameters can't be references in OpenAPI 2.
+		result.Items = FromV3SchemaRef(&openapi3.SchemaRef{Value: items.Value})
+	}
+}
+
 func FromV3Response(ref *openapi3.ResponseRef) (*openapi2.Response, error) {
+	if v := ref.Ref; len(v) > 0 {
+		return &openapi2.Response{
+			Ref: fromV3Ref(v),
+		}, nil
+	}
 	response := ref.Value
 	if response == nil {
 		return nil, nil
 	}
+	if len(response.Links) > 0 {
+		return nil, errors.New("Response links are not supported by OpenAPI 2")
+	}
 	result := &openapi2.Response{
 		Description: response.Description,
 	}
-	if content := response.Content; content != nil {
-		if ct := content["application/json"]; ct != nil {
-			result.Schema = ct.Schema
+	if mediaType := preferredMediaType(response.Content); mediaType != nil {
+		result.Schema = FromV3SchemaRef(mediaType.Schema)
+	}
+	if headers := response.Headers; headers != nil {
+		result.Headers = make(map[string]*openapi2.Header, len(headers))
+		for k, v := range headers {
+			header := v.Value
+			if header == nil {
+				return nil, fmt.Errorf("Header '%s' has an unresolved reference", k)
+			}
+			resultHeader := &openapi2.Header{
+				Description: header.Description,
+			}
+			if schema := header.Schema; schema != nil && schema.Value != nil {
+				resultHeader.Type = schema.Value.Type
+				resultHeader.Format = schema.Value.Format
+				resultHeader.Default = schema.Value.Default
+				resultHeader.Enum = schema.Value.Enum
+				if items := schema.Value.Items; items != nil && items.Value != nil {
+					// Items of headers can't be references in OpenAPI 2.
+					resultHeader.Items = FromV3SchemaRef(&openapi3.SchemaRef{Value: items.Value})
+				}
+			}
+			result.Headers[k] = resultHeader
 		}
 	}
 	return result, nil
 }
 
+// FromV3SchemaRef returns a copy of the schema where references to OpenAPI 3 components
+// are replaced with references to OpenAPI 2 definitions.
+func FromV3SchemaRef(schema *openapi3.SchemaRef) *openapi3.SchemaRef {
+	return convertSchemaRef(schema, fromV3Ref)
+}
+
+// convertSchemaRef returns a deep copy of the schema where references are mapped with the function.
+func convertSchemaRef(schema *openapi3.SchemaRef, convertRef func(string) string) *openapi3.SchemaRef {
+	if schema == nil {
+		return nil
+	}
+	if ref := schema.Ref; len(ref) > 0 {
+		return &openapi3.SchemaRef{
+			Ref:   convertRef(ref),
+			Value: schema.Value,
+		}
+	}
+	value := schema.Value
+	if value == nil {
+		return &openapi3.SchemaRef{}
+	}
+	result := *value
+	result.Items = convertSchemaRef(value.Items, convertRef)
+	result.Not = convertSchemaRef(value.Not, convertRef)
+	result.AdditionalProperties = convertSchemaRef(value.AdditionalProperties, convertRef)
+	result.OneOf = convertSchemaRefs(value.OneOf, convertRef)
+	result.AnyOf = convertSchemaRefs(value.AnyOf, convertRef)
+	result.AllOf = convertSchemaRefs(value.AllOf, convertRef)
+	if properties := value.Properties; properties != nil {
+		result.Properties = make(map[string]*openapi3.SchemaRef, len(properties))
+		for k, v := range properties {
+			result.Properties[k] = convertSchemaRef(v, convertRef)
+		}
+	}
+	return &openapi3.SchemaRef{
+		Value: &result,
+	}
+}
+
+func convertSchemaRefs(schemas []*openapi3.SchemaRef, convertRef func(string) string) []*openapi3.SchemaRef {
+	if schemas == nil {
+		return nil
+	}
+	result := make([]*openapi3.SchemaRef, len(schemas))
+	for i, schema := range schemas {
+		result[i] = convertSchemaRef(schema, convertRef)
+	}
+	return result
+}
+
 func FromV3SecurityScheme(swagger *openapi3.Swagger, ref *openapi3.SecuritySchemeRef) (*openapi2.SecurityScheme, error) {
 	securityScheme := ref.Value
 	if securityScheme == nil {
 		return nil, nil
 	}
 	result := &openapi2.SecurityScheme{
-		Ref:         ref.Ref,
 		Description: securityScheme.Description,
 	}
 	switch securityScheme.Type {
@@ -515,6 +1002,9 @@ func FromV3SecurityScheme(swagger *openapi3.Swagger, ref *openapi3.SecuritySchem
 			result.Name = "Authorization"
 		}
 	case "apiKey":
+		if securityScheme.In == "cookie" {
+			return nil, errors.New("Security scheme of type 'apiKey' in cookie is not supported by OpenAPI 2")
+		}
 		result.Type = "apiKey"
 		result.In = securityScheme.In
 		result.Name = securityScheme.Name
@@ -530,11 +1020,16 @@ func FromV3SecurityScheme(swagger *openapi3.Swagger, ref *openapi3.SecuritySchem
 				result.Flow = "accesscode"
 			} else if flow = flows.Password; flow != nil {
 				result.Flow = "password"
+			} else if flow = flows.ClientCredentials; flow != nil {
+				result.Flow = "application"
 			} else {
-				return nil, nil
+				return nil, errors.New("Security scheme of type 'oauth2' has no flows")
 			}
-			for scope := range flow.Scopes {
-				result.Scopes = append(result.Scopes, scope)
+			result.AuthorizationURL = flow.AuthorizationURL
+			result.TokenURL = flow.TokenURL
+			result.Scopes = make(map[string]string, len(flow.Scopes))
+			for scope, description := range flow.Scopes {
+				result.Scopes[scope] = description
 			}
 		}
 	default:
@@ -543,6 +1038,94 @@ func FromV3SecurityScheme(swagger *openapi3.Swagger, ref *openapi3.SecuritySchem
 	return result, nil
 }
 
+// fromV3Ref rewrites a reference to an OpenAPI 3 component into a reference
+// to the corresponding OpenAPI 2 location.
+func fromV3Ref(ref string) string {
+	for _, v := range refPrefixes {
+		if strings.Contains(ref, v.v3) {
+			return strings.Replace(ref, v.v3, v.v2, 1)
+		}
+	}
+	return ref
+}
+
+func toV3Ref(ref string) string {
+	for _, v := range refPrefixes {
+		if strings.Contains(ref, v.v2) {
+			return strings.Replace(ref, v.v2, v.v3, 1)
+		}
+	}
+	return ref
+}
+
+var refPrefixes = []struct {
+	v2 string
+	v3 string
+}{
+	{v2: "#/definitions/", v3: "#/components/schemas/"},
+	{v2: "#/parameters/", v3: "#/components/parameters/"},
+	{v2: "#/parameters/", v3: "#/components/requestBodies/"},
+	{v2: "#/responses/", v3: "#/components/responses/"},
+}
+
+// formDataMediaType returns the form media type of a request body
+// if the body can only be sent as a form.
+// A multipart form is preferred if the body can be sent as both kinds of forms.
+func formDataMediaType(requestBody *openapi3.RequestBody) *openapi3.MediaType {
+	if requestBody == nil {
+		return nil
+	}
+	for k := range requestBody.Content {
+		switch k {
+		case "application/x-www-form-urlencoded", "multipart/form-data":
+		default:
+			return nil
+		}
+	}
+	for _, k := range []string{"multipart/form-data", "application/x-www-form-urlencoded"} {
+		if v := requestBody.Content[k]; v != nil {
+			return v
+		}
+	}
+	return nil
+}
+
+// preferredMediaType returns the JSON media type of the content if there is one.
+// Otherwise it returns the first media type in alphabetical order.
+func preferredMediaType(content openapi3.Content) *openapi3.MediaType {
+	if v := content["application/json"]; v != nil {
+		return v
+	}
+	for _, k := range mediaTypes(content) {
+		if v := content[k]; v != nil {
+			return v
+		}
+	}
+	return nil
+}
+
+// mediaTypes returns sorted media types of the content.
+func mediaTypes(content openapi3.Content) []string {
+	if len(content) == 0 {
+		return nil
+	}
+	result := make([]string, 0, len(content))
+	for k := range content {
+		result = append(result, k)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
 var attemptedBodyParameterNames = []string{
 	"body",
 	"requestBody",
@@ -1,7 +1,9 @@
 package openapi2conv_test
 
 import (
+	"context"
 	"encoding/json"
+	"io/ioutil"
 	"testing"
 
 	"github.com/getkin/kin-openapi/openapi2"
@@ -22,6 +24,142 @@ func TestConvOpenAPIV3ToV2(t *testing.T) {
 	require.JSONEq(t, exampleV2, string(data))
 }
 
+func TestConvOpenAPIV3ToV2Components(t *testing.T) {
+	swagger3, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(exampleComponentsV3))
+	require.NoError(t, err)
+
+	actualV2, err := openapi2conv.FromV3Swagger(swagger3)
+	require.NoError(t, err)
+	data, err := json.Marshal(actualV2)
+	require.NoError(t, err)
+	require.JSONEq(t, exampleComponentsV2, string(data))
+}
+
+func TestConvOpenAPIV3ToV2FormsAndHeaders(t *testing.T) {
+	swagger3, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(`
+openapi: 3.0.0
+info:
+  title: Uploads
+  version: "1.0"
+paths:
+  /uploads:
+    post:
+      requestBody:
+        content:
+          application/x-www-form-urlencoded:
+            schema:
+              type: object
+              properties:
+                name:
+                  type: string
+          multipart/form-data:
+            schema:
+              type: object
+              properties:
+                file:
+                  type: string
+                  format: binary
+      responses:
+        "201":
+          description: uploaded
+          headers:
+            X-Tags:
+              schema:
+                type: array
+                items:
+                  type: string
+            X-Expires:
+              schema:
+                type: string
+                format: date-time
+`))
+	require.NoError(t, err)
+
+	for i := 0; i < 10; i++ {
+		swagger2, err := openapi2conv.FromV3Swagger(swagger3)
+		require.NoError(t, err)
+		operation := swagger2.Paths["/uploads"].Post
+		require.Len(t, operation.Parameters, 1)
+		require.Equal(t, "file", operation.Parameters[0].Name)
+		require.Equal(t, "file", operation.Parameters[0].Type)
+
+		headers := operation.Responses["201"].Headers
+		require.Equal(t, "date-time", headers["X-Expires"].Format)
+		require.Equal(t, "array", headers["X-Tags"].Type)
+		require.NotNil(t, headers["X-Tags"].Items)
+		require.Equal(t, "string", headers["X-Tags"].Items.Value.Type)
+	}
+}
+
+func TestConvOpenAPIV3ToV2Unconvertible(t *testing.T) {
+	testCases := []struct {
+		name    string
+		swagger *openapi3.Swagger
+	}{
+		{
+			name: "different servers",
+			swagger: &openapi3.Swagger{
+				Servers: openapi3.Servers{
+					{URL: "https://a.example.com/v1"},
+					{URL: "https://b.example.com/v1"},
+				},
+			},
+		},
+		{
+			name: "callbacks",
+			swagger: &openapi3.Swagger{
+				Paths: openapi3.Paths{
+					"/hook": &openapi3.PathItem{
+						Post: &openapi3.Operation{
+							Callbacks: map[string]*openapi3.CallbackRef{
+								"event": {Value: &openapi3.Callback{}},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "links",
+			swagger: &openapi3.Swagger{
+				Paths: openapi3.Paths{
+					"/item": &openapi3.PathItem{
+						Get: &openapi3.Operation{
+							Responses: openapi3.Responses{
+								"200": {Value: &openapi3.Response{
+									Links: map[string]*openapi3.LinkRef{
+										"self": {Value: &openapi3.Link{OperationID: "getItem"}},
+									},
+								}},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "cookie parameter",
+			swagger: &openapi3.Swagger{
+				Paths: openapi3.Paths{
+					"/item": &openapi3.PathItem{
+						Get: &openapi3.Operation{
+							Parameters: openapi3.Parameters{
+								{Value: openapi3.NewCookieParameter("session")},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := openapi2conv.FromV3Swagger(tc.swagger)
+			require.Error(t, err)
+		})
+	}
+}
+
 func TestConvOpenAPIV2ToV3(t *testing.T) {
 	var swagger2 openapi2.Swagger
 	err := json.Unmarshal([]byte(exampleV2), &swagger2)
@@ -34,8 +172,196 @@ func TestConvOpenAPIV2ToV3(t *testing.T) {
 	require.JSONEq(t, exampleV3, string(data))
 }
 
+func TestConvOpenAPIV2ToV3Petstore(t *testing.T) {
+	data, err := ioutil.ReadFile("testdata/swagger_petstore.json")
+	require.NoError(t, err)
+	var swagger2 openapi2.Swagger
+	err = json.Unmarshal(data, &swagger2)
+	require.NoError(t, err)
+
+	swagger3 := loadV3(t, &swagger2)
+	require.Len(t, swagger3.Servers, 2)
+	require.Equal(t, "https://petstore.swagger.io/v2", swagger3.Servers[0].URL)
+
+	// Per-operation "consumes" and "produces"
+	addPet := swagger3.Paths["/pet"].Post
+	require.Len(t, addPet.RequestBody.Value.Content, 2)
+	require.Equal(t, "#/components/schemas/Pet", addPet.RequestBody.Value.Content.Get("application/xml").Schema.Ref)
+	require.NotNil(t, addPet.RequestBody.Value.Content.Get("application/json").Schema.Value)
+
+	// Array query parameters
+	status := swagger3.Paths["/pet/findByStatus"].Get.Parameters.GetByInAndName("query", "status")
+	require.Equal(t, "form", status.Style)
+	require.True(t, *status.Explode)
+	require.Equal(t, []interface{}{"available", "pending", "sold"}, status.Schema.Value.Items.Value.Enum)
+	tags := swagger3.Paths["/pet/findByTags"].Get
+	require.True(t, tags.Deprecated)
+	require.False(t, *tags.Parameters.GetByInAndName("query", "tags").Explode)
+
+	// Form data
+	updatePetWithForm := swagger3.Paths["/pet/{petId}"].Post
+	require.Len(t, updatePetWithForm.Parameters, 1)
+	form := updatePetWithForm.RequestBody.Value.Content.Get("application/x-www-form-urlencoded")
+	require.NotNil(t, form)
+	require.Len(t, form.Schema.Value.Properties, 2)
+	uploadFile := swagger3.Paths["/pet/{petId}/uploadImage"].Post
+	multipart := uploadFile.RequestBody.Value.Content.Get("multipart/form-data")
+	require.NotNil(t, multipart)
+	file := multipart.Schema.Value.Properties["file"].Value
+	require.Equal(t, "string", file.Type)
+	require.Equal(t, "binary", file.Format)
+
+	// Response headers
+	login := swagger3.Paths["/user/login"].Get.Responses.Get(200).Value
+	require.Len(t, login.Content, 2)
+	require.Equal(t, "integer", login.Headers["X-Rate-Limit"].Value.Schema.Value.Type)
+	require.Equal(t, "date-time", login.Headers["X-Expires-After"].Value.Schema.Value.Format)
+
+	petstoreAuth := swagger3.Components.SecuritySchemes["petstore_auth"].Value
+	require.Equal(t, "read your pets", petstoreAuth.Flows.Implicit.Scopes["read:pets"])
+}
+
+func TestConvOpenAPIV2ToV3Features(t *testing.T) {
+	var swagger2 openapi2.Swagger
+	err := json.Unmarshal([]byte(exampleFeaturesV2), &swagger2)
+	require.NoError(t, err)
+
+	swagger3 := loadV3(t, &swagger2)
+
+	// Global body parameters become request bodies
+	require.Contains(t, swagger3.Components.RequestBodies, "Item")
+	require.NotContains(t, swagger3.Components.Parameters, "Item")
+	require.NotContains(t, swagger3.Components.Parameters, "Note")
+	create := swagger3.Paths["/items"].Post
+	require.Equal(t, "#/components/requestBodies/Item", create.RequestBody.Ref)
+	require.Equal(t, "#/components/responses/Error", create.Responses.Get(400).Ref)
+	require.NotNil(t, create.RequestBody.Value.Content.Get("application/vnd.items+json"))
+
+	// Global "consumes" and "produces"
+	list := swagger3.Paths["/items"].Get
+	require.Equal(t, "#/components/parameters/Limit", list.Parameters[0].Ref)
+	require.Equal(t, "pipeDelimited", list.Parameters.GetByInAndName("query", "ids").Style)
+	require.Equal(t, "spaceDelimited", list.Parameters.GetByInAndName("query", "tags").Style)
+	require.Equal(t, "simple", list.Parameters.GetByInAndName("header", "X-Fields").Style)
+	ok := list.Responses.Get(200).Value
+	require.NotNil(t, ok.Content.Get("application/vnd.items+json"))
+	require.Equal(t, []interface{}{"a"}, ok.Content.Get("application/vnd.items+json").Example)
+
+	// Path-level formData parameters
+	update := swagger3.Paths["/items/{id}"].Put
+	require.Empty(t, update.Parameters)
+	form := update.RequestBody.Value.Content.Get("application/x-www-form-urlencoded")
+	require.Equal(t, []string{"name"}, form.Schema.Value.Required)
+	require.Contains(t, form.Schema.Value.Properties, "note")
+	require.Equal(t, "form", form.Encoding["labels"].Style)
+	require.True(t, form.Encoding["labels"].Explode)
+	require.Len(t, swagger3.Paths["/items/{id}"].Parameters, 1)
+}
+
+func TestConvOpenAPIV2ToV3Unconvertible(t *testing.T) {
+	tests := map[string]string{
+		"body and formData": `{"paths": {"/": {"post": {"parameters": [
+			{"in": "body", "name": "body", "schema": {}},
+			{"in": "formData", "name": "field", "type": "string"}
+		]}}}}`,
+		"tsv collection format": `{"paths": {"/": {"get": {"parameters": [
+			{"in": "query", "name": "ids", "type": "array", "items": {"type": "string"}, "collectionFormat": "tsv"}
+		]}}}}`,
+		"ssv collection format in header": `{"paths": {"/": {"get": {"parameters": [
+			{"in": "header", "name": "X-Ids", "type": "array", "items": {"type": "string"}, "collectionFormat": "ssv"}
+		]}}}}`,
+		"pipes collection format in path": `{"paths": {"/{ids}": {"get": {"parameters": [
+			{"in": "path", "name": "ids", "required": true, "type": "array", "items": {"type": "string"}, "collectionFormat": "pipes"}
+		]}}}}`,
+	}
+	for name, spec := range tests {
+		t.Run(name, func(t *testing.T) {
+			var swagger2 openapi2.Swagger
+			err := json.Unmarshal([]byte(spec), &swagger2)
+			require.NoError(t, err)
+			_, err = openapi2conv.ToV3Swagger(&swagger2)
+			require.Error(t, err)
+		})
+	}
+}
+
+// loadV3 converts the document and loads the result so that references are resolved.
+func loadV3(t *testing.T, swagger2 *openapi2.Swagger) *openapi3.Swagger {
+	converted, err := openapi2conv.ToV3Swagger(swagger2)
+	require.NoError(t, err)
+	data, err := json.Marshal(converted)
+	require.NoError(t, err)
+	swagger3, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData(data)
+	require.NoError(t, err)
+	err = swagger3.Validate(context.Background())
+	require.NoError(t, err)
+	return swagger3
+}
+
+const exampleFeaturesV2 = `
+{
+  "swagger": "2.0",
+  "info": {"title": "Items", "version": "1.0"},
+  "host": "api.example.com",
+  "consumes": ["application/vnd.items+json"],
+  "produces": ["application/vnd.items+json"],
+  "paths": {
+    "/items": {
+      "get": {
+        "parameters": [
+          {"$ref": "#/parameters/Limit"},
+          {"in": "query", "name": "ids", "type": "array", "items": {"type": "integer"}, "collectionFormat": "pipes"},
+          {"in": "query", "name": "tags", "type": "array", "items": {"type": "string"}, "collectionFormat": "ssv"},
+          {"in": "header", "name": "X-Fields", "type": "array", "items": {"type": "string"}}
+        ],
+        "responses": {
+          "200": {
+            "description": "ok",
+            "schema": {"type": "array", "items": {"$ref": "#/definitions/Item"}},
+            "examples": {"application/vnd.items+json": ["a"]}
+          }
+        }
+      },
+      "post": {
+        "parameters": [{"$ref": "#/parameters/Item"}],
+        "responses": {
+          "201": {"description": "created"},
+          "400": {"$ref": "#/responses/Error"}
+        }
+      }
+    },
+    "/items/{id}": {
+      "parameters": [
+        {"in": "path", "name": "id", "type": "string", "required": true},
+        {"$ref": "#/parameters/Note"}
+      ],
+      "put": {
+        "consumes": ["application/x-www-form-urlencoded"],
+        "parameters": [
+          {"in": "formData", "name": "name", "type": "string", "required": true},
+          {"in": "formData", "name": "labels", "type": "array", "items": {"type": "string"}, "collectionFormat": "multi"}
+        ],
+        "responses": {"204": {"description": "updated"}}
+      }
+    }
+  },
+  "parameters": {
+    "Limit": {"in": "query", "name": "limit", "type": "integer", "minimum": 1, "default": 20},
+    "Item": {"in": "body", "name": "item", "required": true, "schema": {"$ref": "#/definitions/Item"}},
+    "Note": {"in": "formData", "name": "note", "type": "string"}
+  },
+  "responses": {
+    "Error": {"description": "error", "schema": {"type": "string"}}
+  },
+  "definitions": {
+    "Item": {"type": "string"}
+  }
+}
+`
+
 const exampleV2 = `
 {
+  "swagger": "2.0",
   "info": {},
   "schemes": ["https"],
   "host": "test.example.com",
@@ -49,6 +375,7 @@ const exampleV2 = `
         "operationId": "example-get",
         "summary": "example get",
         "description": "example get",
+        "consumes": ["application/json"],
         "parameters": [
           {
             "in": "query",
@@ -185,3 +512,157 @@ const exampleV3 = `
   ]
 }
 `
+
+const exampleComponentsV3 = `
+{
+  "openapi": "3.0",
+  "info": {},
+  "servers": [
+    {"url": "http://{env}.example.com/api", "variables": {"env": {"default": "prod"}}},
+    {"url": "https://{env}.example.com/api", "variables": {"env": {"default": "prod"}}}
+  ],
+  "paths": {
+    "/pets": {
+      "post": {
+        "requestBody": {"$ref": "#/components/requestBodies/Pet"},
+        "responses": {
+          "200": {
+            "description": "created pet",
+            "headers": {
+              "X-Rate-Limit": {"schema": {"type": "integer"}}
+            },
+            "content": {
+              "application/json": {"schema": {"$ref": "#/components/schemas/Pet"}},
+              "application/xml": {"schema": {"$ref": "#/components/schemas/Pet"}}
+            }
+          },
+          "default": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/pets/{id}/photo": {
+      "parameters": [{"$ref": "#/components/parameters/id"}],
+      "put": {
+        "requestBody": {
+          "required": true,
+          "content": {
+            "multipart/form-data": {
+              "schema": {
+                "type": "object",
+                "required": ["file"],
+                "properties": {
+                  "file": {"type": "string", "format": "binary"},
+                  "tags": {"type": "array", "items": {"type": "string"}}
+                }
+              }
+            }
+          }
+        },
+        "responses": {
+          "204": {"description": "uploaded"}
+        }
+      }
+    }
+  },
+  "components": {
+    "schemas": {
+      "Pet": {
+        "type": "object",
+        "properties": {
+          "name": {"type": "string"},
+          "owner": {"$ref": "#/components/schemas/Owner"}
+        }
+      },
+      "Owner": {"type": "object"}
+    },
+    "parameters": {
+      "id": {"in": "path", "name": "id", "required": true, "schema": {"type": "integer", "minimum": 1, "maximum": 100}}
+    },
+    "requestBodies": {
+      "Pet": {
+        "content": {
+          "application/json": {"schema": {"$ref": "#/components/schemas/Pet"}}
+        }
+      }
+    },
+    "responses": {
+      "Error": {"description": "error"}
+    },
+    "securitySchemes": {
+      "machine": {
+        "type": "oauth2",
+        "flows": {
+          "clientCredentials": {"tokenUrl": "https://example.com/token", "scopes": {"write": "", "read": ""}}
+        }
+      }
+    }
+  }
+}
+`
+
+const exampleComponentsV2 = `
+{
+  "swagger": "2.0",
+  "info": {},
+  "schemes": ["http", "https"],
+  "host": "prod.example.com",
+  "basePath": "/api",
+  "paths": {
+    "/pets": {
+      "post": {
+        "consumes": ["application/json"],
+        "produces": ["application/json", "application/xml"],
+        "parameters": [{"$ref": "#/parameters/Pet"}],
+        "responses": {
+          "200": {
+            "description": "created pet",
+            "headers": {
+              "X-Rate-Limit": {"type": "integer"}
+            },
+            "schema": {"$ref": "#/definitions/Pet"}
+          },
+          "default": {"$ref": "#/responses/Error"}
+        }
+      }
+    },
+    "/pets/{id}/photo": {
+      "parameters": [{"$ref": "#/parameters/id"}],
+      "put": {
+        "consumes": ["multipart/form-data"],
+        "parameters": [
+          {"in": "formData", "name": "file", "required": true, "type": "file"},
+          {"in": "formData", "name": "tags", "type": "array", "items": {"type": "string"}}
+        ],
+        "responses": {
+          "204": {"description": "uploaded"}
+        }
+      }
+    }
+  },
+  "definitions": {
+    "Pet": {
+      "type": "object",
+      "properties": {
+        "name": {"type": "string"},
+        "owner": {"$ref": "#/definitions/Owner"}
+      }
+    },
+    "Owner": {"type": "object"}
+  },
+  "parameters": {
+    "id": {"in": "path", "name": "id", "required": true, "type": "integer", "minimum": 1, "maximum": 100},
+    "Pet": {"in": "body", "name": "body", "schema": {"$ref": "#/definitions/Pet"}}
+  },
+  "responses": {
+    "Error": {"description": "error"}
+  },
+  "securityDefinitions": {
+    "machine": {
+      "type": "oauth2",
+      "flow": "application",
+      "tokenUrl": "https://example.com/token",
+      "scopes": {"read": "", "write": ""}
+    }
+  }
+}
+`
@@ -0,0 +1,78 @@
+package openapi3
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+)
+
+// ErrorMessages replaces messages and names of fields of errors when errors are rendered,
+// for example by SchemaError.Error. Messages are not changed when the catalog is nil.
+var ErrorMessages *MessageCatalog
+
+// MessageCatalog contains templates (text/template) of messages of errors.
+// Templates of schema errors are keyed by keywords like "minLength",
+// and have data with fields Keyword, Value, Schema, Path, and Property.
+//
+// Catalogs may localize messages, for example with one catalog per language.
+type MessageCatalog struct {
+	templates  map[string]*template.Template
+	fieldNames func(name string) string
+}
+
+// NewMessageCatalog returns an empty catalog.
+func NewMessageCatalog() *MessageCatalog {
+	return &MessageCatalog{templates: make(map[string]*template.Template)}
+}
+
+// Set parses the template of messages with the key.
+func (catalog *MessageCatalog) Set(key string, text string) error {
+	t, err := template.New(key).Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return fmt.Errorf("Message '%s' is invalid: %v", key, err)
+	}
+	catalog.templates[key] = t
+	return nil
+}
+
+// WithMessage is like Set, but panics if the template is invalid.
+func (catalog *MessageCatalog) WithMessage(key string, text string) *MessageCatalog {
+	if err := catalog.Set(key, text); err != nil {
+		panic(err)
+	}
+	return catalog
+}
+
+// WithFieldNames sets the function that maps names of properties and parameters in rendered errors,
+// for example to show user-friendly labels instead of JSON names.
+// Errors keep the names of the document, like in SchemaError.Reason and SchemaError.JSONPointer.
+func (catalog *MessageCatalog) WithFieldNames(f func(name string) string) *MessageCatalog {
+	catalog.fieldNames = f
+	return catalog
+}
+
+// FieldName returns the name of a property or parameter that rendered errors show.
+// Names are not changed when the catalog is nil or doesn't map names.
+func (catalog *MessageCatalog) FieldName(name string) string {
+	if catalog == nil || catalog.fieldNames == nil {
+		return name
+	}
+	return catalog.fieldNames(name)
+}
+
+// Format executes the template of messages with the key.
+// The function returns false if the catalog doesn't have the key or the template fails.
+func (catalog *MessageCatalog) Format(key string, data interface{}) (string, bool) {
+	if catalog == nil {
+		return "", false
+	}
+	t := catalog.templates[key]
+	if t == nil {
+		return "", false
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", false
+	}
+	return buf.String(), true
+}
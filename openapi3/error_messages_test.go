@@ -0,0 +1,51 @@
+package openapi3_test
+
+import (
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaErrorMessage(t *testing.T) {
+	catalog := openapi3.NewMessageCatalog().
+		WithMessage("minLength", "{{.Path}} needs at least {{.Schema.MinLength}} characters").
+		WithMessage("maxLength", "{{.Path}} needs at most {{.Schema.MaxLength}} characters").
+		WithMessage("required", "{{.Property}} is required")
+	require.Error(t, catalog.Set("minimum", "{{.Schema"))
+
+	schema := openapi3.NewObjectSchema().
+		WithProperty("name", openapi3.NewStringSchema().WithMinLength(2).WithMaxLength(5)).
+		WithProperty("tags", openapi3.NewArraySchema().WithItems(openapi3.NewIntegerSchema()))
+	schema.Required = []string{"name"}
+	for _, test := range []struct {
+		value      map[string]interface{}
+		message    string
+		pointer    []string
+		schemaPath []string
+	}{
+		{map[string]interface{}{"name": "a"}, "/name needs at least 2 characters", []string{"name"}, []string{"properties", "name", "minLength"}},
+		{map[string]interface{}{"name": "abcdef"}, "/name needs at most 5 characters", []string{"name"}, []string{"properties", "name", "maxLength"}},
+		{map[string]interface{}{}, "name is required", []string{}, []string{"required"}},
+		{map[string]interface{}{"name": "ab", "tags": []interface{}{1.5}}, "Value must be an integer", []string{"tags", "0"}, []string{"properties", "tags", "items", "type"}},
+	} {
+		err := schema.VisitJSON(test.value)
+		require.IsType(t, &openapi3.SchemaError{}, err)
+		schemaError := err.(*openapi3.SchemaError)
+		require.Equal(t, test.message, schemaError.Message(catalog))
+		require.Equal(t, test.pointer, schemaError.JSONPointer())
+		require.Equal(t, test.schemaPath, schemaError.SchemaPath())
+	}
+
+	err := openapi3.NewStringSchema().WithMinLength(2).VisitJSON("a")
+	require.Equal(t, "Minimum string length is 2", err.(*openapi3.SchemaError).Message(nil))
+
+	openapi3.SchemaErrorDetailsDisabled = true
+	openapi3.ErrorMessages = catalog
+	defer func() {
+		openapi3.SchemaErrorDetailsDisabled = false
+		openapi3.ErrorMessages = nil
+	}()
+	err = schema.VisitJSON(map[string]interface{}{"name": "a"})
+	require.EqualError(t, err, `Error at "/name":/name needs at least 2 characters`)
+}
@@ -0,0 +1,109 @@
+package openapi3
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ExamplesReport is a report of validated examples of a document.
+type ExamplesReport struct {
+	Results []*ExampleResult
+}
+
+// ExampleResult is the outcome of validating an example against the schema next to it.
+type ExampleResult struct {
+	// Pointer is the JSON pointer of the example in the document,
+	// like "#/paths/~1users/get/parameters/0/example".
+	Pointer string
+
+	// Ref is the reference of the example to components.examples, if any.
+	Ref string
+
+	Schema *Schema
+	Err    error
+}
+
+// Passed returns true if every example is valid.
+func (report *ExamplesReport) Passed() bool {
+	return len(report.Failed()) == 0
+}
+
+// Failed returns results of invalid examples.
+func (report *ExamplesReport) Failed() []*ExampleResult {
+	var results []*ExampleResult
+	for _, result := range report.Results {
+		if result.Err != nil {
+			results = append(results, result)
+		}
+	}
+	return results
+}
+
+func (report *ExamplesReport) String() string {
+	var buf strings.Builder
+	for _, result := range report.Failed() {
+		fmt.Fprintf(&buf, "%s: %v\n", result.Pointer, result.Err)
+	}
+	return buf.String()
+}
+
+// ValidateAllExamples validates examples of parameters, headers, and media types
+// in paths and components against their schemas.
+//
+// Examples of components.examples don't have a schema,
+// so they're validated everywhere they're referenced.
+// Examples with an external value and objects that refer to components aren't validated,
+// because the components themselves are.
+func (swagger *Swagger) ValidateAllExamples() *ExamplesReport {
+	report := &ExamplesReport{}
+	Walk(swagger, &Visitor{
+		Parameter: func(pointer string, ref *ParameterRef) error {
+			if ref.Ref != "" || ref.Value == nil {
+				return SkipChildren
+			}
+			parameter := ref.Value
+			report.add(pointer, parameter.Schema, parameter.Example, parameter.Examples)
+			return nil
+		},
+		Header: func(pointer string, ref *HeaderRef) error {
+			if ref.Ref != "" || ref.Value == nil {
+				return SkipChildren
+			}
+			header := ref.Value
+			report.add(pointer, header.Schema, header.Example, header.Examples)
+			return nil
+		},
+		MediaType: func(pointer string, mediaType *MediaType) error {
+			report.add(pointer, mediaType.Schema, mediaType.Example, mediaType.Examples)
+			return nil
+		},
+	})
+	return report
+}
+
+// add validates examples of the object at the pointer against the schema of the object.
+func (report *ExamplesReport) add(pointer string, schemaRef *SchemaRef, example interface{}, examples map[string]*ExampleRef) {
+	if schemaRef == nil || schemaRef.Value == nil {
+		return
+	}
+	schema := schemaRef.Value
+	if example != nil {
+		report.Results = append(report.Results, &ExampleResult{
+			Pointer: pointer + "/example",
+			Schema:  schema,
+			Err:     schema.VisitJSON(example),
+		})
+	}
+	for _, name := range sortedKeys(examples) {
+		ref := examples[name]
+		if ref == nil || ref.Value == nil || ref.Value.Value == nil {
+			continue
+		}
+		report.Results = append(report.Results, &ExampleResult{
+			Pointer: pointer + "/examples/" + escapeJSONPointer(name),
+			Ref:     ref.Ref,
+			Schema:  schema,
+			Err:     schema.VisitJSON(ref.Value.Value),
+		})
+	}
+}
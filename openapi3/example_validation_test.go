@@ -0,0 +1,97 @@
+package openapi3_test
+
+import (
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+)
+
+const examplesSpec = `
+openapi: 3.0.0
+info:
+  title: Users
+  version: "1.0"
+paths:
+  /users/{id}:
+    parameters:
+      - name: id
+        in: path
+        required: true
+        schema:
+          type: integer
+        example: abc
+    get:
+      responses:
+        "200":
+          description: user
+          headers:
+            X-Rate-Limit:
+              schema:
+                type: integer
+              example: 10
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/User"
+              examples:
+                alice:
+                  $ref: "#/components/examples/Alice"
+                bob:
+                  $ref: "#/components/examples/Bob"
+        default:
+          $ref: "#/components/responses/Error"
+components:
+  schemas:
+    User:
+      type: object
+      required: [name]
+      properties:
+        name:
+          type: string
+  examples:
+    Alice:
+      value:
+        name: alice
+    Bob:
+      value:
+        id: 2
+  responses:
+    Error:
+      description: error
+      headers:
+        X-Request-ID:
+          schema:
+            type: string
+            format: uuid
+          examples:
+            invalid:
+              value: 123
+`
+
+func TestValidateAllExamples(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(examplesSpec))
+	require.NoError(t, err)
+	report := swagger.ValidateAllExamples()
+	require.False(t, report.Passed())
+
+	var pointers []string
+	for _, result := range report.Results {
+		pointers = append(pointers, result.Pointer)
+	}
+	require.Equal(t, []string{
+		"#/paths/~1users~1{id}/parameters/0/example",
+		"#/paths/~1users~1{id}/get/responses/200/headers/X-Rate-Limit/example",
+		"#/paths/~1users~1{id}/get/responses/200/content/application~1json/examples/alice",
+		"#/paths/~1users~1{id}/get/responses/200/content/application~1json/examples/bob",
+		"#/components/responses/Error/headers/X-Request-ID/examples/invalid",
+	}, pointers)
+
+	var failed []string
+	for _, result := range report.Failed() {
+		failed = append(failed, result.Pointer)
+	}
+	require.Equal(t, []string{pointers[0], pointers[3], pointers[4]}, failed)
+	require.Equal(t, "#/components/examples/Bob", report.Results[3].Ref)
+	require.Equal(t, swagger.Components.Schemas["User"].Value, report.Results[3].Schema)
+}
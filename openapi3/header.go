@@ -12,6 +12,9 @@ type Header struct {
 
 	// Optional schema
 	Schema *SchemaRef `json:"schema,omitempty"`
+
+	Example  interface{}            `json:"example,omitempty"`
+	Examples map[string]*ExampleRef `json:"examples,omitempty"`
 }
 
 func (value *Header) Validate(c context.Context) error {
@@ -2,6 +2,12 @@ package openapi3
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/getkin/kin-openapi/jsoninfo"
 )
@@ -9,11 +15,14 @@ import (
 // Link is specified by OpenAPI/Swagger standard version 3.0.
 type Link struct {
 	ExtensionProps
-	Description string                 `json:"description,omitempty"`
-	Href        string                 `json:"href,omitempty"`
-	OperationID string                 `json:"operationId,omitempty"`
-	Parameters  map[string]interface{} `json:"parameters,omitempty"`
-	Headers     map[string]*Schema     `json:"headers,omitempty"`
+	Description  string                 `json:"description,omitempty"`
+	Href         string                 `json:"href,omitempty"`
+	OperationRef string                 `json:"operationRef,omitempty"`
+	OperationID  string                 `json:"operationId,omitempty"`
+	Parameters   map[string]interface{} `json:"parameters,omitempty"`
+	RequestBody  interface{}            `json:"requestBody,omitempty"`
+	Headers      map[string]*Schema     `json:"headers,omitempty"`
+	Server       *Server                `json:"server,omitempty"`
 }
 
 func (value *Link) MarshalJSON() ([]byte, error) {
@@ -25,5 +34,231 @@ func (value *Link) UnmarshalJSON(data []byte) error {
 }
 
 func (value *Link) Validate(c context.Context) error {
+	if value.OperationID != "" && value.OperationRef != "" {
+		return errors.New("Link can't have both operationId and operationRef")
+	}
+	for k, v := range value.Parameters {
+		if err := validateLinkExpressions(v); err != nil {
+			return fmt.Errorf("Parameter '%s' is invalid: %v", k, err)
+		}
+	}
+	if err := validateLinkExpressions(value.RequestBody); err != nil {
+		return fmt.Errorf("Request body is invalid: %v", err)
+	}
 	return nil
 }
+
+// IsLinkExpression returns true if a value of a parameter or the request body of a link is a runtime expression.
+// A string is an expression if it starts with "$", like "$request.path.id",
+// or if the whole string is an expression in braces, like "{$request.path.id}".
+// Other values are constants.
+func IsLinkExpression(value interface{}) bool {
+	s, ok := value.(string)
+	if !ok {
+		return false
+	}
+	if strings.HasPrefix(s, "$") {
+		return true
+	}
+	return strings.HasPrefix(s, "{$") && strings.IndexByte(s, '}') == len(s)-1
+}
+
+// validateLinkExpressions checks syntax of runtime expressions in a value of a link.
+func validateLinkExpressions(value interface{}) error {
+	if !IsLinkExpression(value) {
+		return nil
+	}
+	_, err := ParseRuntimeExpressionTemplate(value.(string))
+	return err
+}
+
+// linkExpressions returns runtime expressions in a value of a link.
+func linkExpressions(value interface{}) []*RuntimeExpression {
+	if !IsLinkExpression(value) {
+		return nil
+	}
+	expressions, _ := ParseRuntimeExpressionTemplate(value.(string))
+	return expressions
+}
+
+// validateLinks checks that links of responses refer to existing operations,
+// parameters, headers, and fields of bodies.
+func (swagger *Swagger) validateLinks() error {
+	paths := make([]string, 0, len(swagger.Paths))
+	for path := range swagger.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		pathItem := swagger.Paths[path]
+		for method, operation := range pathItem.Operations() {
+			for status, responseRef := range operation.Responses {
+				response := responseRef.Value
+				if response == nil {
+					continue
+				}
+				for name, linkRef := range response.Links {
+					link := linkRef.Value
+					if link == nil {
+						continue
+					}
+					if err := swagger.validateLink(pathItem, operation, response, link); err != nil {
+						return fmt.Errorf("Link '%s' of response '%s' of operation %s %s is invalid: %v", name, status, method, path, err)
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (swagger *Swagger) validateLink(pathItem *PathItem, operation *Operation, response *Response, link *Link) error {
+	targetPathItem, target, err := swagger.linkTarget(link)
+	if err != nil {
+		return err
+	}
+	for key, value := range link.Parameters {
+		if target != nil && targetPathItem.FindLinkParameter(target, key) == nil {
+			return fmt.Errorf("Target operation doesn't have parameter '%s'", key)
+		}
+		for _, expression := range linkExpressions(value) {
+			if err := validateLinkSource(pathItem, operation, response, expression); err != nil {
+				return err
+			}
+		}
+	}
+	if link.RequestBody != nil && target != nil && target.RequestBody == nil {
+		return errors.New("Target operation doesn't have a request body")
+	}
+	for _, expression := range linkExpressions(link.RequestBody) {
+		if err := validateLinkSource(pathItem, operation, response, expression); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// linkTarget returns the operation that the link refers to.
+// The function returns nil if the link refers to an operation in another document.
+func (swagger *Swagger) linkTarget(link *Link) (*PathItem, *Operation, error) {
+	if id := link.OperationID; id != "" {
+		if location := swagger.OperationByID(id); location != nil {
+			return swagger.Paths[location.Path], location.Operation, nil
+		}
+		return nil, nil, fmt.Errorf("Operation '%s' doesn't exist", id)
+	}
+	ref := link.OperationRef
+	if !strings.HasPrefix(ref, "#/paths/") {
+		return nil, nil, nil
+	}
+	i := strings.LastIndexByte(ref, '/')
+	path := strings.Replace(strings.Replace(ref[len("#/paths/"):i], "~1", "/", -1), "~0", "~", -1)
+	if unescaped, err := url.PathUnescape(path); err == nil {
+		path = unescaped
+	}
+	if pathItem := swagger.Paths[path]; pathItem != nil {
+		if operation := pathItem.GetOperation(strings.ToUpper(ref[i+1:])); operation != nil {
+			return pathItem, operation, nil
+		}
+	}
+	return nil, nil, fmt.Errorf("Operation '%s' doesn't exist", ref)
+}
+
+// FindLinkParameter returns the parameter of the operation of the path item with the name of a key
+// of Link.Parameters, which may be qualified by the location like "path.id".
+// Names of headers are case-insensitive. The path item may be nil.
+func (pathItem *PathItem) FindLinkParameter(operation *Operation, key string) *Parameter {
+	in, name := "", key
+	if i := strings.IndexByte(key, '.'); i >= 0 {
+		switch key[:i] {
+		case ParameterInPath, ParameterInQuery, ParameterInHeader, ParameterInCookie:
+			in, name = key[:i], key[i+1:]
+		}
+	}
+	for _, parameterRef := range pathItem.OperationParameters(operation) {
+		parameter := parameterRef.Value
+		if in != "" && parameter.In != in {
+			continue
+		}
+		if parameter.Name == name || (parameter.In == ParameterInHeader && strings.EqualFold(parameter.Name, name)) {
+			return parameter
+		}
+	}
+	return nil
+}
+
+// validateLinkSource checks that the request or response of the operation has what the expression refers to.
+func validateLinkSource(pathItem *PathItem, operation *Operation, response *Response, expression *RuntimeExpression) error {
+	switch expression.Kind {
+	case RuntimeExpressionRequest:
+		if expression.Source == RuntimeExpressionSourceBody {
+			if operation.RequestBody == nil || operation.RequestBody.Value == nil {
+				return fmt.Errorf("Expression '%s' refers to a missing request body", expression)
+			}
+			return validateLinkPointer(operation.RequestBody.Value.Content, expression)
+		}
+		if pathItem.FindLinkParameter(operation, expression.Source+"."+expression.Name) == nil {
+			return fmt.Errorf("Expression '%s' refers to a missing parameter", expression)
+		}
+	case RuntimeExpressionResponse:
+		switch expression.Source {
+		case RuntimeExpressionSourceBody:
+			if len(response.Content) == 0 {
+				return fmt.Errorf("Expression '%s' refers to a missing response body", expression)
+			}
+			return validateLinkPointer(response.Content, expression)
+		case RuntimeExpressionSourceHeader:
+			for name := range response.Headers {
+				if strings.EqualFold(name, expression.Name) {
+					return nil
+				}
+			}
+			return fmt.Errorf("Expression '%s' refers to a missing response header", expression)
+		default:
+			return fmt.Errorf("Expression '%s' refers to a response %s, which doesn't exist", expression, expression.Source)
+		}
+	}
+	return nil
+}
+
+// validateLinkPointer checks that schemas of the content have the property that the expression points to.
+// Properties of schemas that don't declare their properties aren't checked.
+func validateLinkPointer(content Content, expression *RuntimeExpression) error {
+	tokens := expression.PointerTokens()
+	if len(tokens) == 0 {
+		return nil
+	}
+	for _, mediaType := range content {
+		if mediaType == nil || mediaType.Schema == nil {
+			return nil
+		}
+		if schemaHasPointer(mediaType.Schema.Value, tokens) {
+			return nil
+		}
+	}
+	return fmt.Errorf("Expression '%s' refers to a missing field", expression)
+}
+
+func schemaHasPointer(schema *Schema, tokens []string) bool {
+	for _, token := range tokens {
+		if schema == nil || len(schema.AllOf) > 0 || len(schema.AnyOf) > 0 || len(schema.OneOf) > 0 {
+			return true
+		}
+		if items := schema.Items; items != nil {
+			if _, err := strconv.ParseUint(token, 10, 64); err != nil {
+				return false
+			}
+			schema = items.Value
+			continue
+		}
+		if len(schema.Properties) == 0 {
+			return true
+		}
+		property := schema.Properties[token]
+		if property == nil {
+			return false
+		}
+		schema = property.Value
+	}
+	return true
+}
@@ -0,0 +1,144 @@
+package openapi3_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRuntimeExpression(t *testing.T) {
+	for expression, expected := range map[string]openapi3.RuntimeExpression{
+		"$url":                         {Kind: "$url"},
+		"$statusCode":                  {Kind: "$statusCode"},
+		"$request.path.id":             {Kind: "$request", Source: "path", Name: "id"},
+		"$request.header.X-Request-ID": {Kind: "$request", Source: "header", Name: "X-Request-ID"},
+		"$request.body":                {Kind: "$request", Source: "body"},
+		"$response.body#/a~1b/0":       {Kind: "$response", Source: "body", Pointer: "/a~1b/0"},
+	} {
+		actual, err := openapi3.ParseRuntimeExpression(expression)
+		require.NoError(t, err, expression)
+		require.Equal(t, expected, *actual, expression)
+		require.Equal(t, expression, actual.String())
+	}
+	expression, err := openapi3.ParseRuntimeExpression("$response.body#/a~1b/0")
+	require.NoError(t, err)
+	require.Equal(t, []string{"a/b", "0"}, expression.PointerTokens())
+
+	for _, expression := range []string{"$uri", "$request", "$request.cookie.x", "$request.path.", "$response.body/a", "$request.body#a"} {
+		_, err := openapi3.ParseRuntimeExpression(expression)
+		require.Error(t, err, expression)
+	}
+
+	expanded, err := openapi3.ExpandRuntimeExpressionTemplate("{$request.body#/url}/events?id={$request.path.id}", func(expression *openapi3.RuntimeExpression) (string, error) {
+		return strings.ToUpper(expression.Source), nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, "BODY/events?id=PATH", expanded)
+	_, err = openapi3.ExpandRuntimeExpressionTemplate("{$request.body#/url", nil)
+	require.Error(t, err)
+}
+
+const linksSpec = `
+openapi: 3.0.0
+info:
+  title: Users
+  version: "1.0"
+paths:
+  /users:
+    post:
+      operationId: createUser
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                name:
+                  type: string
+      responses:
+        "201":
+          description: created
+          headers:
+            Location:
+              schema:
+                type: string
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  id:
+                    type: string
+          links:
+            GetUser:
+              operationId: getUser
+              parameters:
+                id: $response.body#/id
+            GetUserByRef:
+              operationRef: "#/paths/~1users~1{id}/get"
+              parameters:
+                path.id: "{$response.header.Location}"
+  /users/{id}:
+    get:
+      operationId: getUser
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        "200":
+          description: user
+`
+
+func TestLinksValidation(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(linksSpec))
+	require.NoError(t, err)
+	err = swagger.Validate(context.Background())
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		old         string
+		replacement string
+	}{
+		{"unknown operationId", "operationId: getUser\n              parameters", "operationId: deleteUser\n              parameters"},
+		{"unknown operationRef", "~1users~1{id}/get", "~1users~1{id}/put"},
+		{"unknown target parameter", "                id: $response.body", "                userId: $response.body"},
+		{"unknown response field", "$response.body#/id", "$response.body#/uid"},
+		{"unknown response header", "$response.header.Location", "$response.header.ETag"},
+		{"unknown request parameter", "$response.body#/id", "$request.query.id"},
+		{"invalid expression", "$response.body#/id", "$response.cookie.id"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			spec := strings.Replace(linksSpec, test.old, test.replacement, 1)
+			require.NotEqual(t, linksSpec, spec)
+			swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(spec))
+			require.NoError(t, err)
+			err = swagger.Validate(context.Background())
+			require.Error(t, err)
+		})
+	}
+
+	// Values that aren't expressions are constants.
+	for _, constant := range []string{"{literal}", "users", "{$response.header.Location}/items"} {
+		spec := strings.Replace(linksSpec, `"{$response.header.Location}"`, `"`+constant+`"`, 1)
+		swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(spec))
+		require.NoError(t, err)
+		require.NoError(t, swagger.Validate(context.Background()), constant)
+	}
+}
+
+func TestIsLinkExpression(t *testing.T) {
+	require.True(t, openapi3.IsLinkExpression("$request.path.id"))
+	require.True(t, openapi3.IsLinkExpression("{$request.path.id}"))
+	require.False(t, openapi3.IsLinkExpression("{literal}"))
+	require.False(t, openapi3.IsLinkExpression("{$request.path.id}/items"))
+	require.False(t, openapi3.IsLinkExpression("id"))
+	require.False(t, openapi3.IsLinkExpression(42))
+}
@@ -0,0 +1,234 @@
+package openapi3
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+)
+
+const (
+	OperationEdgeLink     = "link"
+	OperationEdgeCallback = "callback"
+)
+
+// OperationGraph is a graph of operations connected by links and callbacks.
+type OperationGraph struct {
+	Nodes []*OperationNode
+	Edges []*OperationEdge
+}
+
+// OperationNode is an operation of a path or of a callback.
+type OperationNode struct {
+	Method    string
+	Path      string
+	Operation *Operation
+
+	// Callback is the name of the callback that the operation belongs to,
+	// and Parent is the operation that declares the callback.
+	Callback string
+	Parent   *OperationNode
+}
+
+func (node *OperationNode) String() string {
+	s := node.Method + " " + node.Path
+	if node.Callback != "" {
+		s = fmt.Sprintf("%s (callback '%s' of %s)", s, node.Callback, node.Parent)
+	}
+	return s
+}
+
+// OperationEdge connects an operation to the operation that a link or a callback refers to.
+type OperationEdge struct {
+	// Kind is either "link" or "callback".
+	Kind string
+	From *OperationNode
+	To   *OperationNode
+
+	// Name is the name of the link or the callback.
+	Name string
+
+	// Status and Link are defined for links.
+	Status string
+	Link   *Link
+}
+
+// NewOperationGraph returns the graph of operations of the document.
+//
+// Links to operations in other documents are omitted.
+func NewOperationGraph(swagger *Swagger) (*OperationGraph, error) {
+	graph := &OperationGraph{}
+	nodes := make(map[*Operation]*OperationNode)
+	paths := make([]string, 0, len(swagger.Paths))
+	for path := range swagger.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		graph.addNodes(nodes, swagger.Paths[path], path, "", nil)
+	}
+
+	// Nodes of callbacks are appended while iterating.
+	for i := 0; i < len(graph.Nodes); i++ {
+		node := graph.Nodes[i]
+		operation := node.Operation
+		statuses := make([]string, 0, len(operation.Responses))
+		for status := range operation.Responses {
+			statuses = append(statuses, status)
+		}
+		sort.Strings(statuses)
+		for _, status := range statuses {
+			response := operation.Responses[status].Value
+			if response == nil {
+				continue
+			}
+			for _, name := range sortedLinkNames(response.Links) {
+				link := response.Links[name].Value
+				if link == nil {
+					continue
+				}
+				_, target, err := swagger.linkTarget(link)
+				if err != nil {
+					return nil, fmt.Errorf("Link '%s' of response '%s' of operation %s is invalid: %v", name, status, node, err)
+				}
+				if target == nil {
+					continue
+				}
+				graph.Edges = append(graph.Edges, &OperationEdge{
+					Kind:   OperationEdgeLink,
+					From:   node,
+					To:     nodes[target],
+					Name:   name,
+					Status: status,
+					Link:   link,
+				})
+			}
+		}
+		callbackNames := make([]string, 0, len(operation.Callbacks))
+		for name := range operation.Callbacks {
+			callbackNames = append(callbackNames, name)
+		}
+		sort.Strings(callbackNames)
+		for _, name := range callbackNames {
+			callback := operation.Callbacks[name].Value
+			if callback == nil {
+				continue
+			}
+			expressions := make([]string, 0, len(*callback))
+			for expression := range *callback {
+				expressions = append(expressions, expression)
+			}
+			sort.Strings(expressions)
+			for _, expression := range expressions {
+				for _, callbackNode := range graph.addNodes(nodes, (*callback)[expression], expression, name, node) {
+					graph.Edges = append(graph.Edges, &OperationEdge{
+						Kind: OperationEdgeCallback,
+						From: node,
+						To:   callbackNode,
+						Name: name,
+					})
+				}
+			}
+		}
+	}
+	return graph, nil
+}
+
+func (graph *OperationGraph) addNodes(nodes map[*Operation]*OperationNode, pathItem *PathItem, path string, callback string, parent *OperationNode) []*OperationNode {
+	if pathItem == nil {
+		return nil
+	}
+	operations := pathItem.Operations()
+	methods := make([]string, 0, len(operations))
+	for method := range operations {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+	added := make([]*OperationNode, 0, len(methods))
+	for _, method := range methods {
+		operation := operations[method]
+		if nodes[operation] != nil {
+			// A callback may be a reference to a component that is used in many operations.
+			added = append(added, nodes[operation])
+			continue
+		}
+		node := &OperationNode{
+			Method:    method,
+			Path:      path,
+			Operation: operation,
+			Callback:  callback,
+			Parent:    parent,
+		}
+		nodes[operation] = node
+		graph.Nodes = append(graph.Nodes, node)
+		added = append(added, node)
+	}
+	return added
+}
+
+func sortedLinkNames(links map[string]*LinkRef) []string {
+	names := make([]string, 0, len(links))
+	for name := range links {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Node returns the node of the operation of a path, or nil if there is no such operation.
+func (graph *OperationGraph) Node(method string, path string) *OperationNode {
+	for _, node := range graph.Nodes {
+		if node.Method == method && node.Path == path && node.Callback == "" {
+			return node
+		}
+	}
+	return nil
+}
+
+// NodeByOperationID returns the node of the operation with the ID, or nil if there is no such operation.
+func (graph *OperationGraph) NodeByOperationID(id string) *OperationNode {
+	for _, node := range graph.Nodes {
+		if node.Operation.OperationID == id {
+			return node
+		}
+	}
+	return nil
+}
+
+// Next returns edges that start at the node.
+func (graph *OperationGraph) Next(node *OperationNode) []*OperationEdge {
+	var edges []*OperationEdge
+	for _, edge := range graph.Edges {
+		if edge.From == node {
+			edges = append(edges, edge)
+		}
+	}
+	return edges
+}
+
+// WriteDOT writes the graph in the DOT language of Graphviz.
+// Links are solid edges and callbacks are dashed edges.
+func (graph *OperationGraph) WriteDOT(w io.Writer) error {
+	buf := bufio.NewWriter(w)
+	ids := make(map[*OperationNode]string, len(graph.Nodes))
+	buf.WriteString("digraph operations {\n")
+	for i, node := range graph.Nodes {
+		id := fmt.Sprintf("op%d", i)
+		ids[node] = id
+		label := node.Method + " " + node.Path
+		if operationID := node.Operation.OperationID; operationID != "" {
+			label = operationID + "\n" + label
+		}
+		fmt.Fprintf(buf, "  %s [label=%q];\n", id, label)
+	}
+	for _, edge := range graph.Edges {
+		switch edge.Kind {
+		case OperationEdgeCallback:
+			fmt.Fprintf(buf, "  %s -> %s [label=%q, style=dashed];\n", ids[edge.From], ids[edge.To], edge.Name)
+		default:
+			fmt.Fprintf(buf, "  %s -> %s [label=%q];\n", ids[edge.From], ids[edge.To], edge.Status+" "+edge.Name)
+		}
+	}
+	buf.WriteString("}\n")
+	return buf.Flush()
+}
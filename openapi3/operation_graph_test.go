@@ -0,0 +1,87 @@
+package openapi3_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+)
+
+const operationGraphSpec = `
+openapi: 3.0.0
+info:
+  title: Orders
+  version: "1.0"
+paths:
+  /orders:
+    post:
+      operationId: createOrder
+      callbacks:
+        onShipped:
+          "{$request.body#/callbackUrl}":
+            post:
+              responses:
+                "200":
+                  description: received
+      responses:
+        "201":
+          description: created
+          links:
+            GetOrder:
+              operationId: getOrder
+            CancelOrder:
+              operationRef: "#/paths/~1orders~1{id}/delete"
+  /orders/{id}:
+    parameters:
+      - name: id
+        in: path
+        required: true
+        schema:
+          type: string
+    get:
+      operationId: getOrder
+      responses:
+        "200":
+          description: order
+    delete:
+      responses:
+        "204":
+          description: cancelled
+`
+
+func TestOperationGraph(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(operationGraphSpec))
+	require.NoError(t, err)
+	graph, err := openapi3.NewOperationGraph(swagger)
+	require.NoError(t, err)
+	require.Len(t, graph.Nodes, 4)
+	require.Len(t, graph.Edges, 3)
+
+	create := graph.NodeByOperationID("createOrder")
+	require.NotNil(t, create)
+	edges := graph.Next(create)
+	require.Len(t, edges, 3)
+	require.Equal(t, openapi3.OperationEdgeLink, edges[0].Kind)
+	require.Equal(t, "CancelOrder", edges[0].Name)
+	require.Equal(t, graph.Node("DELETE", "/orders/{id}"), edges[0].To)
+	require.Equal(t, "GetOrder", edges[1].Name)
+	require.Equal(t, graph.Node("GET", "/orders/{id}"), edges[1].To)
+	require.Equal(t, openapi3.OperationEdgeCallback, edges[2].Kind)
+	require.Equal(t, "onShipped", edges[2].To.Callback)
+	require.Equal(t, create, edges[2].To.Parent)
+	require.Empty(t, graph.Next(edges[1].To))
+
+	var buf bytes.Buffer
+	require.NoError(t, graph.WriteDOT(&buf))
+	require.Equal(t, `digraph operations {
+  op0 [label="createOrder\nPOST /orders"];
+  op1 [label="DELETE /orders/{id}"];
+  op2 [label="getOrder\nGET /orders/{id}"];
+  op3 [label="POST {$request.body#/callbackUrl}"];
+  op0 -> op1 [label="201 CancelOrder"];
+  op0 -> op2 [label="201 GetOrder"];
+  op0 -> op3 [label="onShipped", style=dashed];
+}
+`, buf.String())
+}
@@ -0,0 +1,99 @@
+package openapi3
+
+import (
+	"sort"
+)
+
+// OperationLocation is an operation with its method and path.
+type OperationLocation struct {
+	Method    string
+	Path      string
+	Operation *Operation
+}
+
+// operationIndex indexes operations of paths by ID, tag, and pointer.
+type operationIndex struct {
+	byID        map[string]*OperationLocation
+	byTag       map[string][]*OperationLocation
+	byOperation map[*Operation]*OperationLocation
+}
+
+// IndexOperations rebuilds the index of operations that OperationByID, OperationsByTag,
+// and LocateOperation use.
+// SwaggerLoader builds the index, so it only needs to be rebuilt after paths are modified.
+func (swagger *Swagger) IndexOperations() {
+	index := &operationIndex{
+		byID:        make(map[string]*OperationLocation),
+		byTag:       make(map[string][]*OperationLocation),
+		byOperation: make(map[*Operation]*OperationLocation),
+	}
+	paths := make([]string, 0, len(swagger.Paths))
+	for path := range swagger.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		pathItem := swagger.Paths[path]
+		if pathItem == nil {
+			continue
+		}
+		operations := pathItem.Operations()
+		methods := make([]string, 0, len(operations))
+		for method := range operations {
+			methods = append(methods, method)
+		}
+		sort.Strings(methods)
+		for _, method := range methods {
+			operation := operations[method]
+			location := &OperationLocation{
+				Method:    method,
+				Path:      path,
+				Operation: operation,
+			}
+			index.byOperation[operation] = location
+			if id := operation.OperationID; id != "" {
+				if _, exists := index.byID[id]; !exists {
+					index.byID[id] = location
+				}
+			}
+			for _, tag := range operation.Tags {
+				index.byTag[tag] = append(index.byTag[tag], location)
+			}
+		}
+	}
+	swagger.operationIndex = index
+}
+
+func (swagger *Swagger) operations() *operationIndex {
+	if swagger.operationIndex == nil {
+		swagger.IndexOperations()
+	}
+	return swagger.operationIndex
+}
+
+// OperationByID returns the operation with the ID, or nil if there is no such operation.
+func (swagger *Swagger) OperationByID(id string) *OperationLocation {
+	return swagger.operations().byID[id]
+}
+
+// OperationsByTag returns operations with the tag, sorted by path and method.
+func (swagger *Swagger) OperationsByTag(tag string) []*OperationLocation {
+	return swagger.operations().byTag[tag]
+}
+
+// OperationTags returns sorted tags of operations.
+func (swagger *Swagger) OperationTags() []string {
+	byTag := swagger.operations().byTag
+	tags := make([]string, 0, len(byTag))
+	for tag := range byTag {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// LocateOperation returns the method and the path of an operation of paths,
+// or nil if the operation isn't in paths.
+func (swagger *Swagger) LocateOperation(operation *Operation) *OperationLocation {
+	return swagger.operations().byOperation[operation]
+}
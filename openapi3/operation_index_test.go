@@ -0,0 +1,70 @@
+package openapi3_test
+
+import (
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+)
+
+const operationIndexSpec = `
+openapi: 3.0.0
+info:
+  title: Pets
+  version: "1.0"
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      tags: [pets]
+      responses:
+        default:
+          description: pets
+    post:
+      operationId: createPet
+      tags: [pets, admin]
+      responses:
+        default:
+          description: pet
+  /users:
+    get:
+      operationId: listUsers
+      tags: [admin]
+      responses:
+        default:
+          description: users
+`
+
+func TestOperationIndex(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(operationIndexSpec))
+	require.NoError(t, err)
+
+	location := swagger.OperationByID("createPet")
+	require.NotNil(t, location)
+	require.Equal(t, "POST", location.Method)
+	require.Equal(t, "/pets", location.Path)
+	require.Equal(t, swagger.Paths["/pets"].Post, location.Operation)
+	require.Nil(t, swagger.OperationByID("deletePet"))
+
+	require.Equal(t, []string{"admin", "pets"}, swagger.OperationTags())
+	var ids []string
+	for _, location := range swagger.OperationsByTag("admin") {
+		ids = append(ids, location.Operation.OperationID)
+	}
+	require.Equal(t, []string{"createPet", "listUsers"}, ids)
+
+	location = swagger.LocateOperation(swagger.Paths["/users"].Get)
+	require.Equal(t, "GET", location.Method)
+	require.Equal(t, "/users", location.Path)
+
+	// Adding an operation updates the index.
+	operation := &openapi3.Operation{OperationID: "deletePet", Tags: []string{"pets"}}
+	swagger.AddOperation("/pets/{id}", "DELETE", operation)
+	require.Equal(t, "/pets/{id}", swagger.OperationByID("deletePet").Path)
+	require.Len(t, swagger.OperationsByTag("pets"), 3)
+
+	// Documents that aren't loaded are indexed on demand.
+	built := &openapi3.Swagger{}
+	built.AddOperation("/health", "GET", &openapi3.Operation{OperationID: "health"})
+	require.Equal(t, "/health", built.OperationByID("health").Path)
+}
@@ -3,6 +3,7 @@ package openapi3
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/getkin/kin-openapi/jsoninfo"
 )
@@ -64,6 +65,40 @@ func (pathItem *PathItem) Operations() map[string]*Operation {
 	return operations
 }
 
+// OperationParameters returns parameters that apply to the operation of the path item:
+// parameters of the path item that the operation doesn't override, followed by parameters of the operation.
+// A parameter overrides a parameter with the same location and name, where names of headers are case-insensitive.
+func (pathItem *PathItem) OperationParameters(operation *Operation) Parameters {
+	var result Parameters
+	if pathItem != nil {
+		for _, parameterRef := range pathItem.Parameters {
+			if parameterRef == nil || parameterRef.Value == nil || overridesParameter(operation.Parameters, parameterRef.Value) {
+				continue
+			}
+			result = append(result, parameterRef)
+		}
+	}
+	for _, parameterRef := range operation.Parameters {
+		if parameterRef != nil && parameterRef.Value != nil {
+			result = append(result, parameterRef)
+		}
+	}
+	return result
+}
+
+func overridesParameter(parameters Parameters, parameter *Parameter) bool {
+	for _, parameterRef := range parameters {
+		other := parameterRef.Value
+		if other == nil || other.In != parameter.In {
+			continue
+		}
+		if other.Name == parameter.Name || (other.In == ParameterInHeader && strings.EqualFold(other.Name, parameter.Name)) {
+			return true
+		}
+	}
+	return false
+}
+
 func (pathItem *PathItem) GetOperation(method string) *Operation {
 	switch method {
 	case "CONNECT":
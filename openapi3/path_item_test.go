@@ -0,0 +1,27 @@
+package openapi3_test
+
+import (
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPathItemOperationParameters(t *testing.T) {
+	id := &openapi3.ParameterRef{Value: openapi3.NewPathParameter("id").WithSchema(openapi3.NewStringSchema())}
+	limit := &openapi3.ParameterRef{Value: openapi3.NewQueryParameter("limit").WithSchema(openapi3.NewIntegerSchema())}
+	traceID := &openapi3.ParameterRef{Value: openapi3.NewHeaderParameter("X-Trace-ID").WithSchema(openapi3.NewStringSchema())}
+	operationID := &openapi3.ParameterRef{Value: openapi3.NewPathParameter("id").WithSchema(openapi3.NewIntegerSchema())}
+	operationTraceID := &openapi3.ParameterRef{Value: openapi3.NewHeaderParameter("x-trace-id")}
+	queryID := &openapi3.ParameterRef{Value: openapi3.NewQueryParameter("id")}
+
+	pathItem := &openapi3.PathItem{Parameters: openapi3.Parameters{id, limit, traceID}}
+	operation := openapi3.NewOperation()
+	operation.Parameters = openapi3.Parameters{operationID, operationTraceID, queryID}
+	require.Equal(t, openapi3.Parameters{limit, operationID, operationTraceID, queryID}, pathItem.OperationParameters(operation))
+
+	require.Equal(t, openapi3.Parameters{id, limit, traceID}, pathItem.OperationParameters(openapi3.NewOperation()))
+
+	var noPathItem *openapi3.PathItem
+	require.Equal(t, operation.Parameters, noPathItem.OperationParameters(operation))
+}
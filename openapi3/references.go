@@ -0,0 +1,146 @@
+package openapi3
+
+import (
+	"strings"
+)
+
+// References returns JSON pointers of locations that refer to a component like "#/components/schemas/User",
+// directly or through other components.
+// A document can't drop a component while this list is not empty.
+//
+// Locations are pointers of objects with the "$ref", like "#/paths/~1users/get/responses/200/content/application~1json/schema".
+func (swagger *Swagger) References(ref string) []string {
+	refs := swagger.refLocations()
+	targets := map[string]bool{ref: true}
+	found := make(map[string]bool)
+	var result []string
+	for changed := true; changed; {
+		changed = false
+		for _, location := range refs {
+			if found[location.pointer] || !targets[location.ref] {
+				continue
+			}
+			found[location.pointer] = true
+			result = append(result, location.pointer)
+			// Locations that refer to the component that contains this location
+			// refer to the component transitively.
+			if component := componentOfPointer(location.pointer); component != "" && !targets[component] {
+				targets[component] = true
+				changed = true
+			}
+		}
+	}
+	return result
+}
+
+// componentOfPointer returns the pointer of the component that contains the location,
+// or an empty string if the location isn't in components.
+func componentOfPointer(pointer string) string {
+	const prefix = "#/components/"
+	if !strings.HasPrefix(pointer, prefix) {
+		return ""
+	}
+	parts := strings.SplitN(pointer[len(prefix):], "/", 3)
+	if len(parts) < 2 {
+		return ""
+	}
+	return prefix + parts[0] + "/" + parts[1]
+}
+
+type refLocation struct {
+	pointer string
+	ref     string
+}
+
+// refLocations returns every "$ref" of the document in a stable order.
+func (swagger *Swagger) refLocations() []refLocation {
+	v := newDocumentVisitor()
+	v.visit(swagger)
+	return v.locations
+}
+
+// documentVisitor collects locations of references, and calls visitSchema with inline schemas.
+// Values of references aren't visited, because they're visited as components.
+type documentVisitor struct {
+	locations   []refLocation
+	visited     map[*Schema]bool
+	visitSchema func(pointer string, schema *Schema)
+
+	// visitSchemaRef is called with inline schemas before they are visited, and may replace their values.
+	visitSchemaRef func(pointer string, ref *SchemaRef)
+}
+
+func newDocumentVisitor() *documentVisitor {
+	return &documentVisitor{visited: make(map[*Schema]bool)}
+}
+
+// visit visits paths and components of the document.
+func (v *documentVisitor) visit(swagger *Swagger) {
+	Walk(swagger, &Visitor{
+		Parameter: func(pointer string, ref *ParameterRef) error {
+			return v.refError(pointer, ref.Ref)
+		},
+		RequestBody: func(pointer string, ref *RequestBodyRef) error {
+			return v.refError(pointer, ref.Ref)
+		},
+		Response: func(pointer string, ref *ResponseRef) error {
+			return v.refError(pointer, ref.Ref)
+		},
+		Header: func(pointer string, ref *HeaderRef) error {
+			return v.refError(pointer, ref.Ref)
+		},
+		Example: func(pointer string, ref *ExampleRef) error {
+			return v.refError(pointer, ref.Ref)
+		},
+		Link: func(pointer string, ref *LinkRef) error {
+			return v.refError(pointer, ref.Ref)
+		},
+		Callback: func(pointer string, ref *CallbackRef) error {
+			return v.refError(pointer, ref.Ref)
+		},
+		SecurityScheme: func(pointer string, ref *SecuritySchemeRef) error {
+			return v.refError(pointer, ref.Ref)
+		},
+		Schema: v.schema,
+	})
+}
+
+// ref records the reference, and returns true if the value of the reference is inline, and needs to be visited.
+func (v *documentVisitor) ref(pointer string, ref string) bool {
+	if ref == "" {
+		return true
+	}
+	v.locations = append(v.locations, refLocation{pointer: pointer, ref: ref})
+	return false
+}
+
+// refError records the reference like ref, and returns SkipChildren if the value isn't inline.
+func (v *documentVisitor) refError(pointer string, ref string) error {
+	if !v.ref(pointer, ref) {
+		return SkipChildren
+	}
+	return nil
+}
+
+func (v *documentVisitor) schema(pointer string, ref *SchemaRef) error {
+	if !v.ref(pointer, ref.Ref) || ref.Value == nil {
+		return SkipChildren
+	}
+	if v.visitSchemaRef != nil {
+		v.visitSchemaRef(pointer, ref)
+	}
+	schema := ref.Value
+	if v.visited[schema] {
+		return SkipChildren
+	}
+	v.visited[schema] = true
+	if v.visitSchema != nil {
+		v.visitSchema(pointer, schema)
+	}
+	if discriminator := schema.Discriminator; discriminator != nil {
+		for _, value := range sortedKeys(discriminator.Mapping) {
+			v.ref(pointer+"/discriminator/mapping/"+escapeJSONPointer(value), discriminator.Mapping[value])
+		}
+	}
+	return nil
+}
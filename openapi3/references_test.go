@@ -0,0 +1,88 @@
+package openapi3_test
+
+import (
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+)
+
+const referencesSpec = `
+openapi: 3.0.0
+info:
+  title: Users
+  version: "1.0"
+paths:
+  /users:
+    get:
+      responses:
+        "200":
+          $ref: "#/components/responses/Users"
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: "#/components/schemas/User"
+      responses:
+        default:
+          description: created
+  /groups:
+    get:
+      responses:
+        "200":
+          description: groups
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: "#/components/schemas/Group"
+components:
+  schemas:
+    User:
+      type: object
+      properties:
+        name:
+          type: string
+        friends:
+          type: array
+          items:
+            $ref: "#/components/schemas/User"
+    Group:
+      type: object
+      properties:
+        owner:
+          $ref: "#/components/schemas/User"
+    Unused:
+      type: string
+  responses:
+    Users:
+      description: users
+      content:
+        application/json:
+          schema:
+            type: array
+            items:
+              $ref: "#/components/schemas/User"
+`
+
+func TestReferences(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(referencesSpec))
+	require.NoError(t, err)
+
+	require.Equal(t, []string{
+		"#/paths/~1users/post/requestBody/content/application~1json/schema",
+		"#/components/schemas/Group/properties/owner",
+		"#/components/schemas/User/properties/friends/items",
+		"#/components/responses/Users/content/application~1json/schema/items",
+		// Locations that refer to components that refer to the schema
+		"#/paths/~1groups/get/responses/200/content/application~1json/schema/items",
+		"#/paths/~1users/get/responses/200",
+	}, swagger.References("#/components/schemas/User"))
+
+	require.Equal(t, []string{
+		"#/paths/~1groups/get/responses/200/content/application~1json/schema/items",
+	}, swagger.References("#/components/schemas/Group"))
+	require.Empty(t, swagger.References("#/components/schemas/Unused"))
+}
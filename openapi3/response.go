@@ -2,6 +2,7 @@ package openapi3
 
 import (
 	"context"
+	"fmt"
 	"strconv"
 
 	"github.com/getkin/kin-openapi/jsoninfo"
@@ -22,6 +23,28 @@ func (responses Responses) Get(status int) *ResponseRef {
 	return responses[strconv.FormatInt(int64(status), 10)]
 }
 
+// ResolveStatus returns the response that applies to the status, and its key in the responses:
+// the response of the exact status like "404" takes precedence over the response of the range like "4XX",
+// which takes precedence over the "default" response.
+// The function returns nil and an empty key if no response applies.
+func (responses Responses) ResolveStatus(status int) (*ResponseRef, string) {
+	key := strconv.Itoa(status)
+	if responseRef := responses[key]; responseRef != nil {
+		return responseRef, key
+	}
+	if 100 <= status && status < 600 {
+		for _, key := range []string{key[:1] + "XX", key[:1] + "xx"} {
+			if responseRef := responses[key]; responseRef != nil {
+				return responseRef, key
+			}
+		}
+	}
+	if responseRef := responses.Default(); responseRef != nil {
+		return responseRef, "default"
+	}
+	return nil, ""
+}
+
 func (responses Responses) Validate(c context.Context) error {
 	for _, v := range responses {
 		if err := v.Validate(c); err != nil {
@@ -78,5 +101,10 @@ func (response *Response) Validate(c context.Context) error {
 			return err
 		}
 	}
+	for k, v := range response.Links {
+		if err := v.Validate(c); err != nil {
+			return fmt.Errorf("Link '%s' is invalid: %v", k, err)
+		}
+	}
 	return nil
 }
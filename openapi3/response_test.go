@@ -0,0 +1,40 @@
+package openapi3_test
+
+import (
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResponsesResolveStatus(t *testing.T) {
+	ok := &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("ok")}
+	success := &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("success")}
+	clientError := &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("client error")}
+	unexpected := &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("unexpected")}
+	responses := openapi3.Responses{
+		"200":     ok,
+		"2XX":     success,
+		"4xx":     clientError,
+		"default": unexpected,
+	}
+	for _, test := range []struct {
+		status   int
+		response *openapi3.ResponseRef
+		key      string
+	}{
+		{200, ok, "200"},
+		{201, success, "2XX"},
+		{404, clientError, "4xx"},
+		{500, unexpected, "default"},
+		{99, unexpected, "default"},
+	} {
+		response, key := responses.ResolveStatus(test.status)
+		require.Equal(t, test.response, response, test.status)
+		require.Equal(t, test.key, key, test.status)
+	}
+
+	response, key := openapi3.Responses{"2XX": success}.ResolveStatus(404)
+	require.Nil(t, response)
+	require.Empty(t, key)
+}
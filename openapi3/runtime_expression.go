@@ -0,0 +1,158 @@
+package openapi3
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	RuntimeExpressionURL        = "$url"
+	RuntimeExpressionMethod     = "$method"
+	RuntimeExpressionStatusCode = "$statusCode"
+	RuntimeExpressionRequest    = "$request"
+	RuntimeExpressionResponse   = "$response"
+)
+
+const (
+	RuntimeExpressionSourceHeader = "header"
+	RuntimeExpressionSourceQuery  = "query"
+	RuntimeExpressionSourcePath   = "path"
+	RuntimeExpressionSourceBody   = "body"
+)
+
+// RuntimeExpression is an expression used by links and callbacks, such as "$request.body#/url".
+type RuntimeExpression struct {
+	// Kind is one of "$url", "$method", "$statusCode", "$request", or "$response".
+	Kind string
+
+	// Source is one of "header", "query", "path", or "body" for "$request" and "$response" expressions.
+	Source string
+
+	// Name is the name of a header, query, or path parameter.
+	Name string
+
+	// Pointer is the JSON pointer of a body reference, which is empty for the whole body.
+	Pointer string
+}
+
+// ParseRuntimeExpression parses an expression such as "$request.path.id".
+func ParseRuntimeExpression(expression string) (*RuntimeExpression, error) {
+	switch expression {
+	case RuntimeExpressionURL, RuntimeExpressionMethod, RuntimeExpressionStatusCode:
+		return &RuntimeExpression{Kind: expression}, nil
+	}
+	result := &RuntimeExpression{}
+	var source string
+	switch {
+	case strings.HasPrefix(expression, RuntimeExpressionRequest+"."):
+		result.Kind = RuntimeExpressionRequest
+		source = expression[len(RuntimeExpressionRequest)+1:]
+	case strings.HasPrefix(expression, RuntimeExpressionResponse+"."):
+		result.Kind = RuntimeExpressionResponse
+		source = expression[len(RuntimeExpressionResponse)+1:]
+	default:
+		return nil, fmt.Errorf("Invalid runtime expression '%s'", expression)
+	}
+	if source == RuntimeExpressionSourceBody || strings.HasPrefix(source, RuntimeExpressionSourceBody+"#") {
+		result.Source = RuntimeExpressionSourceBody
+		pointer := source[len(RuntimeExpressionSourceBody):]
+		if pointer != "" {
+			pointer = pointer[1:]
+			if pointer != "" && !strings.HasPrefix(pointer, "/") {
+				return nil, fmt.Errorf("Invalid JSON pointer in runtime expression '%s'", expression)
+			}
+		}
+		result.Pointer = pointer
+		return result, nil
+	}
+	i := strings.IndexByte(source, '.')
+	if i < 0 {
+		return nil, fmt.Errorf("Invalid runtime expression '%s'", expression)
+	}
+	result.Source, result.Name = source[:i], source[i+1:]
+	switch result.Source {
+	case RuntimeExpressionSourceHeader, RuntimeExpressionSourceQuery, RuntimeExpressionSourcePath:
+	default:
+		return nil, fmt.Errorf("Invalid source '%s' in runtime expression '%s'", result.Source, expression)
+	}
+	if result.Name == "" {
+		return nil, fmt.Errorf("Missing name in runtime expression '%s'", expression)
+	}
+	return result, nil
+}
+
+func (expression *RuntimeExpression) String() string {
+	switch expression.Kind {
+	case RuntimeExpressionRequest, RuntimeExpressionResponse:
+	default:
+		return expression.Kind
+	}
+	if expression.Source == RuntimeExpressionSourceBody {
+		if expression.Pointer == "" {
+			return expression.Kind + ".body"
+		}
+		return expression.Kind + ".body#" + expression.Pointer
+	}
+	return expression.Kind + "." + expression.Source + "." + expression.Name
+}
+
+// PointerTokens returns unescaped reference tokens of the JSON pointer of the expression.
+func (expression *RuntimeExpression) PointerTokens() []string {
+	if expression.Pointer == "" {
+		return nil
+	}
+	tokens := strings.Split(expression.Pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.Replace(strings.Replace(token, "~1", "/", -1), "~0", "~", -1)
+	}
+	return tokens
+}
+
+// ParseRuntimeExpressionTemplate returns expressions embedded in braces in the template,
+// such as "{$request.body#/url}/events".
+func ParseRuntimeExpressionTemplate(template string) ([]*RuntimeExpression, error) {
+	var result []*RuntimeExpression
+	_, err := ExpandRuntimeExpressionTemplate(template, func(expression *RuntimeExpression) (string, error) {
+		result = append(result, expression)
+		return "", nil
+	})
+	return result, err
+}
+
+// ExpandRuntimeExpressionTemplate replaces expressions embedded in braces in the template
+// with values returned by the function.
+// A template that is just an expression, like "$request.path.id", is an expression too.
+func ExpandRuntimeExpressionTemplate(template string, evaluate func(*RuntimeExpression) (string, error)) (string, error) {
+	if strings.HasPrefix(template, "$") {
+		expression, err := ParseRuntimeExpression(template)
+		if err != nil {
+			return "", err
+		}
+		return evaluate(expression)
+	}
+	var buf strings.Builder
+	remaining := template
+	for {
+		start := strings.IndexByte(remaining, '{')
+		if start < 0 {
+			buf.WriteString(remaining)
+			return buf.String(), nil
+		}
+		end := strings.IndexByte(remaining[start:], '}')
+		if end < 0 {
+			return "", fmt.Errorf("Missing '}' in '%s'", template)
+		}
+		end += start
+		buf.WriteString(remaining[:start])
+		expression, err := ParseRuntimeExpression(remaining[start+1 : end])
+		if err != nil {
+			return "", err
+		}
+		value, err := evaluate(expression)
+		if err != nil {
+			return "", err
+		}
+		buf.WriteString(value)
+		remaining = remaining[end+1:]
+	}
+}
@@ -7,9 +7,8 @@ import (
 	"errors"
 	"fmt"
 	"math"
-	"math/big"
-	"regexp"
 	"strconv"
+	"sync/atomic"
 	"unicode/utf16"
 
 	"github.com/getkin/kin-openapi/jsoninfo"
@@ -46,6 +45,8 @@ func Uint64Ptr(value uint64) *uint64 {
 }
 
 // Schema is specified by OpenAPI/Swagger 3.0 standard.
+//
+// Validation caches regular expressions compiled from the schema, see Compile.
 type Schema struct {
 	ExtensionProps
 
@@ -80,10 +81,9 @@ type Schema struct {
 	MultipleOf *float64 `json:"multipleOf,omitempty"`
 
 	// String
-	MinLength       uint64  `json:"minLength,omitempty"`
-	MaxLength       *uint64 `json:"maxLength,omitempty"`
-	Pattern         string  `json:"pattern,omitempty"`
-	compiledPattern *compiledPattern
+	MinLength uint64  `json:"minLength,omitempty"`
+	MaxLength *uint64 `json:"maxLength,omitempty"`
+	Pattern   string  `json:"pattern,omitempty"`
 
 	// Array
 	MinItems uint64     `json:"minItems,omitempty"`
@@ -98,8 +98,15 @@ type Schema struct {
 	AdditionalProperties *SchemaRef            `json:"-" multijson:"additionalProperties,omitempty"`
 	Discriminator        *Discriminator        `json:"discriminator,omitempty"`
 
-	PatternProperties         string `json:"patternProperties,omitempty"`
-	compiledPatternProperties *compiledPattern
+	PatternProperties string `json:"patternProperties,omitempty"`
+
+	// PropertyOrder lists names of properties in the order of their declaration.
+	// It's set when a schema is unmarshaled or built with WithProperty.
+	// Use OrderedPropertyNames to get names of all properties.
+	PropertyOrder []string `json:"-"`
+
+	compiledSchema atomic.Value // *CompiledSchema
+	regexCompiler  RegexCompiler
 }
 
 func NewSchema() *Schema {
@@ -111,7 +118,11 @@ func (schema *Schema) MarshalJSON() ([]byte, error) {
 }
 
 func (schema *Schema) UnmarshalJSON(data []byte) error {
-	return jsoninfo.UnmarshalStrictStruct(data, schema)
+	if err := jsoninfo.UnmarshalStrictStruct(data, schema); err != nil {
+		return err
+	}
+	schema.PropertyOrder = propertyOrder(data)
+	return nil
 }
 
 func (schema *Schema) NewRef() *SchemaRef {
@@ -215,11 +226,6 @@ func NewObjectSchema() *Schema {
 	}
 }
 
-type compiledPattern struct {
-	Regexp    *regexp.Regexp
-	ErrReason string
-}
-
 func (schema *Schema) WithNullable() *Schema {
 	schema.Nullable = true
 	return schema
@@ -334,6 +340,9 @@ func (schema *Schema) WithPropertyRef(name string, ref *SchemaRef) *Schema {
 		properties = make(map[string]*SchemaRef)
 		schema.Properties = properties
 	}
+	if _, ok := properties[name]; !ok {
+		schema.PropertyOrder = append(schema.PropertyOrder, name)
+	}
 	properties[name] = ref
 	return schema
 }
@@ -555,34 +564,40 @@ func (schema *Schema) validate(c context.Context, stack []*Schema) (err error) {
 }
 
 func (schema *Schema) IsMatching(value interface{}) bool {
-	return schema.visitJSON(value, true) == nil
+	return schema.visitJSON(context.Background(), value, true) == nil
 }
 
 func (schema *Schema) IsMatchingJSONBoolean(value bool) bool {
-	return schema.visitJSON(value, true) == nil
+	return schema.visitJSON(context.Background(), value, true) == nil
 }
 
 func (schema *Schema) IsMatchingJSONNumber(value float64) bool {
-	return schema.visitJSON(value, true) == nil
+	return schema.visitJSON(context.Background(), value, true) == nil
 }
 
 func (schema *Schema) IsMatchingJSONString(value string) bool {
-	return schema.visitJSON(value, true) == nil
+	return schema.visitJSON(context.Background(), value, true) == nil
 }
 
 func (schema *Schema) IsMatchingJSONArray(value []interface{}) bool {
-	return schema.visitJSON(value, true) == nil
+	return schema.visitJSON(context.Background(), value, true) == nil
 }
 
 func (schema *Schema) IsMatchingJSONObject(value map[string]interface{}) bool {
-	return schema.visitJSON(value, true) == nil
+	return schema.visitJSON(context.Background(), value, true) == nil
 }
 
 func (schema *Schema) VisitJSON(value interface{}) error {
-	return schema.visitJSON(value, false)
+	return schema.visitJSON(context.Background(), value, false)
+}
+
+// VisitJSONContext validates the value like VisitJSON,
+// but stops validation of arrays and objects with the error of the context when it's done.
+func (schema *Schema) VisitJSONContext(c context.Context, value interface{}) error {
+	return schema.visitJSON(c, value, false)
 }
 
-func (schema *Schema) visitJSON(value interface{}, fast bool) (err error) {
+func (schema *Schema) visitJSON(c context.Context, value interface{}, fast bool) (err error) {
 	switch value := value.(type) {
 	case float64:
 		if math.IsNaN(value) {
@@ -596,7 +611,7 @@ func (schema *Schema) visitJSON(value interface{}, fast bool) (err error) {
 	if schema.IsEmpty() {
 		return
 	}
-	if err = schema.visitSetOperations(value, fast); err != nil {
+	if err = schema.visitSetOperations(c, value, fast); err != nil {
 		return
 	}
 
@@ -608,11 +623,11 @@ func (schema *Schema) visitJSON(value interface{}, fast bool) (err error) {
 	case float64:
 		return schema.visitJSONNumber(value, fast)
 	case string:
-		return schema.visitJSONString(value, fast)
+		return schema.visitJSONString(c, value, fast)
 	case []interface{}:
-		return schema.visitJSONArray(value, fast)
+		return schema.visitJSONArray(c, value, fast)
 	case map[string]interface{}:
-		return schema.visitJSONObject(value, fast)
+		return schema.visitJSONObject(c, value, fast)
 	default:
 		return &SchemaError{
 			Value:       value,
@@ -623,7 +638,7 @@ func (schema *Schema) visitJSON(value interface{}, fast bool) (err error) {
 	}
 }
 
-func (schema *Schema) visitSetOperations(value interface{}, fast bool) (err error) {
+func (schema *Schema) visitSetOperations(c context.Context, value interface{}, fast bool) (err error) {
 	if enum := schema.Enum; len(enum) != 0 {
 		for _, v := range enum {
 			if value == v {
@@ -646,7 +661,7 @@ func (schema *Schema) visitSetOperations(value interface{}, fast bool) (err erro
 		if v == nil {
 			return foundUnresolvedRef(ref.Ref)
 		}
-		if err := v.visitJSON(value, true); err == nil {
+		if err := v.visitJSON(c, value, true); err == nil {
 			if fast {
 				return errSchema
 			}
@@ -665,7 +680,7 @@ func (schema *Schema) visitSetOperations(value interface{}, fast bool) (err erro
 			if v == nil {
 				return foundUnresolvedRef(item.Ref)
 			}
-			if err := v.visitJSON(value, true); err == nil {
+			if err := v.visitJSON(c, value, true); err == nil {
 				ok++
 			}
 		}
@@ -688,7 +703,7 @@ func (schema *Schema) visitSetOperations(value interface{}, fast bool) (err erro
 			if v == nil {
 				return foundUnresolvedRef(item.Ref)
 			}
-			if err := v.visitJSON(value, true); err == nil {
+			if err := v.visitJSON(c, value, true); err == nil {
 				ok = true
 				break
 			}
@@ -705,15 +720,18 @@ func (schema *Schema) visitSetOperations(value interface{}, fast bool) (err erro
 		}
 	}
 
-	for _, item := range schema.AllOf {
+	for i, item := range schema.AllOf {
 		v := item.Value
 		if v == nil {
 			return foundUnresolvedRef(item.Ref)
 		}
-		if err := v.visitJSON(value, false); err != nil {
+		if err := v.visitJSON(c, value, false); err != nil {
 			if fast {
 				return errSchema
 			}
+			if origin, ok := err.(*SchemaError); ok {
+				origin.reverseSchemaPath = append(origin.reverseSchemaPath, strconv.Itoa(i), "allOf")
+			}
 			return &SchemaError{
 				Value:       value,
 				Schema:      schema,
@@ -758,7 +776,7 @@ func (schema *Schema) VisitJSONNumber(value float64) error {
 func (schema *Schema) visitJSONNumber(value float64, fast bool) (err error) {
 	schemaType := schema.Type
 	if schemaType == "integer" {
-		if bigFloat := big.NewFloat(value); !bigFloat.IsInt() {
+		if !isInteger(value) {
 			if fast {
 				return errSchema
 			}
@@ -829,7 +847,7 @@ func (schema *Schema) visitJSONNumber(value float64, fast bool) (err error) {
 	if v := schema.MultipleOf; v != nil {
 		// "A numeric instance is valid only if division by this keyword's
 		//    value results in an integer."
-		if bigFloat := big.NewFloat(value / *v); !bigFloat.IsInt() {
+		if !isInteger(value / *v) {
 			if fast {
 				return errSchema
 			}
@@ -844,10 +862,10 @@ func (schema *Schema) visitJSONNumber(value float64, fast bool) (err error) {
 }
 
 func (schema *Schema) VisitJSONString(value string) error {
-	return schema.visitJSONString(value, false)
+	return schema.visitJSONString(context.Background(), value, false)
 }
 
-func (schema *Schema) visitJSONString(value string, fast bool) (err error) {
+func (schema *Schema) visitJSONString(c context.Context, value string, fast bool) (err error) {
 	if schemaType := schema.Type; schemaType != "" && schemaType != "string" {
 		return schema.expectedType("string", fast)
 	}
@@ -890,54 +908,33 @@ func (schema *Schema) visitJSONString(value string, fast bool) (err error) {
 	}
 
 	// "format" and "pattern"
-	cp := schema.compiledPattern
-	if cp == nil {
-		pattern := schema.Pattern
-		if v := schema.Pattern; len(v) > 0 {
-			// Pattern
-			re, err := regexp.Compile(v)
-			if err != nil {
-				return fmt.Errorf("Error while compiling regular expression '%s': %v", pattern, err)
-			}
-			cp = &compiledPattern{
-				Regexp:    re,
-				ErrReason: "JSON string doesn't match the regular expression '" + v + "'",
-			}
-			schema.compiledPattern = cp
-		} else if v := schema.Format; len(v) > 0 {
-			// No pattern, but does have a format
-			re := SchemaStringFormats[v]
-			if re != nil {
-				cp = &compiledPattern{
-					Regexp:    re,
-					ErrReason: "JSON string doesn't match the format '" + v + " (regular expression `" + re.String() + "`)'",
-				}
-				schema.compiledPattern = cp
-			}
-		}
+	compiled, err := schema.compiledContext(c)
+	if err != nil {
+		return
 	}
-	if cp != nil {
-		if !cp.Regexp.MatchString(value) {
-			field := "format"
-			if schema.Pattern != "" {
-				field = "pattern"
-			}
-			return &SchemaError{
-				Value:       value,
-				Schema:      schema,
-				SchemaField: field,
-				Reason:      cp.ErrReason,
-			}
+	if re := compiled.Pattern; re != nil && !re.MatchString(value) {
+		if fast {
+			return errSchema
+		}
+		field, reason := "format", "JSON string doesn't match the format '"+schema.Format+" (regular expression `"+re.String()+"`)'"
+		if schema.Pattern != "" {
+			field, reason = "pattern", "JSON string doesn't match the regular expression '"+schema.Pattern+"'"
+		}
+		return &SchemaError{
+			Value:       value,
+			Schema:      schema,
+			SchemaField: field,
+			Reason:      reason,
 		}
 	}
 	return
 }
 
 func (schema *Schema) VisitJSONArray(value []interface{}) error {
-	return schema.visitJSONArray(value, false)
+	return schema.visitJSONArray(context.Background(), value, false)
 }
 
-func (schema *Schema) visitJSONArray(value []interface{}, fast bool) (err error) {
+func (schema *Schema) visitJSONArray(c context.Context, value []interface{}, fast bool) (err error) {
 	if schemaType := schema.Type; schemaType != "" && schemaType != "array" {
 		return schema.expectedType("array", fast)
 	}
@@ -990,7 +987,10 @@ func (schema *Schema) visitJSONArray(value []interface{}, fast bool) (err error)
 			return foundUnresolvedRef(itemSchemaRef.Ref)
 		}
 		for i, item := range value {
-			if err := itemSchema.VisitJSON(item); err != nil {
+			if err := c.Err(); err != nil {
+				return err
+			}
+			if err := itemSchema.visitJSON(c, item, false); err != nil {
 				return markSchemaErrorIndex(err, i)
 			}
 		}
@@ -999,10 +999,10 @@ func (schema *Schema) visitJSONArray(value []interface{}, fast bool) (err error)
 }
 
 func (schema *Schema) VisitJSONObject(value map[string]interface{}) error {
-	return schema.visitJSONObject(value, false)
+	return schema.visitJSONObject(context.Background(), value, false)
 }
 
-func (schema *Schema) visitJSONObject(value map[string]interface{}, fast bool) (err error) {
+func (schema *Schema) visitJSONObject(c context.Context, value map[string]interface{}, fast bool) (err error) {
 	if schemaType := schema.Type; schemaType != "" && schemaType != "object" {
 		return schema.expectedType("object", fast)
 	}
@@ -1038,22 +1038,11 @@ func (schema *Schema) visitJSONObject(value map[string]interface{}, fast bool) (
 	}
 
 	// "patternProperties"
-	var cp *compiledPattern
-	patternProperties := schema.PatternProperties
-	if len(patternProperties) > 0 {
-		cp = schema.compiledPatternProperties
-		if cp == nil {
-			re, err := regexp.Compile(patternProperties)
-			if err != nil {
-				return fmt.Errorf("Error while compiling regular expression '%s': %v", patternProperties, err)
-			}
-			cp = &compiledPattern{
-				Regexp:    re,
-				ErrReason: "JSON property doesn't match the regular expression '" + patternProperties + "'",
-			}
-			schema.compiledPatternProperties = cp
-		}
+	compiled, err := schema.compiledContext(c)
+	if err != nil {
+		return
 	}
+	patternProperties := compiled.PatternProperties
 
 	// "additionalProperties"
 	var additionalProperties *Schema
@@ -1061,6 +1050,9 @@ func (schema *Schema) visitJSONObject(value map[string]interface{}, fast bool) (
 		additionalProperties = ref.Value
 	}
 	for k, v := range value {
+		if err := c.Err(); err != nil {
+			return err
+		}
 		if properties != nil {
 			propertyRef := properties[k]
 			if propertyRef != nil {
@@ -1068,32 +1060,30 @@ func (schema *Schema) visitJSONObject(value map[string]interface{}, fast bool) (
 				if p == nil {
 					return foundUnresolvedRef(propertyRef.Ref)
 				}
-				if err := p.VisitJSON(v); err != nil {
+				if err := p.visitJSON(c, v, false); err != nil {
 					if fast {
 						return errSchema
 					}
-					return markSchemaErrorKey(err, k)
+					return markSchemaErrorKey(err, k, "properties", k)
 				}
 				continue
 			}
 		}
 		allowed := schema.AdditionalPropertiesAllowed
 		if additionalProperties != nil || allowed == nil || (allowed != nil && *allowed) {
-			if cp != nil {
-				if !cp.Regexp.MatchString(k) {
-					return &SchemaError{
-						Schema:      schema,
-						SchemaField: "patternProperties",
-						Reason:      cp.ErrReason,
-					}
+			if patternProperties != nil && !patternProperties.MatchString(k) {
+				return &SchemaError{
+					Schema:      schema,
+					SchemaField: "patternProperties",
+					Reason:      "JSON property doesn't match the regular expression '" + schema.PatternProperties + "'",
 				}
 			}
 			if additionalProperties != nil {
-				if err := additionalProperties.VisitJSON(v); err != nil {
+				if err := additionalProperties.visitJSON(c, v, false); err != nil {
 					if fast {
 						return errSchema
 					}
-					return markSchemaErrorKey(err, k)
+					return markSchemaErrorKey(err, k, "additionalProperties")
 				}
 			}
 			continue
@@ -1105,7 +1095,8 @@ func (schema *Schema) visitJSONObject(value map[string]interface{}, fast bool) (
 			Value:       value,
 			Schema:      schema,
 			SchemaField: "properties",
-			Reason:      fmt.Sprintf("Property '%s' is unsupported", k),
+			Reason:      propertyReason("properties", k),
+			property:    k,
 		}
 	}
 	for _, k := range schema.Required {
@@ -1117,7 +1108,8 @@ func (schema *Schema) visitJSONObject(value map[string]interface{}, fast bool) (
 				Value:       value,
 				Schema:      schema,
 				SchemaField: "required",
-				Reason:      fmt.Sprintf("Property '%s' is missing", k),
+				Reason:      propertyReason("required", k),
+				property:    k,
 			}
 		}
 	}
@@ -1137,17 +1129,25 @@ func (schema *Schema) expectedType(typ string, fast bool) error {
 }
 
 type SchemaError struct {
-	Value       interface{}
-	reversePath []string
-	Schema      *Schema
-	SchemaField string
-	Reason      string
-	Origin      error
+	Value             interface{}
+	reversePath       []string
+	reverseSchemaPath []string
+	Schema            *Schema
+	SchemaField       string
+	Reason            string
+	Origin            error
+
+	// property is the name of a missing or unsupported property.
+	property string
 }
 
-func markSchemaErrorKey(err error, key string) error {
+// markSchemaErrorKey adds a property to the path of the value and keywords to the path of the schema.
+func markSchemaErrorKey(err error, key string, schemaPath ...string) error {
 	if v, ok := err.(*SchemaError); ok {
 		v.reversePath = append(v.reversePath, key)
+		for i := len(schemaPath) - 1; i >= 0; i-- {
+			v.reverseSchemaPath = append(v.reverseSchemaPath, schemaPath[i])
+		}
 		return v
 	}
 	return err
@@ -1156,11 +1156,65 @@ func markSchemaErrorKey(err error, key string) error {
 func markSchemaErrorIndex(err error, index int) error {
 	if v, ok := err.(*SchemaError); ok {
 		v.reversePath = append(v.reversePath, strconv.FormatInt(int64(index), 10))
+		v.reverseSchemaPath = append(v.reverseSchemaPath, "items")
 		return v
 	}
 	return err
 }
 
+// SchemaPath returns the path of the failing keyword in the schema, such as ["properties", "name", "minLength"].
+func (err *SchemaError) SchemaPath() []string {
+	reversePath := err.reverseSchemaPath
+	path := make([]string, len(reversePath), len(reversePath)+1)
+	for i := range path {
+		path[i] = reversePath[len(path)-1-i]
+	}
+	return append(path, err.SchemaField)
+}
+
+// Message returns the message of the catalog for the failing keyword, or the reason of the error.
+// Unlike Error, the message doesn't include the path or details.
+func (err *SchemaError) Message(catalog *MessageCatalog) string {
+	if origin, ok := err.Origin.(*SchemaError); ok {
+		return origin.Message(catalog)
+	}
+	if err.Origin != nil {
+		return err.Origin.Error()
+	}
+	path := ""
+	for i := len(err.reversePath) - 1; i >= 0; i-- {
+		path += "/" + catalog.FieldName(err.reversePath[i])
+	}
+	property := err.property
+	if property != "" {
+		property = catalog.FieldName(property)
+	}
+	if message, ok := catalog.Format(err.SchemaField, map[string]interface{}{
+		"Keyword":  err.SchemaField,
+		"Value":    err.Value,
+		"Schema":   err.Schema,
+		"Path":     path,
+		"Property": property,
+	}); ok {
+		return message
+	}
+	if err.Reason == "" {
+		return `Doesn't match schema "` + err.SchemaField + `"`
+	}
+	if property != err.property {
+		return propertyReason(err.SchemaField, property)
+	}
+	return err.Reason
+}
+
+// propertyReason returns the reason of an error of a missing or unsupported property.
+func propertyReason(schemaField string, property string) string {
+	if schemaField == "required" {
+		return fmt.Sprintf("Property '%s' is missing", property)
+	}
+	return fmt.Sprintf("Property '%s' is unsupported", property)
+}
+
 func (err *SchemaError) JSONPointer() []string {
 	reversePath := err.reversePath
 	path := make([]string, len(reversePath))
@@ -1181,18 +1235,11 @@ func (err *SchemaError) Error() string {
 		reversePath := err.reversePath
 		for i := len(reversePath) - 1; i >= 0; i-- {
 			buf.WriteByte('/')
-			buf.WriteString(reversePath[i])
+			buf.WriteString(ErrorMessages.FieldName(reversePath[i]))
 		}
 		buf.WriteString(`":`)
 	}
-	reason := err.Reason
-	if reason == "" {
-		buf.WriteString(`Doesn't match schema "`)
-		buf.WriteString(err.SchemaField)
-		buf.WriteString(`"`)
-	} else {
-		buf.WriteString(reason)
-	}
+	buf.WriteString(err.Message(ErrorMessages))
 	if !SchemaErrorDetailsDisabled {
 		buf.WriteString("\nSchema:\n  ")
 		encoder := json.NewEncoder(buf)
@@ -1208,6 +1255,10 @@ func (err *SchemaError) Error() string {
 	return buf.String()
 }
 
+func isInteger(value float64) bool {
+	return !math.IsInf(value, 0) && value == math.Trunc(value)
+}
+
 func isSliceOfUniqueItems(xs []interface{}) bool {
 	s := len(xs)
 	m := make(map[interface{}]struct{}, s)
@@ -0,0 +1,122 @@
+package openapi3
+
+import "context"
+
+// CompiledSchema is data of a schema that validation prepares once and reuses:
+// the compiled regular expressions.
+// Other fields of the schema, like "type" or "required", are read on each validation,
+// so they can be modified without ResetCompiled.
+type CompiledSchema struct {
+	// Pattern is the compiled "pattern", or the regular expression of "format" if there is no "pattern".
+	Pattern Regexp
+
+	// PatternProperties is the compiled "patternProperties".
+	PatternProperties Regexp
+
+	// The schema and the fields that the data was compiled from.
+	// Compiled data of another schema, like of a schema copied by value, isn't reused.
+	schema            *Schema
+	pattern           string
+	format            string
+	patternProperties string
+}
+
+// Compile prepares the schema and its subschemas for validation,
+// so invalid regular expressions are reported before any value is validated.
+//
+// Validation compiles schemas lazily, so calling Compile is optional.
+// A compiled schema is cached. It's compiled again when "pattern", "format", or "patternProperties" change.
+// ResetCompiled must be called if the regular expression of a format is redefined afterwards.
+func (schema *Schema) Compile() (*CompiledSchema, error) {
+	return schema.compileAll(make(map[*Schema]struct{}))
+}
+
+func (schema *Schema) compileAll(visited map[*Schema]struct{}) (*CompiledSchema, error) {
+	compiled, err := schema.compiled()
+	if err != nil {
+		return nil, err
+	}
+	visited[schema] = struct{}{}
+	refs := make([]*SchemaRef, 0, 3+len(schema.OneOf)+len(schema.AnyOf)+len(schema.AllOf)+len(schema.Properties))
+	refs = append(refs, schema.OneOf...)
+	refs = append(refs, schema.AnyOf...)
+	refs = append(refs, schema.AllOf...)
+	refs = append(refs, schema.Not, schema.Items, schema.AdditionalProperties)
+	for _, ref := range schema.Properties {
+		refs = append(refs, ref)
+	}
+	for _, ref := range refs {
+		if ref == nil || ref.Value == nil {
+			continue
+		}
+		if _, ok := visited[ref.Value]; ok {
+			continue
+		}
+		if _, err := ref.Value.compileAll(visited); err != nil {
+			return nil, err
+		}
+	}
+	return compiled, nil
+}
+
+// ResetCompiled discards the compiled schema, so the next validation compiles the schema again.
+func (schema *Schema) ResetCompiled() {
+	schema.compiledSchema.Store((*CompiledSchema)(nil))
+}
+
+// compiled returns the cached compiled schema, and compiles it when needed.
+// Subschemas are not compiled.
+func (schema *Schema) compiled() (*CompiledSchema, error) {
+	if compiled, _ := schema.compiledSchema.Load().(*CompiledSchema); compiled.isCompiledFrom(schema) {
+		return compiled, nil
+	}
+	compiled, err := schema.compile(schema.compileRegex)
+	if err != nil {
+		return nil, err
+	}
+	schema.compiledSchema.Store(compiled)
+	return compiled, nil
+}
+
+// compiledContext returns the schema compiled by the regex engine of the context,
+// or the cached compiled schema if the context has no engine.
+func (schema *Schema) compiledContext(c context.Context) (*CompiledSchema, error) {
+	if engine := regexEngineFromContext(c); engine != nil {
+		return engine.compiled(schema)
+	}
+	return schema.compiled()
+}
+
+func (compiled *CompiledSchema) isCompiledFrom(schema *Schema) bool {
+	return compiled != nil &&
+		compiled.schema == schema &&
+		compiled.pattern == schema.Pattern &&
+		compiled.format == schema.Format &&
+		compiled.patternProperties == schema.PatternProperties
+}
+
+func (schema *Schema) compile(compileRegex func(pattern string) (Regexp, error)) (*CompiledSchema, error) {
+	compiled := &CompiledSchema{
+		schema:            schema,
+		pattern:           schema.Pattern,
+		format:            schema.Format,
+		patternProperties: schema.PatternProperties,
+	}
+	if pattern := schema.Pattern; pattern != "" {
+		re, err := compileRegex(pattern)
+		if err != nil {
+			return nil, err
+		}
+		compiled.Pattern = re
+	} else if re := SchemaStringFormats[schema.Format]; re != nil {
+		compiled.Pattern = re
+	}
+	if pattern := schema.PatternProperties; pattern != "" {
+		re, err := compileRegex(pattern)
+		if err != nil {
+			return nil, err
+		}
+		compiled.PatternProperties = re
+	}
+	return compiled, nil
+}
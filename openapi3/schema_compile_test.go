@@ -0,0 +1,147 @@
+package openapi3_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+)
+
+func benchmarkSchema() *openapi3.Schema {
+	item := openapi3.NewObjectSchema().
+		WithProperty("id", openapi3.NewIntegerSchema().WithMin(1)).
+		WithProperty("email", openapi3.NewStringSchema().WithFormat("email")).
+		WithProperty("code", openapi3.NewStringSchema().WithPattern("^[A-Z]{3}-[0-9]{4}$")).
+		WithProperty("price", openapi3.NewFloat64Schema().WithMin(0))
+	item.Required = []string{"id", "email", "code"}
+	return openapi3.NewArraySchema().WithItems(item)
+}
+
+func benchmarkValue() interface{} {
+	items := make([]interface{}, 0, 20)
+	for i := 0; i < 20; i++ {
+		items = append(items, map[string]interface{}{
+			"id":    float64(i + 1),
+			"email": "user@example.com",
+			"code":  "ABC-1234",
+			"price": 9.99,
+		})
+	}
+	return items
+}
+
+func TestSchemaCompile(t *testing.T) {
+	schema := benchmarkSchema()
+	_, err := schema.Compile()
+	require.NoError(t, err)
+	code := schema.Items.Value.Properties["code"].Value
+	compiledCode, err := code.Compile()
+	require.NoError(t, err)
+	require.NotNil(t, compiledCode.Pattern)
+	require.NoError(t, schema.VisitJSON(benchmarkValue()))
+
+	again, err := code.Compile()
+	require.NoError(t, err)
+	require.True(t, compiledCode == again, "compiled schema is cached")
+	code.ResetCompiled()
+	again, err = code.Compile()
+	require.NoError(t, err)
+	require.False(t, compiledCode == again)
+
+	code.Pattern = "^[a-z]+$"
+	require.Error(t, code.VisitJSON("ABC-1234"))
+
+	invalid := openapi3.NewObjectSchema().WithProperty("name", openapi3.NewStringSchema().WithPattern("["))
+	_, err = invalid.Compile()
+	require.Error(t, err)
+	require.Error(t, invalid.VisitJSON(map[string]interface{}{"name": "x"}))
+}
+
+func TestSchemaCompileConcurrently(t *testing.T) {
+	schema := benchmarkSchema()
+	value := benchmarkValue()
+	done := make(chan error)
+	for i := 0; i < 8; i++ {
+		go func() {
+			done <- schema.VisitJSON(value)
+		}()
+	}
+	for i := 0; i < 8; i++ {
+		require.NoError(t, <-done)
+	}
+	require.NoError(t, schema.Validate(context.Background()))
+}
+
+func BenchmarkSchemaVisitJSON(b *testing.B) {
+	schema := benchmarkSchema()
+	value := benchmarkValue()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := schema.VisitJSON(value); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestSchemaVisitJSONContext(t *testing.T) {
+	c, cancel := context.WithCancel(context.Background())
+	cancel()
+	schema := benchmarkSchema()
+	require.NoError(t, schema.VisitJSONContext(context.Background(), benchmarkValue()))
+	require.Equal(t, context.Canceled, schema.VisitJSONContext(c, benchmarkValue()))
+}
+
+func TestSchemaCompileStale(t *testing.T) {
+	openapi3.DefineStringFormat("onlyx", "^x$")
+	defer delete(openapi3.SchemaStringFormats, "onlyx")
+
+	schema := openapi3.NewStringSchema()
+	require.NoError(t, schema.VisitJSON("abc"))
+	schema.WithFormat("onlyx")
+	require.Error(t, schema.VisitJSON("abc"))
+
+	schema.Format = ""
+	require.NoError(t, schema.VisitJSON("abc"), "changed format is compiled again")
+	schema.Pattern = "^x$"
+	require.Error(t, schema.VisitJSON("abc"), "changed pattern is compiled again")
+
+	schema.Pattern = ""
+	require.NoError(t, schema.VisitJSON("abc"))
+	copied := *schema
+	copied.Type = ""
+	copied.Nullable = true
+	require.True(t, copied.IsEmpty())
+	copied.Format = "onlyx"
+	require.Error(t, copied.VisitJSON("abc"), "data compiled for the original isn't reused by the copy")
+
+	swagger := &openapi3.Swagger{Components: openapi3.Components{Schemas: map[string]*openapi3.SchemaRef{
+		"Name": schema.NewRef(),
+	}}}
+	require.NoError(t, openapi3.Walk(swagger, &openapi3.Visitor{
+		Schema: func(pointer string, ref *openapi3.SchemaRef) error {
+			ref.Value.MaxLength = openapi3.Uint64Ptr(1)
+			return nil
+		},
+	}))
+	require.Error(t, schema.VisitJSON("abc"))
+}
+
+func TestSchemaCompileModifiedFields(t *testing.T) {
+	schema := &openapi3.Schema{Nullable: true}
+	require.NoError(t, schema.VisitJSON(1.0))
+	schema.Type = "string"
+	require.Error(t, schema.VisitJSON(1.0))
+
+	object := &openapi3.Schema{Nullable: true}
+	require.NoError(t, object.VisitJSON(map[string]interface{}{}))
+	object.Required = []string{"name"}
+	require.Error(t, object.VisitJSON(map[string]interface{}{}))
+
+	child := &openapi3.Schema{}
+	parent := &openapi3.Schema{Items: child.NewRef()}
+	require.NoError(t, parent.VisitJSON([]interface{}{1.0}))
+	child.Type = "string"
+	require.Error(t, parent.VisitJSON([]interface{}{1.0}))
+}
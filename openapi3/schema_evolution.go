@@ -0,0 +1,76 @@
+package openapi3
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// SchemaChangeReport describes how a corpus of recorded payloads fares against a changed schema.
+type SchemaChangeReport struct {
+	// Total is the number of replayed payloads.
+	Total int
+
+	// Broken are payloads that are valid against the old schema, but not against the new one.
+	Broken []*SchemaChangeFailure
+
+	// Ignored are payloads that are not valid against the old schema either.
+	Ignored []*SchemaChangeFailure
+}
+
+// SchemaChangeFailure is a payload that doesn't pass validation.
+type SchemaChangeFailure struct {
+	// Index is the position of the payload in the corpus.
+	Index   int
+	Payload interface{}
+	Err     error
+}
+
+// IsBreaking returns true if some recorded payload would fail after the change.
+func (report *SchemaChangeReport) IsBreaking() bool {
+	return len(report.Broken) > 0
+}
+
+// SimulateSchemaChange replays payloads against the new schema
+// and reports payloads that would no longer be valid.
+//
+// Payloads are expected to be decoded with encoding/json, so numbers must be float64.
+func SimulateSchemaChange(oldSchema *Schema, newSchema *Schema, payloads []interface{}) *SchemaChangeReport {
+	report := &SchemaChangeReport{
+		Total: len(payloads),
+	}
+	for i, payload := range payloads {
+		if err := oldSchema.VisitJSON(payload); err != nil {
+			report.Ignored = append(report.Ignored, &SchemaChangeFailure{
+				Index:   i,
+				Payload: payload,
+				Err:     err,
+			})
+			continue
+		}
+		if err := newSchema.VisitJSON(payload); err != nil {
+			report.Broken = append(report.Broken, &SchemaChangeFailure{
+				Index:   i,
+				Payload: payload,
+				Err:     err,
+			})
+		}
+	}
+	return report
+}
+
+// ReadPayloadCorpus reads a sequence of JSON values, such as newline-delimited JSON.
+func ReadPayloadCorpus(r io.Reader) ([]interface{}, error) {
+	decoder := json.NewDecoder(r)
+	var payloads []interface{}
+	for {
+		var payload interface{}
+		if err := decoder.Decode(&payload); err != nil {
+			if err == io.EOF {
+				return payloads, nil
+			}
+			return nil, fmt.Errorf("Failed to decode payload #%d: %v", len(payloads), err)
+		}
+		payloads = append(payloads, payload)
+	}
+}
@@ -0,0 +1,44 @@
+package openapi3_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimulateSchemaChange(t *testing.T) {
+	payloads, err := openapi3.ReadPayloadCorpus(strings.NewReader(`
+{"name": "a", "age": 3}
+{"name": "b"}
+{"name": "c", "age": 300}
+{"age": 1}
+`))
+	require.NoError(t, err)
+	require.Len(t, payloads, 4)
+
+	oldSchema := openapi3.NewObjectSchema().
+		WithProperty("name", openapi3.NewStringSchema()).
+		WithProperty("age", openapi3.NewIntegerSchema())
+	oldSchema.Required = []string{"name"}
+	newSchema := openapi3.NewObjectSchema().
+		WithProperty("name", openapi3.NewStringSchema()).
+		WithProperty("age", openapi3.NewIntegerSchema().WithMax(150))
+	newSchema.Required = []string{"name", "age"}
+
+	report := openapi3.SimulateSchemaChange(oldSchema, newSchema, payloads)
+	require.True(t, report.IsBreaking())
+	require.Equal(t, 4, report.Total)
+	require.Len(t, report.Broken, 2)
+	require.Equal(t, 1, report.Broken[0].Index)
+	require.Equal(t, 2, report.Broken[1].Index)
+	require.Len(t, report.Ignored, 1)
+	require.Equal(t, 3, report.Ignored[0].Index)
+
+	report = openapi3.SimulateSchemaChange(oldSchema, oldSchema, payloads)
+	require.False(t, report.IsBreaking())
+
+	_, err = openapi3.ReadPayloadCorpus(strings.NewReader(`{"name": "a"} {`))
+	require.Error(t, err)
+}
@@ -0,0 +1,94 @@
+package openapi3
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// LoaderStats reports results of options of a SwaggerLoader for the last loaded document.
+type LoaderStats struct {
+	// Schemas is the number of distinct inline schemas after interning.
+	Schemas int
+	// InternedSchemas is the number of inline schemas that were replaced with shared instances.
+	InternedSchemas int
+	// InternedBytes is the total size of JSON of replaced schemas, an estimate of saved memory.
+	InternedBytes int
+}
+
+// Stats returns results of options like InternSchemas for the last loaded document.
+func (swaggerLoader *SwaggerLoader) Stats() LoaderStats {
+	return swaggerLoader.stats
+}
+
+// internSchemasIn replaces structurally identical inline schemas of the document with shared instances.
+// Schemas of components stay the instances that references of the document refer to.
+func internSchemasIn(swagger *Swagger) (LoaderStats, error) {
+	var (
+		stats   LoaderStats
+		err     error
+		schemas = make(map[string]*Schema)
+	)
+	v := newDocumentVisitor()
+	v.visitSchemaRef = func(pointer string, ref *SchemaRef) {
+		if err != nil {
+			return
+		}
+		key, keyErr := schemaInternKey(ref.Value)
+		if keyErr != nil {
+			err = fmt.Errorf("Schema '%s' can't be interned: %v", pointer, keyErr)
+			return
+		}
+		shared := schemas[key]
+		if shared == nil {
+			schemas[key] = ref.Value
+			stats.Schemas++
+			return
+		}
+		if shared == ref.Value || isComponentSchemaPointer(pointer) {
+			return
+		}
+		ref.Value = shared
+		stats.InternedSchemas++
+		stats.InternedBytes += len(key)
+	}
+	v.visit(swagger)
+	return stats, err
+}
+
+// schemaInternKey returns JSON of the schema followed by orders of properties of the schema and its inline schemas,
+// so only schemas that are equal in every aspect share a key.
+func schemaInternKey(schema *Schema) (string, error) {
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return "", err
+	}
+	buf := bytes.NewBuffer(data)
+	writePropertyOrders(buf, schema)
+	return buf.String(), nil
+}
+
+func writePropertyOrders(buf *bytes.Buffer, schema *Schema) {
+	buf.WriteByte(0)
+	buf.WriteString(strings.Join(schema.PropertyOrder, ","))
+	refs := make([]*SchemaRef, 0, len(schema.OneOf)+len(schema.AnyOf)+len(schema.AllOf)+len(schema.Properties)+3)
+	refs = append(refs, schema.OneOf...)
+	refs = append(refs, schema.AnyOf...)
+	refs = append(refs, schema.AllOf...)
+	refs = append(refs, schema.Not, schema.Items, schema.AdditionalProperties)
+	for _, name := range sortedKeys(schema.Properties) {
+		refs = append(refs, schema.Properties[name])
+	}
+	for _, ref := range refs {
+		if ref != nil && ref.Ref == "" && ref.Value != nil {
+			writePropertyOrders(buf, ref.Value)
+		}
+	}
+}
+
+// isComponentSchemaPointer returns true if the pointer is a pointer to a schema of components, like "#/components/schemas/Pet".
+func isComponentSchemaPointer(pointer string) bool {
+	const prefix = "#/components/schemas/"
+	return strings.HasPrefix(pointer, prefix) && !strings.Contains(pointer[len(prefix):], "/")
+}
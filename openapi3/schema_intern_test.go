@@ -0,0 +1,87 @@
+package openapi3_test
+
+import (
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+)
+
+const internSchemasSpec = `
+openapi: 3.0.0
+info:
+  title: Pets
+  version: "1.0"
+paths:
+  /pets:
+    get:
+      parameters:
+        - name: limit
+          in: query
+          schema:
+            type: integer
+            minimum: 1
+      responses:
+        "200":
+          description: pets
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: "#/components/schemas/Pet"
+  /owners:
+    get:
+      parameters:
+        - name: limit
+          in: query
+          schema:
+            type: integer
+            minimum: 1
+      responses:
+        "200":
+          description: owners
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  name:
+                    type: string
+                  id:
+                    type: integer
+components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        id:
+          type: integer
+        name:
+          type: string
+`
+
+func TestSwaggerLoaderInternSchemas(t *testing.T) {
+	loader := openapi3.NewSwaggerLoader()
+	loader.InternSchemas = true
+	swagger, err := loader.LoadSwaggerFromData([]byte(internSchemasSpec))
+	require.NoError(t, err)
+
+	pets := swagger.Paths["/pets"].Get
+	owners := swagger.Paths["/owners"].Get
+	require.True(t, pets.Parameters[0].Value.Schema.Value == owners.Parameters[0].Value.Schema.Value)
+
+	// Properties of owners are declared in another order, so only schemas of properties are shared.
+	pet := swagger.Components.Schemas["Pet"].Value
+	owner := owners.Responses["200"].Value.Content["application/json"].Schema.Value
+	require.False(t, pet == owner)
+	require.Equal(t, []string{"name", "id"}, owner.OrderedPropertyNames())
+	require.True(t, pet.Properties["id"].Value == owner.Properties["id"].Value)
+	require.True(t, pet.Properties["name"].Value == owner.Properties["name"].Value)
+	require.True(t, pet == pets.Responses["200"].Value.Content["application/json"].Schema.Value.Items.Value)
+
+	stats := loader.Stats()
+	require.Equal(t, 6, stats.Schemas)
+	require.Equal(t, 3, stats.InternedSchemas)
+	require.True(t, stats.InternedBytes > 0)
+}
@@ -0,0 +1,266 @@
+package openapi3
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// MergeAllOf returns a schema with constraints of the schema and of schemas in its allOf,
+// so the result accepts the same values without allOf:
+// properties are merged, required properties of every schema are required,
+// and the tightest bounds of numbers, strings, arrays, and objects apply.
+//
+// Schemas in allOf of properties are kept as they are.
+// The function returns an error if the constraints can't be expressed by one schema,
+// for example when types or patterns differ.
+// The schema itself isn't modified.
+func (schema *Schema) MergeAllOf() (*Schema, error) {
+	return schema.mergeAllOf(make(map[*Schema]bool))
+}
+
+func (schema *Schema) mergeAllOf(visiting map[*Schema]bool) (*Schema, error) {
+	if len(schema.AllOf) == 0 {
+		return schema, nil
+	}
+	if visiting[schema] {
+		return nil, fmt.Errorf("Schemas in allOf refer to each other")
+	}
+	visiting[schema] = true
+	defer delete(visiting, schema)
+	merged := schema.cloneForMerge()
+	merged.AllOf = nil
+	for _, ref := range schema.AllOf {
+		if ref == nil {
+			continue
+		}
+		if ref.Value == nil {
+			return nil, foundUnresolvedRef(ref.Ref)
+		}
+		branch, err := ref.Value.mergeAllOf(visiting)
+		if err != nil {
+			return nil, err
+		}
+		if err := merged.mergeConstraints(branch, visiting); err != nil {
+			return nil, err
+		}
+	}
+	return merged, nil
+}
+
+// cloneForMerge returns a copy of the schema, where slices and maps that merging modifies are copied too.
+func (schema *Schema) cloneForMerge() *Schema {
+	clone := &Schema{}
+	*clone = *schema
+	clone.ResetCompiled()
+	clone.Enum = append([]interface{}(nil), schema.Enum...)
+	clone.Required = append([]string(nil), schema.Required...)
+	clone.PropertyOrder = append([]string(nil), schema.PropertyOrder...)
+	if schema.Properties != nil {
+		clone.Properties = make(map[string]*SchemaRef, len(schema.Properties))
+		for name, ref := range schema.Properties {
+			clone.Properties[name] = ref
+		}
+	}
+	return clone
+}
+
+// mergeSchemaRefs returns a schema that accepts values that both schemas accept.
+func mergeSchemaRefs(a *SchemaRef, b *SchemaRef, visiting map[*Schema]bool) (*SchemaRef, error) {
+	if a == nil || a.Value == nil {
+		return b, nil
+	}
+	if b == nil || b.Value == nil {
+		return a, nil
+	}
+	if a.Value == b.Value {
+		return a, nil
+	}
+	merged, err := NewAllOfSchema(a.Value, b.Value).mergeAllOf(visiting)
+	if err != nil {
+		return nil, err
+	}
+	// A schema with allOf isn't nullable unless it says so, but null is valid if both schemas accept it.
+	merged.Nullable = a.Value.Nullable && b.Value.Nullable
+	return &SchemaRef{Value: merged}, nil
+}
+
+// mergeConstraints adds constraints of the other schema to the schema.
+func (schema *Schema) mergeConstraints(other *Schema, visiting map[*Schema]bool) error {
+	switch {
+	case other.Type == "" || other.Type == schema.Type:
+	case schema.Type == "":
+		schema.Type = other.Type
+	case schema.Type == "number" && other.Type == "integer":
+		schema.Type = "integer"
+	case schema.Type == "integer" && other.Type == "number":
+	default:
+		return fmt.Errorf("Schemas in allOf have conflicting types '%s' and '%s'", schema.Type, other.Type)
+	}
+	switch {
+	case other.Format == "" || other.Format == schema.Format:
+	case schema.Format == "":
+		schema.Format = other.Format
+	default:
+		return fmt.Errorf("Schemas in allOf have conflicting formats '%s' and '%s'", schema.Format, other.Format)
+	}
+	if schema.Description == "" {
+		schema.Description = other.Description
+	}
+	if schema.Default == nil {
+		schema.Default = other.Default
+	}
+	if schema.Example == nil {
+		schema.Example = other.Example
+	}
+	if len(other.Enum) > 0 {
+		if len(schema.Enum) == 0 {
+			schema.Enum = append(schema.Enum, other.Enum...)
+		} else {
+			var enum []interface{}
+			for _, value := range schema.Enum {
+				for _, otherValue := range other.Enum {
+					if reflect.DeepEqual(value, otherValue) {
+						enum = append(enum, value)
+						break
+					}
+				}
+			}
+			if len(enum) == 0 {
+				return fmt.Errorf("Enums of schemas in allOf don't have a common value")
+			}
+			schema.Enum = enum
+		}
+	}
+	schema.Nullable = schema.Nullable && other.Nullable
+	schema.ReadOnly = schema.ReadOnly || other.ReadOnly
+	schema.WriteOnly = schema.WriteOnly || other.WriteOnly
+	schema.UniqueItems = schema.UniqueItems || other.UniqueItems
+
+	// Numbers
+	if other.Min != nil && (schema.Min == nil || *other.Min > *schema.Min) {
+		schema.Min, schema.ExclusiveMin = other.Min, other.ExclusiveMin
+	} else if other.Min != nil && *other.Min == *schema.Min {
+		schema.ExclusiveMin = schema.ExclusiveMin || other.ExclusiveMin
+	}
+	if other.Max != nil && (schema.Max == nil || *other.Max < *schema.Max) {
+		schema.Max, schema.ExclusiveMax = other.Max, other.ExclusiveMax
+	} else if other.Max != nil && *other.Max == *schema.Max {
+		schema.ExclusiveMax = schema.ExclusiveMax || other.ExclusiveMax
+	}
+	if b := other.MultipleOf; b != nil {
+		switch a := schema.MultipleOf; {
+		case a == nil || *a == *b:
+			schema.MultipleOf = b
+		case math.Mod(*a, *b) == 0:
+		case math.Mod(*b, *a) == 0:
+			schema.MultipleOf = b
+		default:
+			return fmt.Errorf("Schemas in allOf have multipleOf %v and %v", *a, *b)
+		}
+	}
+
+	// Strings
+	if other.MinLength > schema.MinLength {
+		schema.MinLength = other.MinLength
+	}
+	schema.MaxLength = minUint64Ptr(schema.MaxLength, other.MaxLength)
+	switch {
+	case other.Pattern == "" || other.Pattern == schema.Pattern:
+	case schema.Pattern == "":
+		schema.Pattern = other.Pattern
+	default:
+		return fmt.Errorf("Schemas in allOf have conflicting patterns '%s' and '%s'", schema.Pattern, other.Pattern)
+	}
+
+	// Arrays
+	if other.MinItems > schema.MinItems {
+		schema.MinItems = other.MinItems
+	}
+	schema.MaxItems = minUint64Ptr(schema.MaxItems, other.MaxItems)
+	items, err := mergeSchemaRefs(schema.Items, other.Items, visiting)
+	if err != nil {
+		return err
+	}
+	schema.Items = items
+
+	// Objects
+	for _, name := range other.Required {
+		if !schema.isRequired(name) {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+	for _, name := range other.OrderedPropertyNames() {
+		if schema.Properties == nil {
+			schema.Properties = make(map[string]*SchemaRef)
+		}
+		property, exists := schema.Properties[name]
+		if !exists {
+			schema.PropertyOrder = append(schema.PropertyOrder, name)
+		}
+		if schema.Properties[name], err = mergeSchemaRefs(property, other.Properties[name], visiting); err != nil {
+			return fmt.Errorf("Property '%s' can't be merged: %v", name, err)
+		}
+	}
+	if other.MinProps > schema.MinProps {
+		schema.MinProps = other.MinProps
+	}
+	schema.MaxProps = minUint64Ptr(schema.MaxProps, other.MaxProps)
+	if allowed := other.AdditionalPropertiesAllowed; allowed != nil && (schema.AdditionalPropertiesAllowed == nil || !*allowed) {
+		schema.AdditionalPropertiesAllowed = allowed
+	}
+	additionalProperties, err := mergeSchemaRefs(schema.AdditionalProperties, other.AdditionalProperties, visiting)
+	if err != nil {
+		return err
+	}
+	schema.AdditionalProperties = additionalProperties
+	switch {
+	case other.PatternProperties == "" || other.PatternProperties == schema.PatternProperties:
+	case schema.PatternProperties == "":
+		schema.PatternProperties = other.PatternProperties
+	default:
+		return fmt.Errorf("Schemas in allOf have conflicting patternProperties '%s' and '%s'", schema.PatternProperties, other.PatternProperties)
+	}
+	if schema.Discriminator == nil {
+		schema.Discriminator = other.Discriminator
+	}
+
+	// Composition
+	if len(other.OneOf) > 0 {
+		if len(schema.OneOf) > 0 {
+			return fmt.Errorf("Schemas in allOf can't have oneOf both")
+		}
+		schema.OneOf = other.OneOf
+	}
+	if len(other.AnyOf) > 0 {
+		if len(schema.AnyOf) > 0 {
+			return fmt.Errorf("Schemas in allOf can't have anyOf both")
+		}
+		schema.AnyOf = other.AnyOf
+	}
+	if other.Not != nil {
+		if schema.Not == nil {
+			schema.Not = other.Not
+		} else {
+			// Neither A nor B
+			schema.Not = NewAnyOfSchema(schema.Not.Value, other.Not.Value).NewRef()
+		}
+	}
+	return nil
+}
+
+func (schema *Schema) isRequired(name string) bool {
+	for _, required := range schema.Required {
+		if required == name {
+			return true
+		}
+	}
+	return false
+}
+
+func minUint64Ptr(a *uint64, b *uint64) *uint64 {
+	if a == nil || (b != nil && *b < *a) {
+		return b
+	}
+	return a
+}
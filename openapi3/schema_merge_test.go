@@ -0,0 +1,98 @@
+package openapi3_test
+
+import (
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+)
+
+const mergeAllOfSpec = `
+openapi: 3.0.0
+info:
+  title: Users
+  version: "1.0"
+paths: {}
+components:
+  schemas:
+    Named:
+      type: object
+      required: [name]
+      properties:
+        name:
+          type: string
+          maxLength: 20
+    Admin:
+      description: An administrator.
+      allOf:
+        - $ref: "#/components/schemas/Named"
+        - type: object
+          required: [level]
+          properties:
+            name:
+              type: string
+              minLength: 1
+              maxLength: 10
+            level:
+              type: integer
+              minimum: 1
+              maximum: 10
+        - properties:
+            level:
+              type: number
+              maximum: 5
+              exclusiveMaximum: true
+`
+
+func TestMergeAllOf(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(mergeAllOfSpec))
+	require.NoError(t, err)
+	admin := swagger.Components.Schemas["Admin"].Value
+
+	merged, err := admin.MergeAllOf()
+	require.NoError(t, err)
+	require.Len(t, admin.AllOf, 3, "the schema is not modified")
+	require.Empty(t, merged.AllOf)
+	require.Equal(t, "object", merged.Type)
+	require.Equal(t, "An administrator.", merged.Description)
+	require.Equal(t, []string{"name", "level"}, merged.Required)
+	require.Equal(t, []string{"name", "level"}, merged.OrderedPropertyNames())
+
+	name := merged.Properties["name"].Value
+	require.Equal(t, uint64(1), name.MinLength)
+	require.Equal(t, uint64(10), *name.MaxLength)
+	require.Equal(t, uint64(20), *swagger.Components.Schemas["Named"].Value.Properties["name"].Value.MaxLength)
+
+	level := merged.Properties["level"].Value
+	require.Equal(t, "integer", level.Type)
+	require.Equal(t, 1.0, *level.Min)
+	require.Equal(t, 5.0, *level.Max)
+	require.True(t, level.ExclusiveMax)
+
+	for _, value := range []map[string]interface{}{
+		{"name": "alice", "level": 3.0},
+		{"name": "alice", "level": 5.0},
+		{"name": "", "level": 3.0},
+		{"level": 3.0},
+	} {
+		require.Equal(t, admin.VisitJSON(value) == nil, merged.VisitJSON(value) == nil, "%v", value)
+	}
+
+	_, err = openapi3.NewAllOfSchema(openapi3.NewStringSchema(), openapi3.NewIntegerSchema()).MergeAllOf()
+	require.EqualError(t, err, "Schemas in allOf have conflicting types 'string' and 'integer'")
+
+	merged, err = openapi3.NewAllOfSchema(
+		openapi3.NewStringSchema().WithEnum("a", "b", "c"),
+		openapi3.NewStringSchema().WithEnum("b", "c", "d"),
+	).MergeAllOf()
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{"b", "c"}, merged.Enum)
+
+	loader := openapi3.NewSwaggerLoader()
+	loader.MergeAllOf = true
+	swagger, err = loader.LoadSwaggerFromData([]byte(mergeAllOfSpec))
+	require.NoError(t, err)
+	admin = swagger.Components.Schemas["Admin"].Value
+	require.Empty(t, admin.AllOf)
+	require.Equal(t, []string{"name", "level"}, admin.Required)
+}
@@ -0,0 +1,71 @@
+package openapi3
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+)
+
+// OrderedPropertyNames returns names of properties in the order of their declaration.
+// Properties whose order is unknown follow in lexical order.
+func (schema *Schema) OrderedPropertyNames() []string {
+	names := make([]string, 0, len(schema.Properties))
+	seen := make(map[string]bool, len(schema.Properties))
+	for _, name := range schema.PropertyOrder {
+		if _, ok := schema.Properties[name]; ok && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	if len(names) == len(schema.Properties) {
+		return names
+	}
+	rest := make([]string, 0, len(schema.Properties)-len(names))
+	for name := range schema.Properties {
+		if !seen[name] {
+			rest = append(rest, name)
+		}
+	}
+	sort.Strings(rest)
+	return append(names, rest...)
+}
+
+// propertyOrder returns keys of the member "properties" of a JSON object in their order,
+// or nil if the object doesn't have the member.
+func propertyOrder(data []byte) []string {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return nil
+	}
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil
+		}
+		if key, _ := token.(string); key != "properties" {
+			var value json.RawMessage
+			if err := decoder.Decode(&value); err != nil {
+				return nil
+			}
+			continue
+		}
+		if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+			return nil
+		}
+		var names []string
+		for decoder.More() {
+			token, err := decoder.Token()
+			if err != nil {
+				return nil
+			}
+			name, _ := token.(string)
+			names = append(names, name)
+			var value json.RawMessage
+			if err := decoder.Decode(&value); err != nil {
+				return nil
+			}
+		}
+		return names
+	}
+	return nil
+}
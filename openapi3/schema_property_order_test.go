@@ -0,0 +1,73 @@
+package openapi3_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+)
+
+const propertyOrderSpec = `
+openapi: 3.0.0
+info:
+  title: Orders
+  version: "1.0"
+paths:
+  /orders:
+    get:
+      responses:
+        200:
+          description: orders
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: "#/components/schemas/Order"
+components:
+  schemas:
+    Order:
+      type: object
+      properties:
+        status:
+          type: string
+        id:
+          type: integer
+        customer:
+          type: object
+          properties:
+            name:
+              type: string
+            email:
+              type: string
+        createdAt:
+          type: string
+`
+
+func TestSchemaPropertyOrder(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(propertyOrderSpec))
+	require.NoError(t, err)
+	order := swagger.Components.Schemas["Order"].Value
+	require.Equal(t, []string{"status", "id", "customer", "createdAt"}, order.OrderedPropertyNames())
+	require.Equal(t, []string{"name", "email"}, order.Properties["customer"].Value.OrderedPropertyNames())
+
+	// Keys that aren't strings in YAML are converted to strings.
+	require.NotNil(t, swagger.Paths["/orders"].Get.Responses.Get(200))
+
+	var schema openapi3.Schema
+	require.NoError(t, json.Unmarshal([]byte(`{"properties":{"b":{},"c":{},"a":{}}}`), &schema))
+	require.Equal(t, []string{"b", "c", "a"}, schema.PropertyOrder)
+
+	built := openapi3.NewObjectSchema().
+		WithProperty("b", openapi3.NewStringSchema()).
+		WithProperty("a", openapi3.NewStringSchema()).
+		WithProperty("b", openapi3.NewIntegerSchema())
+	require.Equal(t, []string{"b", "a"}, built.OrderedPropertyNames())
+
+	// Properties of unknown order follow in lexical order.
+	built.Properties["d"] = openapi3.NewStringSchema().NewRef()
+	built.Properties["c"] = openapi3.NewStringSchema().NewRef()
+	delete(built.Properties, "a")
+	require.Equal(t, []string{"b", "c", "d"}, built.OrderedPropertyNames())
+}
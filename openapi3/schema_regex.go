@@ -0,0 +1,177 @@
+package openapi3
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sync"
+)
+
+// Regexp is a compiled regular expression of "pattern" or "patternProperties".
+// *regexp.Regexp implements it.
+type Regexp interface {
+	MatchString(s string) bool
+	String() string
+}
+
+// RegexCompiler compiles regular expressions of schemas.
+//
+// Patterns of documents are usually written in the ECMA-262 syntax, which supports lookarounds and backreferences,
+// while the regexp package of Go supports only the RE2 syntax.
+// A compiler of another engine validates such patterns like the ecosystems that authored them.
+type RegexCompiler func(pattern string) (Regexp, error)
+
+// DefaultRegexCompiler compiles patterns of schemas that don't have a compiler. It uses the regexp package.
+var DefaultRegexCompiler RegexCompiler = compileRE2
+
+func compileRE2(pattern string) (Regexp, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return re, nil
+}
+
+// SetRegexCompiler sets the compiler of patterns of the schema, and discards the compiled schema.
+// Subschemas keep their compilers. A nil compiler means DefaultRegexCompiler.
+//
+// The compiler belongs to the schema, so a schema shared by several documents or validators,
+// like one interned by InternSchemas, uses the compiler that was set last.
+// A RegexEngine in the context of validation overrides it instead.
+func (schema *Schema) SetRegexCompiler(compiler RegexCompiler) {
+	schema.regexCompiler = compiler
+	schema.ResetCompiled()
+}
+
+func (schema *Schema) compileRegex(pattern string) (Regexp, error) {
+	return compileRegexWith(schema.regexCompiler, pattern)
+}
+
+func compileRegexWith(compiler RegexCompiler, pattern string) (Regexp, error) {
+	if compiler == nil {
+		compiler = DefaultRegexCompiler
+	}
+	re, err := compiler(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("Error while compiling regular expression '%s': %v", pattern, err)
+	}
+	return re, nil
+}
+
+// RegexEngine compiles patterns of the schemas that a validation visits,
+// in place of the compilers of the schemas.
+// It's set with ContextWithRegexEngine, so each validator can use its own engine
+// even when validators share schemas.
+//
+// An engine keeps the schemas it compiled, so it should live as long as the validator that uses it.
+// Like compiled schemas, its data isn't updated if schemas are modified directly; use a new engine instead.
+type RegexEngine struct {
+	compiler RegexCompiler
+
+	mu      sync.RWMutex
+	schemas map[*Schema]*CompiledSchema
+}
+
+// NewRegexEngine returns an engine that compiles patterns with the compiler.
+// A nil compiler means DefaultRegexCompiler.
+func NewRegexEngine(compiler RegexCompiler) *RegexEngine {
+	return &RegexEngine{
+		compiler: compiler,
+		schemas:  make(map[*Schema]*CompiledSchema),
+	}
+}
+
+// Compile compiles a pattern with the compiler of the engine.
+func (engine *RegexEngine) Compile(pattern string) (Regexp, error) {
+	return compileRegexWith(engine.compiler, pattern)
+}
+
+func (engine *RegexEngine) compiled(schema *Schema) (*CompiledSchema, error) {
+	engine.mu.RLock()
+	compiled := engine.schemas[schema]
+	engine.mu.RUnlock()
+	if compiled.isCompiledFrom(schema) {
+		return compiled, nil
+	}
+	compiled, err := schema.compile(engine.Compile)
+	if err != nil {
+		return nil, err
+	}
+	engine.mu.Lock()
+	engine.schemas[schema] = compiled
+	engine.mu.Unlock()
+	return compiled, nil
+}
+
+type regexEngineContextKey struct{}
+
+// ContextWithRegexEngine returns a context whose validations compile patterns with the engine.
+// It's passed to functions like VisitJSONContext.
+func ContextWithRegexEngine(c context.Context, engine *RegexEngine) context.Context {
+	return context.WithValue(c, regexEngineContextKey{}, engine)
+}
+
+func regexEngineFromContext(c context.Context) *RegexEngine {
+	if c == nil {
+		return nil
+	}
+	engine, _ := c.Value(regexEngineContextKey{}).(*RegexEngine)
+	return engine
+}
+
+// PatternError tells that a regular expression of a schema doesn't compile.
+type PatternError struct {
+	// Pointer is the JSON pointer of the schema in the document,
+	// like "#/components/schemas/User/properties/name".
+	Pointer string
+
+	// Field is "pattern" or "patternProperties".
+	Field   string
+	Pattern string
+	Err     error
+}
+
+func (err *PatternError) Error() string {
+	return fmt.Sprintf("%s/%s: %v", err.Pointer, err.Field, err.Err)
+}
+
+// CheckPatterns compiles regular expressions of schemas in paths and components with the compiler,
+// and returns errors of those that don't compile, in a stable order.
+// A nil compiler means DefaultRegexCompiler,
+// so patterns that need another engine can be found before any value is validated.
+func (swagger *Swagger) CheckPatterns(compiler RegexCompiler) []*PatternError {
+	if compiler == nil {
+		compiler = DefaultRegexCompiler
+	}
+	var errs []*PatternError
+	v := newDocumentVisitor()
+	v.visitSchema = func(pointer string, schema *Schema) {
+		for _, field := range []struct {
+			name    string
+			pattern string
+		}{{"pattern", schema.Pattern}, {"patternProperties", schema.PatternProperties}} {
+			if field.pattern == "" {
+				continue
+			}
+			if _, err := compiler(field.pattern); err != nil {
+				errs = append(errs, &PatternError{
+					Pointer: pointer,
+					Field:   field.name,
+					Pattern: field.pattern,
+					Err:     err,
+				})
+			}
+		}
+	}
+	v.visit(swagger)
+	return errs
+}
+
+// setRegexCompilerIn sets the compiler of every schema of the document.
+func setRegexCompilerIn(swagger *Swagger, compiler RegexCompiler) {
+	v := newDocumentVisitor()
+	v.visitSchema = func(pointer string, schema *Schema) {
+		schema.SetRegexCompiler(compiler)
+	}
+	v.visit(swagger)
+}
@@ -0,0 +1,108 @@
+package openapi3_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+)
+
+const regexSpec = `
+openapi: 3.0.0
+info:
+  title: Users
+  version: "1.0"
+paths:
+  /users/{name}:
+    get:
+      parameters:
+        - name: name
+          in: path
+          required: true
+          schema:
+            type: string
+            pattern: "^(?!admin).+$"
+      responses:
+        default:
+          description: user
+components:
+  schemas:
+    Code:
+      type: string
+      pattern: "^[A-Z]+$"
+`
+
+// prefixMatcher stands for another engine: it supports only the negative lookahead of a prefix.
+type prefixMatcher struct {
+	pattern string
+	prefix  string
+}
+
+func (m *prefixMatcher) MatchString(s string) bool { return !strings.HasPrefix(s, m.prefix) }
+func (m *prefixMatcher) String() string            { return m.pattern }
+
+func compilePrefix(pattern string) (openapi3.Regexp, error) {
+	if !strings.HasPrefix(pattern, "^(?!") {
+		return openapi3.DefaultRegexCompiler(pattern)
+	}
+	end := strings.Index(pattern, ")")
+	if end < 0 {
+		return nil, errors.New("unsupported pattern")
+	}
+	return &prefixMatcher{pattern: pattern, prefix: pattern[len("^(?!"):end]}, nil
+}
+
+func TestCheckPatterns(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(regexSpec))
+	require.NoError(t, err)
+
+	errs := swagger.CheckPatterns(nil)
+	require.Len(t, errs, 1)
+	require.Equal(t, "#/paths/~1users~1{name}/get/parameters/0/schema", errs[0].Pointer)
+	require.Equal(t, "pattern", errs[0].Field)
+	require.Equal(t, "^(?!admin).+$", errs[0].Pattern)
+
+	require.Empty(t, swagger.CheckPatterns(compilePrefix))
+}
+
+func TestLoaderRegexCompiler(t *testing.T) {
+	loader := openapi3.NewSwaggerLoader()
+	loader.RegexCompiler = compilePrefix
+	swagger, err := loader.LoadSwaggerFromData([]byte(regexSpec))
+	require.NoError(t, err)
+
+	name := swagger.Paths["/users/{name}"].Get.Parameters[0].Value.Schema.Value
+	require.NoError(t, name.VisitJSON("alice"))
+	require.Error(t, name.VisitJSON("administrator"))
+
+	code := swagger.Components.Schemas["Code"].Value
+	require.NoError(t, code.VisitJSON("ABC"))
+	require.Error(t, code.VisitJSON("abc"))
+}
+
+func TestSetRegexCompiler(t *testing.T) {
+	schema := openapi3.NewStringSchema().WithPattern("^(?!admin).+$")
+	_, err := schema.Compile()
+	require.Error(t, err)
+
+	schema.SetRegexCompiler(compilePrefix)
+	_, err = schema.Compile()
+	require.NoError(t, err)
+	require.Error(t, schema.VisitJSON("admin"))
+}
+
+func TestRegexEngine(t *testing.T) {
+	schema := openapi3.NewObjectSchema().
+		WithProperty("name", openapi3.NewStringSchema().WithPattern("^(?!admin).+$"))
+	require.Error(t, schema.VisitJSON(map[string]interface{}{"name": "alice"}))
+
+	c := openapi3.ContextWithRegexEngine(context.Background(), openapi3.NewRegexEngine(compilePrefix))
+	require.NoError(t, schema.VisitJSONContext(c, map[string]interface{}{"name": "alice"}))
+	require.Error(t, schema.VisitJSONContext(c, map[string]interface{}{"name": "administrator"}))
+
+	// The engine doesn't change the schema.
+	require.Error(t, schema.VisitJSON(map[string]interface{}{"name": "alice"}))
+}
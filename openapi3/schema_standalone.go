@@ -0,0 +1,235 @@
+package openapi3
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Drafts of JSON Schema that MarshalStandaloneJSONSchema supports.
+const (
+	JSONSchemaDraft07     = "http://json-schema.org/draft-07/schema#"
+	JSONSchemaDraft202012 = "https://json-schema.org/draft/2020-12/schema"
+)
+
+// MarshalStandaloneJSONSchema returns a JSON Schema of the draft that doesn't refer to the document of the schema,
+// so validators of JSON Schema can use it.
+//
+// Referenced schemas become definitions of "$defs", which are named by last segments of references,
+// like "Pet" for "#/components/schemas/Pet". Names of different references that end with the same segment get numbers.
+// Keywords are converted from OpenAPI to JSON Schema: for example, "nullable" adds the "null" type,
+// and "example" becomes "examples". Keywords without equivalents, like "discriminator" and extensions, are removed.
+// The output is stable, because keys of objects are sorted.
+func (schema *Schema) MarshalStandaloneJSONSchema(draft string) ([]byte, error) {
+	switch draft {
+	case JSONSchemaDraft07, JSONSchemaDraft202012:
+	default:
+		return nil, fmt.Errorf("Unsupported JSON Schema draft %q", draft)
+	}
+	e := &standaloneSchemaEncoder{
+		names: make(map[string]string),
+		used:  make(map[string]bool),
+		defs:  make(map[string]interface{}),
+	}
+	root, err := e.schema(schema)
+	if err != nil {
+		return nil, err
+	}
+	root["$schema"] = draft
+	if len(e.defs) > 0 {
+		root["$defs"] = e.defs
+	}
+	return json.Marshal(root)
+}
+
+type standaloneSchemaEncoder struct {
+	// names maps references to names of their definitions.
+	names map[string]string
+	used  map[string]bool
+	defs  map[string]interface{}
+}
+
+func (e *standaloneSchemaEncoder) ref(ref *SchemaRef) (map[string]interface{}, error) {
+	if ref.Ref == "" {
+		if ref.Value == nil {
+			return map[string]interface{}{}, nil
+		}
+		return e.schema(ref.Value)
+	}
+	name, ok := e.names[ref.Ref]
+	if !ok {
+		if ref.Value == nil {
+			return nil, fmt.Errorf("Reference %q isn't resolved", ref.Ref)
+		}
+		name = e.definitionName(ref.Ref)
+		// The name is reserved before the value is encoded, so recursive schemas refer to it.
+		e.names[ref.Ref] = name
+		def, err := e.schema(ref.Value)
+		if err != nil {
+			return nil, err
+		}
+		e.defs[name] = def
+	}
+	return map[string]interface{}{"$ref": "#/$defs/" + escapeJSONPointer(name)}, nil
+}
+
+func (e *standaloneSchemaEncoder) definitionName(ref string) string {
+	base := ref[strings.LastIndexAny(ref, "/#")+1:]
+	base = strings.Replace(strings.Replace(base, "~1", "/", -1), "~0", "~", -1)
+	if base == "" {
+		base = "schema"
+	}
+	name := base
+	for i := 2; e.used[name]; i++ {
+		name = base + strconv.Itoa(i)
+	}
+	e.used[name] = true
+	return name
+}
+
+func (e *standaloneSchemaEncoder) refs(refs []*SchemaRef) ([]interface{}, error) {
+	result := make([]interface{}, 0, len(refs))
+	for _, ref := range refs {
+		v, err := e.ref(ref)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, v)
+	}
+	return result, nil
+}
+
+func (e *standaloneSchemaEncoder) schema(schema *Schema) (map[string]interface{}, error) {
+	m := make(map[string]interface{})
+	// Keywords are visited in a stable order, because it decides names of definitions.
+	for _, keyword := range []struct {
+		key  string
+		refs []*SchemaRef
+	}{{"oneOf", schema.OneOf}, {"anyOf", schema.AnyOf}, {"allOf", schema.AllOf}} {
+		if len(keyword.refs) > 0 {
+			v, err := e.refs(keyword.refs)
+			if err != nil {
+				return nil, err
+			}
+			m[keyword.key] = v
+		}
+	}
+	for _, keyword := range []struct {
+		key string
+		ref *SchemaRef
+	}{{"not", schema.Not}, {"items", schema.Items}, {"additionalProperties", schema.AdditionalProperties}} {
+		if keyword.ref != nil {
+			v, err := e.ref(keyword.ref)
+			if err != nil {
+				return nil, err
+			}
+			m[keyword.key] = v
+		}
+	}
+	if v := schema.AdditionalPropertiesAllowed; v != nil && schema.AdditionalProperties == nil {
+		m["additionalProperties"] = *v
+	}
+	if len(schema.Properties) > 0 {
+		names := make([]string, 0, len(schema.Properties))
+		for name := range schema.Properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		properties := make(map[string]interface{}, len(names))
+		for _, name := range names {
+			v, err := e.ref(schema.Properties[name])
+			if err != nil {
+				return nil, err
+			}
+			properties[name] = v
+		}
+		m["properties"] = properties
+	}
+
+	if v := schema.Type; v != "" {
+		m["type"] = v
+	}
+	if v := schema.Format; v != "" {
+		m["format"] = v
+	}
+	if v := schema.Description; v != "" {
+		m["description"] = v
+	}
+	if len(schema.Enum) > 0 {
+		m["enum"] = append([]interface{}(nil), schema.Enum...)
+	}
+	if v := schema.Default; v != nil {
+		m["default"] = v
+	}
+	if v := schema.Example; v != nil {
+		m["examples"] = []interface{}{v}
+	}
+	if schema.ReadOnly {
+		m["readOnly"] = true
+	}
+	if schema.WriteOnly {
+		m["writeOnly"] = true
+	}
+
+	// Exclusive bounds are numbers in JSON Schema.
+	if v := schema.Min; v != nil {
+		if schema.ExclusiveMin {
+			m["exclusiveMinimum"] = *v
+		} else {
+			m["minimum"] = *v
+		}
+	}
+	if v := schema.Max; v != nil {
+		if schema.ExclusiveMax {
+			m["exclusiveMaximum"] = *v
+		} else {
+			m["maximum"] = *v
+		}
+	}
+	if v := schema.MultipleOf; v != nil {
+		m["multipleOf"] = *v
+	}
+
+	if v := schema.MinLength; v != 0 {
+		m["minLength"] = v
+	}
+	if v := schema.MaxLength; v != nil {
+		m["maxLength"] = *v
+	}
+	if v := schema.Pattern; v != "" {
+		m["pattern"] = v
+	}
+
+	if v := schema.MinItems; v != 0 {
+		m["minItems"] = v
+	}
+	if v := schema.MaxItems; v != nil {
+		m["maxItems"] = *v
+	}
+	if schema.UniqueItems {
+		m["uniqueItems"] = true
+	}
+
+	if len(schema.Required) > 0 {
+		m["required"] = append([]string(nil), schema.Required...)
+	}
+	if v := schema.MinProps; v != 0 {
+		m["minProperties"] = v
+	}
+	if v := schema.MaxProps; v != nil {
+		m["maxProperties"] = *v
+	}
+
+	if schema.Nullable {
+		if enum, ok := m["enum"].([]interface{}); ok {
+			m["enum"] = append(enum, nil)
+		}
+		if schema.Type == "" {
+			return map[string]interface{}{"anyOf": []interface{}{m, map[string]interface{}{"type": "null"}}}, nil
+		}
+		m["type"] = []interface{}{schema.Type, "null"}
+	}
+	return m, nil
+}
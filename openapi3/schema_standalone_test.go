@@ -0,0 +1,108 @@
+package openapi3_test
+
+import (
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+)
+
+const standaloneSpec = `
+openapi: 3.0.0
+info:
+  title: Pets
+  version: "1.0"
+paths: {}
+components:
+  schemas:
+    Pet:
+      type: object
+      required: [name]
+      discriminator:
+        propertyName: kind
+      x-internal: true
+      properties:
+        name:
+          type: string
+          example: Rex
+        tag:
+          type: string
+          nullable: true
+        owner:
+          $ref: "#/components/schemas/Owner"
+        age:
+          type: integer
+          minimum: 0
+          exclusiveMinimum: true
+    Owner:
+      type: object
+      properties:
+        pets:
+          type: array
+          items:
+            $ref: "#/components/schemas/Pet"
+`
+
+func TestMarshalStandaloneJSONSchema(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(standaloneSpec))
+	require.NoError(t, err)
+	pet := swagger.Components.Schemas["Pet"].Value
+
+	data, err := pet.MarshalStandaloneJSONSchema(openapi3.JSONSchemaDraft202012)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "type": "object",
+  "required": ["name"],
+  "properties": {
+    "age": {"type": "integer", "exclusiveMinimum": 0},
+    "name": {"type": "string", "examples": ["Rex"]},
+    "owner": {"$ref": "#/$defs/Owner"},
+    "tag": {"type": ["string", "null"]}
+  },
+  "$defs": {
+    "Owner": {
+      "type": "object",
+      "properties": {
+        "pets": {"type": "array", "items": {"$ref": "#/$defs/Pet"}}
+      }
+    },
+    "Pet": {
+      "type": "object",
+      "required": ["name"],
+      "properties": {
+        "age": {"type": "integer", "exclusiveMinimum": 0},
+        "name": {"type": "string", "examples": ["Rex"]},
+        "owner": {"$ref": "#/$defs/Owner"},
+        "tag": {"type": ["string", "null"]}
+      }
+    }
+  }
+}`, string(data))
+
+	again, err := pet.MarshalStandaloneJSONSchema(openapi3.JSONSchemaDraft202012)
+	require.NoError(t, err)
+	require.Equal(t, string(data), string(again))
+
+	_, err = pet.MarshalStandaloneJSONSchema("http://json-schema.org/draft-04/schema#")
+	require.Error(t, err)
+}
+
+func TestMarshalStandaloneJSONSchemaNullable(t *testing.T) {
+	schema := &openapi3.Schema{
+		Nullable: true,
+		OneOf: []*openapi3.SchemaRef{
+			{Value: openapi3.NewStringSchema()},
+			{Value: &openapi3.Schema{Type: "string", Enum: []interface{}{"a"}, Nullable: true}},
+		},
+	}
+	data, err := schema.MarshalStandaloneJSONSchema(openapi3.JSONSchemaDraft07)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "anyOf": [
+    {"oneOf": [{"type": "string"}, {"type": ["string", "null"], "enum": ["a", null]}]},
+    {"type": "null"}
+  ]
+}`, string(data))
+}
@@ -705,3 +705,23 @@ var schemaErrorExamples = []schemaErrorExample{
 		Want: "NEST",
 	},
 }
+
+func TestErrorFieldNames(t *testing.T) {
+	openapi3.ErrorMessages = openapi3.NewMessageCatalog().WithFieldNames(func(name string) string {
+		return strings.Replace(name, "_", " ", -1)
+	})
+	defer func() { openapi3.ErrorMessages = nil }()
+
+	schema := openapi3.NewObjectSchema().
+		WithProperty("home_address", openapi3.NewObjectSchema().
+			WithProperty("zip_code", openapi3.NewStringSchema().WithMaxLength(5)))
+	schema.Properties["home_address"].Value.Required = []string{"street_name"}
+
+	err := schema.VisitJSON(map[string]interface{}{
+		"home_address": map[string]interface{}{"zip_code": "12345"},
+	})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `Error at "/home address":Property 'street name' is missing`)
+	require.Equal(t, []string{"home_address"}, err.(*openapi3.SchemaError).JSONPointer())
+	require.Equal(t, "Property 'street_name' is missing", err.(*openapi3.SchemaError).Reason)
+}
@@ -0,0 +1,76 @@
+package openapi3
+
+import (
+	"strings"
+)
+
+// DisallowAdditionalPropertiesOptions selects schemas that DisallowAdditionalProperties changes.
+type DisallowAdditionalPropertiesOptions struct {
+	// Include lists JSON pointers of schemas like "#/components/schemas/User" that may be changed,
+	// including schemas nested in them. If empty, every schema may be changed.
+	Include []string
+
+	// Exclude lists JSON pointers of schemas that must not be changed, including schemas nested in them.
+	Exclude []string
+}
+
+// DisallowAdditionalProperties sets additionalProperties to false in object schemas
+// that don't declare additionalProperties, and returns JSON pointers of changed schemas.
+// Validation of the document can then be compared before and after the change,
+// for example with SimulateSchemaChange.
+//
+// Schemas without properties are free-form objects, so they aren't changed,
+// and neither are schemas with patternProperties.
+// Schemas with allOf, anyOf, or oneOf, and schemas in allOf aren't changed either,
+// because they would reject properties of other subschemas.
+func (swagger *Swagger) DisallowAdditionalProperties(options *DisallowAdditionalPropertiesOptions) []string {
+	if options == nil {
+		options = &DisallowAdditionalPropertiesOptions{}
+	}
+	composed := make(map[*Schema]bool)
+	v := newDocumentVisitor()
+	v.visitSchema = func(pointer string, schema *Schema) {
+		for _, ref := range schema.AllOf {
+			if ref != nil && ref.Value != nil {
+				composed[ref.Value] = true
+			}
+		}
+	}
+	v.visit(swagger)
+
+	var changed []string
+	v = newDocumentVisitor()
+	v.visitSchema = func(pointer string, schema *Schema) {
+		if composed[schema] ||
+			len(schema.Properties) == 0 ||
+			len(schema.AllOf)+len(schema.AnyOf)+len(schema.OneOf) > 0 ||
+			schema.AdditionalProperties != nil ||
+			schema.AdditionalPropertiesAllowed != nil ||
+			schema.PatternProperties != "" ||
+			(schema.Type != "" && schema.Type != "object") {
+			return
+		}
+		if len(options.Include) > 0 && !matchesPointers(pointer, options.Include) {
+			return
+		}
+		if matchesPointers(pointer, options.Exclude) {
+			return
+		}
+		allowed := false
+		schema.AdditionalPropertiesAllowed = &allowed
+		schema.ResetCompiled()
+		changed = append(changed, pointer)
+	}
+	v.visit(swagger)
+	return changed
+}
+
+// matchesPointers returns true if the JSON pointer is one of the pointers, or is nested in one of them.
+func matchesPointers(pointer string, pointers []string) bool {
+	for _, prefix := range pointers {
+		if pointer == prefix || strings.HasPrefix(pointer, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
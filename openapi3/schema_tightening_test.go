@@ -0,0 +1,94 @@
+package openapi3_test
+
+import (
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+)
+
+const tighteningSpec = `
+openapi: 3.0.0
+info:
+  title: Users
+  version: "1.0"
+paths:
+  /users:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                user:
+                  $ref: "#/components/schemas/User"
+                options:
+                  type: object
+                  properties:
+                    notify:
+                      type: boolean
+      responses:
+        default:
+          description: created
+components:
+  schemas:
+    User:
+      type: object
+      properties:
+        name:
+          type: string
+        labels:
+          type: object
+          additionalProperties:
+            type: string
+        metadata:
+          type: object
+          properties:
+            source:
+              type: string
+    Admin:
+      allOf:
+        - $ref: "#/components/schemas/User"
+        - type: object
+          properties:
+            role:
+              type: string
+    Open:
+      type: object
+      additionalProperties: true
+      properties:
+        name:
+          type: string
+`
+
+func TestDisallowAdditionalProperties(t *testing.T) {
+	load := func() *openapi3.Swagger {
+		swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(tighteningSpec))
+		require.NoError(t, err)
+		return swagger
+	}
+
+	swagger := load()
+	user := swagger.Components.Schemas["User"].Value
+	require.NoError(t, user.VisitJSON(map[string]interface{}{"name": "alice", "age": 30.0}))
+	changed := swagger.DisallowAdditionalProperties(nil)
+	// User is in allOf of Admin, so it isn't changed.
+	require.Equal(t, []string{
+		"#/paths/~1users/post/requestBody/content/application~1json/schema",
+		"#/paths/~1users/post/requestBody/content/application~1json/schema/properties/options",
+		"#/components/schemas/User/properties/metadata",
+	}, changed)
+	require.NoError(t, user.VisitJSON(map[string]interface{}{"name": "alice", "age": 30.0}))
+	metadata := user.Properties["metadata"].Value
+	require.Error(t, metadata.VisitJSON(map[string]interface{}{"source": "import", "at": "now"}))
+
+	swagger = load()
+	changed = swagger.DisallowAdditionalProperties(&openapi3.DisallowAdditionalPropertiesOptions{
+		Include: []string{"#/paths"},
+		Exclude: []string{"#/paths/~1users/post/requestBody/content/application~1json/schema/properties/options"},
+	})
+	require.Equal(t, []string{
+		"#/paths/~1users/post/requestBody/content/application~1json/schema",
+	}, changed)
+}
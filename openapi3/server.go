@@ -5,6 +5,8 @@ import (
 	"errors"
 	"net/url"
 	"strings"
+
+	"github.com/getkin/kin-openapi/jsoninfo"
 )
 
 // Servers is specified by OpenAPI/Swagger standard version 3.0.
@@ -35,11 +37,20 @@ func (servers Servers) MatchURL(parsedURL *url.URL) (*Server, []string, string)
 
 // Server is specified by OpenAPI/Swagger standard version 3.0.
 type Server struct {
+	ExtensionProps
 	URL         string                     `json:"url,omitempty"`
 	Description string                     `json:"description,omitempty"`
 	Variables   map[string]*ServerVariable `json:"variables,omitempty"`
 }
 
+func (server *Server) MarshalJSON() ([]byte, error) {
+	return jsoninfo.MarshalStrictStruct(server)
+}
+
+func (server *Server) UnmarshalJSON(data []byte) error {
+	return jsoninfo.UnmarshalStrictStruct(data, server)
+}
+
 func (server Server) ParameterNames() ([]string, error) {
 	pattern := server.URL
 	var params []string
@@ -0,0 +1,207 @@
+package openapi3
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// ExtServerName is the extension of a server that names its environment, like "staging".
+const ExtServerName = "x-server-name"
+
+// Environment variables read by ServerSelectionFromEnv.
+const (
+	EnvServerName      = "OPENAPI_SERVER"
+	EnvServerURL       = "OPENAPI_SERVER_URL"
+	EnvServerVarPrefix = "OPENAPI_SERVER_VAR_"
+)
+
+// ServerSelection selects the server that tools like clients and mock servers use,
+// so one document drives every environment.
+type ServerSelection struct {
+	// Name selects the server that has the name in the extension ExtServerName,
+	// or else the description. If empty, the first server is selected.
+	Name string
+
+	// URL overrides the URL of the selected server. The URL may have variables too.
+	URL string
+
+	// Variables override default values of variables of the server.
+	Variables map[string]string
+}
+
+// ServerSelectionFromEnv returns a selection described by environment variables:
+//
+//	OPENAPI_SERVER=staging
+//	OPENAPI_SERVER_URL=https://{region}.staging.example.com/v1
+//	OPENAPI_SERVER_VAR_REGION=eu
+//
+// Names of variables are matched case-insensitively, with characters other than letters and digits
+// replaced by '_'.
+func ServerSelectionFromEnv() *ServerSelection {
+	selection := &ServerSelection{
+		Name: os.Getenv(EnvServerName),
+		URL:  os.Getenv(EnvServerURL),
+	}
+	for _, kv := range os.Environ() {
+		if !strings.HasPrefix(kv, EnvServerVarPrefix) {
+			continue
+		}
+		kv = kv[len(EnvServerVarPrefix):]
+		i := strings.IndexByte(kv, '=')
+		if i <= 0 {
+			continue
+		}
+		if selection.Variables == nil {
+			selection.Variables = make(map[string]string)
+		}
+		selection.Variables[kv[:i]] = kv[i+1:]
+	}
+	return selection
+}
+
+// Select returns the selected server. The server is a copy if the selection overrides its URL.
+func (selection *ServerSelection) Select(servers Servers) (*Server, error) {
+	var server *Server
+	if name := selection.Name; name != "" {
+		if server = servers.ByName(name); server == nil {
+			return nil, fmt.Errorf("Server '%s' doesn't exist", name)
+		}
+	} else if len(servers) > 0 {
+		server = servers[0]
+	}
+	if selection.URL != "" {
+		if server == nil {
+			server = &Server{}
+		}
+		override := *server
+		override.URL = selection.URL
+		server = &override
+	}
+	if server == nil {
+		return nil, errors.New("No server is declared")
+	}
+	return server, nil
+}
+
+// BaseURL returns the URL of the selected server with values of its variables.
+func (selection *ServerSelection) BaseURL(servers Servers) (string, error) {
+	server, err := selection.Select(servers)
+	if err != nil {
+		return "", err
+	}
+	return server.BaseURL(selection.Variables)
+}
+
+// ByName returns the server that has the name in the extension ExtServerName,
+// or else the server whose description is the name, or nil if there's no such server.
+func (servers Servers) ByName(name string) *Server {
+	for _, server := range servers {
+		if serverName(server) == name {
+			return server
+		}
+	}
+	for _, server := range servers {
+		if strings.EqualFold(server.Description, name) {
+			return server
+		}
+	}
+	return nil
+}
+
+func serverName(server *Server) string {
+	var name string
+	switch v := server.Extensions[ExtServerName].(type) {
+	case string:
+		name = v
+	case json.RawMessage:
+		json.Unmarshal(v, &name)
+	}
+	return name
+}
+
+// BaseURL returns the URL of the server where variables are replaced with the values,
+// or else with their defaults.
+// The function returns an error if a value isn't in the enum of the variable.
+func (server *Server) BaseURL(values map[string]string) (string, error) {
+	var buf strings.Builder
+	for pattern := server.URL; pattern != ""; {
+		i := strings.IndexByte(pattern, '{')
+		if i < 0 {
+			buf.WriteString(pattern)
+			break
+		}
+		buf.WriteString(pattern[:i])
+		pattern = pattern[i+1:]
+		i = strings.IndexByte(pattern, '}')
+		if i < 0 {
+			return "", fmt.Errorf("Server URL '%s' is missing '}'", server.URL)
+		}
+		name := strings.TrimSpace(pattern[:i])
+		pattern = pattern[i+1:]
+		value, err := server.variableValue(name, values)
+		if err != nil {
+			return "", err
+		}
+		buf.WriteString(value)
+	}
+	return buf.String(), nil
+}
+
+func (server *Server) variableValue(name string, values map[string]string) (string, error) {
+	variable := server.Variables[name]
+	value, ok := values[name]
+	if !ok {
+		for key, v := range values {
+			if strings.EqualFold(key, envVariableName(name)) {
+				value, ok = v, true
+				break
+			}
+		}
+	}
+	if !ok {
+		if variable == nil || variable.Default == nil {
+			return "", fmt.Errorf("Server variable '%s' doesn't have a value", name)
+		}
+		return fmt.Sprint(variable.Default), nil
+	}
+	if variable != nil && len(variable.Enum) > 0 {
+		for _, item := range variable.Enum {
+			if fmt.Sprint(item) == value {
+				return value, nil
+			}
+		}
+		return "", fmt.Errorf("Server variable '%s' doesn't allow value %q", name, value)
+	}
+	return value, nil
+}
+
+// envVariableName returns the name of a variable where characters other than letters and digits are '_'.
+func envVariableName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+// RewriteURL returns the URL of a request to one of the servers, rewritten to the base URL,
+// so requests of one environment can be sent to another.
+// The function returns an error if the URL doesn't match any of the servers.
+func (servers Servers) RewriteURL(u *url.URL, baseURL string) (*url.URL, error) {
+	server, _, remaining := servers.MatchURL(u)
+	if server == nil {
+		return nil, fmt.Errorf("URL '%s' doesn't match any server", u)
+	}
+	result, err := url.Parse(strings.TrimSuffix(baseURL, "/") + remaining)
+	if err != nil {
+		return nil, err
+	}
+	result.RawQuery = u.RawQuery
+	result.Fragment = u.Fragment
+	return result, nil
+}
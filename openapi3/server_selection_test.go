@@ -0,0 +1,97 @@
+package openapi3_test
+
+import (
+	"net/url"
+	"os"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+)
+
+const serverSelectionSpec = `
+openapi: 3.0.0
+info:
+  title: Environments
+  version: "1.0"
+servers:
+  - url: https://api.example.com/v1
+    description: Production
+    x-server-name: prod
+  - url: https://{region}.staging.example.com/v1
+    description: Staging
+    x-server-name: staging
+    variables:
+      region:
+        default: us
+        enum: [us, eu]
+  - url: http://localhost:{port}/v1
+    description: Local
+    variables:
+      port:
+        default: 8080
+paths: {}
+`
+
+func TestServerSelection(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(serverSelectionSpec))
+	require.NoError(t, err)
+	servers := swagger.Servers
+
+	for _, tc := range []struct {
+		selection openapi3.ServerSelection
+		want      string
+		err       string
+	}{
+		{selection: openapi3.ServerSelection{}, want: "https://api.example.com/v1"},
+		{selection: openapi3.ServerSelection{Name: "staging"}, want: "https://us.staging.example.com/v1"},
+		{selection: openapi3.ServerSelection{Name: "staging", Variables: map[string]string{"region": "eu"}}, want: "https://eu.staging.example.com/v1"},
+		{selection: openapi3.ServerSelection{Name: "staging", Variables: map[string]string{"region": "ap"}}, err: `Server variable 'region' doesn't allow value "ap"`},
+		{selection: openapi3.ServerSelection{Name: "local"}, want: "http://localhost:8080/v1"},
+		{selection: openapi3.ServerSelection{Name: "local", Variables: map[string]string{"PORT": "9090"}}, want: "http://localhost:9090/v1"},
+		{selection: openapi3.ServerSelection{Name: "staging", URL: "https://{region}.preview.example.com"}, want: "https://us.preview.example.com"},
+		{selection: openapi3.ServerSelection{Name: "qa"}, err: "Server 'qa' doesn't exist"},
+	} {
+		got, err := tc.selection.BaseURL(servers)
+		if tc.err != "" {
+			require.EqualError(t, err, tc.err)
+			continue
+		}
+		require.NoError(t, err)
+		require.Equal(t, tc.want, got)
+	}
+
+	data, err := swagger.MarshalJSON()
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"x-server-name":"staging"`)
+}
+
+func TestServerSelectionFromEnv(t *testing.T) {
+	for k, v := range map[string]string{
+		openapi3.EnvServerName:                 "staging",
+		openapi3.EnvServerVarPrefix + "REGION": "eu",
+	} {
+		require.NoError(t, os.Setenv(k, v))
+		defer os.Unsetenv(k)
+	}
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(serverSelectionSpec))
+	require.NoError(t, err)
+	got, err := openapi3.ServerSelectionFromEnv().BaseURL(swagger.Servers)
+	require.NoError(t, err)
+	require.Equal(t, "https://eu.staging.example.com/v1", got)
+}
+
+func TestServersRewriteURL(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(serverSelectionSpec))
+	require.NoError(t, err)
+	u, err := url.Parse("https://api.example.com/v1/users/1?fields=name")
+	require.NoError(t, err)
+	got, err := swagger.Servers.RewriteURL(u, "http://localhost:8080/v1/")
+	require.NoError(t, err)
+	require.Equal(t, "http://localhost:8080/v1/users/1?fields=name", got.String())
+
+	u, err = url.Parse("https://other.example.com/users")
+	require.NoError(t, err)
+	_, err = swagger.Servers.RewriteURL(u, "http://localhost:8080/v1")
+	require.Error(t, err)
+}
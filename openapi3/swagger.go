@@ -15,6 +15,8 @@ type Swagger struct {
 	Components   Components           `json:"components,omitempty"`
 	Security     SecurityRequirements `json:"security,omitempty"`
 	ExternalDocs *ExternalDocs        `json:"externalDocs,omitempty"`
+
+	operationIndex *operationIndex
 }
 
 func (swagger *Swagger) MarshalJSON() ([]byte, error) {
@@ -37,6 +39,7 @@ func (swagger *Swagger) AddOperation(path string, method string, operation *Oper
 		paths[path] = pathItem
 	}
 	pathItem.SetOperation(method, operation)
+	swagger.operationIndex = nil
 }
 
 func (swagger *Swagger) AddServer(server *Server) {
@@ -67,5 +70,8 @@ func (swagger *Swagger) Validate(c context.Context) error {
 			return err
 		}
 	}
+	if err := swagger.validateLinks(); err != nil {
+		return err
+	}
 	return nil
 }
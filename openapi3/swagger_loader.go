@@ -9,8 +9,6 @@ import (
 	"net/url"
 	"path"
 	"strings"
-
-	"github.com/ghodss/yaml"
 )
 
 func foundUnresolvedRef(ref string) error {
@@ -26,10 +24,30 @@ func failedToResolveRefFragmentPart(value string, what string) error {
 }
 
 type SwaggerLoader struct {
-	IsExternalRefsAllowed  bool
+	IsExternalRefsAllowed bool
+
+	// Context is used for fetching external references.
+	// When the context is done, loading fails with the error of the context.
 	Context                context.Context
 	LoadSwaggerFromURIFunc func(loader *SwaggerLoader, url *url.URL) (*Swagger, error)
-	visited                map[interface{}]struct{}
+
+	// MergeAllOf replaces schemas of the document that have allOf with their effective schemas
+	// after references are resolved. See Schema.MergeAllOf.
+	MergeAllOf bool
+
+	// InternSchemas replaces structurally identical inline schemas of the document with shared instances
+	// after references are resolved, which reduces memory of large documents.
+	// Shared schemas must not be modified. See Stats.
+	InternSchemas bool
+
+	// RegexCompiler compiles patterns of schemas of loaded documents instead of DefaultRegexCompiler.
+	// See Swagger.CheckPatterns to find patterns that don't compile.
+	// It's set in the schemas, so schemas shared with other documents, like interned ones, use the last compiler.
+	// Use a RegexEngine to pick an engine per validation instead.
+	RegexCompiler RegexCompiler
+
+	visited map[interface{}]struct{}
+	stats   LoaderStats
 }
 
 func NewSwaggerLoader() *SwaggerLoader {
@@ -41,16 +59,27 @@ func (swaggerLoader *SwaggerLoader) LoadSwaggerFromURI(location *url.URL) (*Swag
 	if f != nil {
 		return f(swaggerLoader, location)
 	}
-	data, err := readUrl(location)
+	data, err := readUrl(swaggerLoader.context(), location)
 	if err != nil {
 		return nil, err
 	}
 	return swaggerLoader.LoadSwaggerFromDataWithPath(data, location)
 }
 
-func readUrl(location *url.URL) ([]byte, error) {
+func (swaggerLoader *SwaggerLoader) context() context.Context {
+	if c := swaggerLoader.Context; c != nil {
+		return c
+	}
+	return context.Background()
+}
+
+func readUrl(c context.Context, location *url.URL) ([]byte, error) {
 	if location.Scheme != "" && location.Host != "" {
-		resp, err := http.Get(location.String())
+		req, err := http.NewRequestWithContext(c, http.MethodGet, location.String(), nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := http.DefaultClient.Do(req)
 		if err != nil {
 			return nil, err
 		}
@@ -89,7 +118,7 @@ func (swaggerLoader *SwaggerLoader) LoadSwaggerFromFile(path string) (*Swagger,
 
 func (swaggerLoader *SwaggerLoader) LoadSwaggerFromData(data []byte) (*Swagger, error) {
 	swagger := &Swagger{}
-	if err := yaml.Unmarshal(data, swagger); err != nil {
+	if err := unmarshalYAML(data, swagger); err != nil {
 		return nil, err
 	}
 	return swagger, swaggerLoader.ResolveRefsIn(swagger, nil)
@@ -97,7 +126,7 @@ func (swaggerLoader *SwaggerLoader) LoadSwaggerFromData(data []byte) (*Swagger,
 
 func (swaggerLoader *SwaggerLoader) LoadSwaggerFromDataWithPath(data []byte, path *url.URL) (*Swagger, error) {
 	swagger := &Swagger{}
-	if err := yaml.Unmarshal(data, swagger); err != nil {
+	if err := unmarshalYAML(data, swagger); err != nil {
 		return nil, err
 	}
 	return swagger, swaggerLoader.ResolveRefsIn(swagger, path)
@@ -105,6 +134,7 @@ func (swaggerLoader *SwaggerLoader) LoadSwaggerFromDataWithPath(data []byte, pat
 
 func (swaggerLoader *SwaggerLoader) ResolveRefsIn(swagger *Swagger, path *url.URL) (err error) {
 	swaggerLoader.visited = make(map[interface{}]struct{})
+	swaggerLoader.stats = LoaderStats{}
 
 	// Visit all components
 	components := swagger.Components
@@ -173,9 +203,53 @@ func (swaggerLoader *SwaggerLoader) ResolveRefsIn(swagger *Swagger, path *url.UR
 		}
 	}
 
+	if swaggerLoader.MergeAllOf {
+		if err = mergeAllOfIn(swagger); err != nil {
+			return
+		}
+	}
+	if swaggerLoader.InternSchemas {
+		if swaggerLoader.stats, err = internSchemasIn(swagger); err != nil {
+			return
+		}
+	}
+	if compiler := swaggerLoader.RegexCompiler; compiler != nil {
+		setRegexCompilerIn(swagger, compiler)
+	}
+	swagger.IndexOperations()
 	return
 }
 
+// mergeAllOfIn replaces schemas of the document that have allOf with their effective schemas.
+func mergeAllOfIn(swagger *Swagger) error {
+	var (
+		pointers []string
+		schemas  []*Schema
+	)
+	v := newDocumentVisitor()
+	v.visitSchema = func(pointer string, schema *Schema) {
+		if len(schema.AllOf) > 0 {
+			pointers = append(pointers, pointer)
+			schemas = append(schemas, schema)
+		}
+	}
+	v.visit(swagger)
+
+	// Schemas are replaced after all of them are merged, so merging doesn't depend on the order.
+	merged := make([]*Schema, len(schemas))
+	for i, schema := range schemas {
+		var err error
+		if merged[i], err = schema.MergeAllOf(); err != nil {
+			return fmt.Errorf("Schema '%s' can't be merged: %v", pointers[i], err)
+		}
+	}
+	for i, schema := range schemas {
+		*schema = *merged[i]
+		schema.ResetCompiled()
+	}
+	return nil
+}
+
 func copyURL(basePath *url.URL) (*url.URL, error) {
 	return url.Parse(basePath.String())
 }
@@ -210,6 +284,9 @@ func (swaggerLoader *SwaggerLoader) resolveComponent(swagger *Swagger, ref strin
 		if !swaggerLoader.IsExternalRefsAllowed {
 			return nil, "", nil, fmt.Errorf("Encountered non-allowed external reference: '%s'", ref)
 		}
+		if err := swaggerLoader.context().Err(); err != nil {
+			return nil, "", nil, err
+		}
 		parsedURL, err := url.Parse(ref)
 		if err != nil {
 			return nil, "", nil, fmt.Errorf("Can't parse reference: '%s': %v", ref, parsedURL)
@@ -1,6 +1,7 @@
 package openapi3_test
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"net/http"
@@ -423,3 +424,14 @@ func TestLoadYamlFileWithExternalSchemaRef(t *testing.T) {
 
 	require.NotNil(t, swagger.Components.Schemas["AnotherTestSchema"].Value.Type)
 }
+
+func TestLoadWithCanceledContext(t *testing.T) {
+	c, cancel := context.WithCancel(context.Background())
+	cancel()
+	loader := openapi3.NewSwaggerLoader()
+	loader.IsExternalRefsAllowed = true
+	loader.Context = c
+	_, err := loader.LoadSwaggerFromFile("testdata/testref.openapi.json")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), context.Canceled.Error())
+}
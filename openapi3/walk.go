@@ -0,0 +1,384 @@
+package openapi3
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SkipChildren is returned by functions of a Visitor to skip objects that are nested in the object.
+var SkipChildren = errors.New("skip children")
+
+// Visitor contains functions that Walk calls with objects of a document and their JSON pointers,
+// like "#/paths/~1pets/get/responses/200". Nil functions aren't called.
+//
+// Functions may modify objects, including values of references.
+// A function returns SkipChildren to skip objects nested in the object, or another error to stop walking.
+type Visitor struct {
+	PathItem       func(pointer string, pathItem *PathItem) error
+	Operation      func(pointer string, method string, operation *Operation) error
+	Parameter      func(pointer string, ref *ParameterRef) error
+	RequestBody    func(pointer string, ref *RequestBodyRef) error
+	Response       func(pointer string, ref *ResponseRef) error
+	Header         func(pointer string, ref *HeaderRef) error
+	MediaType      func(pointer string, mediaType *MediaType) error
+	Schema         func(pointer string, ref *SchemaRef) error
+	Example        func(pointer string, ref *ExampleRef) error
+	Link           func(pointer string, ref *LinkRef) error
+	Callback       func(pointer string, ref *CallbackRef) error
+	SecurityScheme func(pointer string, ref *SecuritySchemeRef) error
+}
+
+// Walk visits paths and components of the document in a stable order, including callbacks, headers, and encodings.
+// The function returns the first error of a function of the visitor, except for SkipChildren.
+//
+// References like {"$ref": "#/components/schemas/Pet"} are visited, but their values aren't,
+// because values of references are visited as components.
+// Inline schemas that several locations share are visited at every location.
+func Walk(swagger *Swagger, visitor *Visitor) error {
+	w := &walker{visitor: visitor}
+	for _, path := range sortedKeys(swagger.Paths) {
+		if err := w.pathItem("#/paths/"+escapeJSONPointer(path), swagger.Paths[path]); err != nil {
+			return err
+		}
+	}
+	components := swagger.Components
+	for _, name := range sortedKeys(components.Schemas) {
+		if err := w.schema("#/components/schemas/"+escapeJSONPointer(name), components.Schemas[name]); err != nil {
+			return err
+		}
+	}
+	for _, name := range sortedKeys(components.Parameters) {
+		if err := w.parameter("#/components/parameters/"+escapeJSONPointer(name), components.Parameters[name]); err != nil {
+			return err
+		}
+	}
+	if err := w.headers("#/components/headers", components.Headers); err != nil {
+		return err
+	}
+	for _, name := range sortedKeys(components.RequestBodies) {
+		if err := w.requestBody("#/components/requestBodies/"+escapeJSONPointer(name), components.RequestBodies[name]); err != nil {
+			return err
+		}
+	}
+	for _, name := range sortedKeys(components.Responses) {
+		if err := w.response("#/components/responses/"+escapeJSONPointer(name), components.Responses[name]); err != nil {
+			return err
+		}
+	}
+	for _, name := range sortedKeys(components.SecuritySchemes) {
+		ref := components.SecuritySchemes[name]
+		if f := visitor.SecurityScheme; f != nil && ref != nil {
+			if _, err := skip(f("#/components/securitySchemes/"+escapeJSONPointer(name), ref)); err != nil {
+				return err
+			}
+		}
+	}
+	if err := w.examples("#/components/examples", components.Examples); err != nil {
+		return err
+	}
+	if err := w.links("#/components/links", components.Links); err != nil {
+		return err
+	}
+	for _, name := range sortedKeys(components.Callbacks) {
+		if err := w.callback("#/components/callbacks/"+escapeJSONPointer(name), components.Callbacks[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type walker struct {
+	visitor *Visitor
+}
+
+// skip returns true if objects nested in the object must be skipped.
+func skip(err error) (bool, error) {
+	if err == SkipChildren {
+		return true, nil
+	}
+	return err != nil, err
+}
+
+func (w *walker) pathItem(pointer string, pathItem *PathItem) error {
+	if pathItem == nil {
+		return nil
+	}
+	if f := w.visitor.PathItem; f != nil {
+		if skipped, err := skip(f(pointer, pathItem)); skipped {
+			return err
+		}
+	}
+	if err := w.parameters(pointer+"/parameters", pathItem.Parameters); err != nil {
+		return err
+	}
+	operations := pathItem.Operations()
+	for _, method := range sortedKeys(operations) {
+		if err := w.operation(pointer+"/"+strings.ToLower(method), method, operations[method]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *walker) operation(pointer string, method string, operation *Operation) error {
+	if f := w.visitor.Operation; f != nil {
+		if skipped, err := skip(f(pointer, method, operation)); skipped {
+			return err
+		}
+	}
+	if err := w.parameters(pointer+"/parameters", operation.Parameters); err != nil {
+		return err
+	}
+	if err := w.requestBody(pointer+"/requestBody", operation.RequestBody); err != nil {
+		return err
+	}
+	for _, status := range sortedKeys(operation.Responses) {
+		if err := w.response(pointer+"/responses/"+escapeJSONPointer(status), operation.Responses[status]); err != nil {
+			return err
+		}
+	}
+	for _, name := range sortedKeys(operation.Callbacks) {
+		if err := w.callback(pointer+"/callbacks/"+escapeJSONPointer(name), operation.Callbacks[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *walker) callback(pointer string, ref *CallbackRef) error {
+	if ref == nil {
+		return nil
+	}
+	if f := w.visitor.Callback; f != nil {
+		if skipped, err := skip(f(pointer, ref)); skipped {
+			return err
+		}
+	}
+	if ref.Ref != "" || ref.Value == nil {
+		return nil
+	}
+	callback := *ref.Value
+	for _, expression := range sortedKeys(callback) {
+		if err := w.pathItem(pointer+"/"+escapeJSONPointer(expression), callback[expression]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *walker) parameters(pointer string, parameters Parameters) error {
+	for i, ref := range parameters {
+		if err := w.parameter(pointer+"/"+strconv.Itoa(i), ref); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *walker) parameter(pointer string, ref *ParameterRef) error {
+	if ref == nil {
+		return nil
+	}
+	if f := w.visitor.Parameter; f != nil {
+		if skipped, err := skip(f(pointer, ref)); skipped {
+			return err
+		}
+	}
+	if ref.Ref != "" || ref.Value == nil {
+		return nil
+	}
+	parameter := ref.Value
+	if err := w.schema(pointer+"/schema", parameter.Schema); err != nil {
+		return err
+	}
+	if err := w.examples(pointer+"/examples", parameter.Examples); err != nil {
+		return err
+	}
+	return w.content(pointer+"/content", parameter.Content)
+}
+
+func (w *walker) requestBody(pointer string, ref *RequestBodyRef) error {
+	if ref == nil {
+		return nil
+	}
+	if f := w.visitor.RequestBody; f != nil {
+		if skipped, err := skip(f(pointer, ref)); skipped {
+			return err
+		}
+	}
+	if ref.Ref != "" || ref.Value == nil {
+		return nil
+	}
+	return w.content(pointer+"/content", ref.Value.Content)
+}
+
+func (w *walker) response(pointer string, ref *ResponseRef) error {
+	if ref == nil {
+		return nil
+	}
+	if f := w.visitor.Response; f != nil {
+		if skipped, err := skip(f(pointer, ref)); skipped {
+			return err
+		}
+	}
+	if ref.Ref != "" || ref.Value == nil {
+		return nil
+	}
+	response := ref.Value
+	if err := w.headers(pointer+"/headers", response.Headers); err != nil {
+		return err
+	}
+	if err := w.content(pointer+"/content", response.Content); err != nil {
+		return err
+	}
+	return w.links(pointer+"/links", response.Links)
+}
+
+func (w *walker) headers(pointer string, headers map[string]*HeaderRef) error {
+	for _, name := range sortedKeys(headers) {
+		if err := w.header(pointer+"/"+escapeJSONPointer(name), headers[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *walker) header(pointer string, ref *HeaderRef) error {
+	if ref == nil {
+		return nil
+	}
+	if f := w.visitor.Header; f != nil {
+		if skipped, err := skip(f(pointer, ref)); skipped {
+			return err
+		}
+	}
+	if ref.Ref != "" || ref.Value == nil {
+		return nil
+	}
+	if err := w.schema(pointer+"/schema", ref.Value.Schema); err != nil {
+		return err
+	}
+	return w.examples(pointer+"/examples", ref.Value.Examples)
+}
+
+func (w *walker) content(pointer string, content Content) error {
+	for _, mime := range sortedKeys(content) {
+		mediaType := content[mime]
+		if mediaType == nil {
+			continue
+		}
+		pointer := pointer + "/" + escapeJSONPointer(mime)
+		if f := w.visitor.MediaType; f != nil {
+			if skipped, err := skip(f(pointer, mediaType)); skipped {
+				if err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		if err := w.schema(pointer+"/schema", mediaType.Schema); err != nil {
+			return err
+		}
+		if err := w.examples(pointer+"/examples", mediaType.Examples); err != nil {
+			return err
+		}
+		for _, name := range sortedKeys(mediaType.Encoding) {
+			if encoding := mediaType.Encoding[name]; encoding != nil {
+				if err := w.headers(pointer+"/encoding/"+escapeJSONPointer(name)+"/headers", encoding.Headers); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (w *walker) examples(pointer string, examples map[string]*ExampleRef) error {
+	f := w.visitor.Example
+	if f == nil {
+		return nil
+	}
+	for _, name := range sortedKeys(examples) {
+		if ref := examples[name]; ref != nil {
+			if _, err := skip(f(pointer+"/"+escapeJSONPointer(name), ref)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (w *walker) links(pointer string, links map[string]*LinkRef) error {
+	f := w.visitor.Link
+	if f == nil {
+		return nil
+	}
+	for _, name := range sortedKeys(links) {
+		if ref := links[name]; ref != nil {
+			if _, err := skip(f(pointer+"/"+escapeJSONPointer(name), ref)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (w *walker) schema(pointer string, ref *SchemaRef) error {
+	if ref == nil {
+		return nil
+	}
+	if f := w.visitor.Schema; f != nil {
+		if skipped, err := skip(f(pointer, ref)); skipped {
+			return err
+		}
+	}
+	if ref.Ref != "" || ref.Value == nil {
+		return nil
+	}
+	schema := ref.Value
+	for i, item := range schema.OneOf {
+		if err := w.schema(pointer+"/oneOf/"+strconv.Itoa(i), item); err != nil {
+			return err
+		}
+	}
+	for i, item := range schema.AnyOf {
+		if err := w.schema(pointer+"/anyOf/"+strconv.Itoa(i), item); err != nil {
+			return err
+		}
+	}
+	for i, item := range schema.AllOf {
+		if err := w.schema(pointer+"/allOf/"+strconv.Itoa(i), item); err != nil {
+			return err
+		}
+	}
+	if err := w.schema(pointer+"/not", schema.Not); err != nil {
+		return err
+	}
+	if err := w.schema(pointer+"/items", schema.Items); err != nil {
+		return err
+	}
+	for _, name := range sortedKeys(schema.Properties) {
+		if err := w.schema(pointer+"/properties/"+escapeJSONPointer(name), schema.Properties[name]); err != nil {
+			return err
+		}
+	}
+	return w.schema(pointer+"/additionalProperties", schema.AdditionalProperties)
+}
+
+func escapeJSONPointer(token string) string {
+	return strings.Replace(strings.Replace(token, "~", "~0", -1), "/", "~1", -1)
+}
+
+// sortedKeys returns sorted keys of a map with string keys.
+func sortedKeys(m interface{}) []string {
+	keys := reflect.ValueOf(m).MapKeys()
+	result := make([]string, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, key.String())
+	}
+	sort.Strings(result)
+	return result
+}
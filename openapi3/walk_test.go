@@ -0,0 +1,123 @@
+package openapi3_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+)
+
+const walkSpec = `
+openapi: 3.0.0
+info:
+  title: Pets
+  version: "1.0"
+paths:
+  /pets:
+    post:
+      parameters:
+        - $ref: "#/components/parameters/Limit"
+      requestBody:
+        content:
+          multipart/form-data:
+            schema:
+              type: object
+              properties:
+                photo:
+                  type: string
+            encoding:
+              photo:
+                headers:
+                  X-Rate:
+                    schema:
+                      type: integer
+      responses:
+        "201":
+          description: created
+          headers:
+            Location:
+              schema:
+                type: string
+      callbacks:
+        created:
+          "{$request.body#/url}":
+            post:
+              responses:
+                "200":
+                  description: ok
+components:
+  parameters:
+    Limit:
+      name: limit
+      in: query
+      schema:
+        type: integer
+`
+
+func TestWalk(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(walkSpec))
+	require.NoError(t, err)
+
+	var pointers []string
+	record := func(pointer string) { pointers = append(pointers, pointer) }
+	visitor := &openapi3.Visitor{
+		Operation: func(pointer string, method string, operation *openapi3.Operation) error {
+			record(pointer)
+			return nil
+		},
+		Parameter: func(pointer string, ref *openapi3.ParameterRef) error {
+			record(pointer)
+			return nil
+		},
+		Header: func(pointer string, ref *openapi3.HeaderRef) error {
+			record(pointer)
+			return nil
+		},
+		Schema: func(pointer string, ref *openapi3.SchemaRef) error {
+			record(pointer)
+			ref.Value.Description = "visited"
+			return nil
+		},
+	}
+	require.NoError(t, openapi3.Walk(swagger, visitor))
+	require.Equal(t, []string{
+		"#/paths/~1pets/post",
+		"#/paths/~1pets/post/parameters/0",
+		"#/paths/~1pets/post/requestBody/content/multipart~1form-data/schema",
+		"#/paths/~1pets/post/requestBody/content/multipart~1form-data/schema/properties/photo",
+		"#/paths/~1pets/post/requestBody/content/multipart~1form-data/encoding/photo/headers/X-Rate",
+		"#/paths/~1pets/post/requestBody/content/multipart~1form-data/encoding/photo/headers/X-Rate/schema",
+		"#/paths/~1pets/post/responses/201/headers/Location",
+		"#/paths/~1pets/post/responses/201/headers/Location/schema",
+		"#/paths/~1pets/post/callbacks/created/{$request.body#~1url}/post",
+		"#/components/parameters/Limit",
+		"#/components/parameters/Limit/schema",
+	}, pointers)
+	require.Equal(t, "visited", swagger.Components.Parameters["Limit"].Value.Schema.Value.Description)
+
+	// Skipped objects and errors stop walking of nested objects and the document.
+	pointers = nil
+	visitor.Header = func(pointer string, ref *openapi3.HeaderRef) error {
+		record(pointer)
+		return openapi3.SkipChildren
+	}
+	stop := errors.New("stop")
+	visitor.Operation = func(pointer string, method string, operation *openapi3.Operation) error {
+		record(pointer)
+		if len(operation.Callbacks) == 0 {
+			return stop
+		}
+		return nil
+	}
+	require.Equal(t, stop, openapi3.Walk(swagger, visitor))
+	require.Equal(t, []string{
+		"#/paths/~1pets/post",
+		"#/paths/~1pets/post/parameters/0",
+		"#/paths/~1pets/post/requestBody/content/multipart~1form-data/schema",
+		"#/paths/~1pets/post/requestBody/content/multipart~1form-data/schema/properties/photo",
+		"#/paths/~1pets/post/requestBody/content/multipart~1form-data/encoding/photo/headers/X-Rate",
+		"#/paths/~1pets/post/responses/201/headers/Location",
+		"#/paths/~1pets/post/callbacks/created/{$request.body#~1url}/post",
+	}, pointers)
+}
@@ -0,0 +1,101 @@
+package openapi3
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"gopkg.in/yaml.v2"
+)
+
+// unmarshalYAML decodes a document in YAML or JSON like yaml.Unmarshal of github.com/ghodss/yaml,
+// but keeps the order of keys of mappings, so the declaration order of properties of schemas is known.
+func unmarshalYAML(data []byte, v interface{}) error {
+	// Mappings nested in yaml.MapSlice are decoded as yaml.MapSlice too.
+	var value yaml.MapSlice
+	if err := yaml.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("error converting YAML to JSON: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := writeYAMLAsJSON(&buf, value); err != nil {
+		return fmt.Errorf("error converting YAML to JSON: %v", err)
+	}
+	if err := json.Unmarshal(buf.Bytes(), v); err != nil {
+		return fmt.Errorf("error unmarshaling JSON: %v", err)
+	}
+	return nil
+}
+
+func writeYAMLAsJSON(buf *bytes.Buffer, value interface{}) error {
+	switch value := value.(type) {
+	case yaml.MapSlice:
+		buf.WriteByte('{')
+		for i, item := range value {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			key, err := yamlKeyString(item.Key)
+			if err != nil {
+				return err
+			}
+			data, err := json.Marshal(key)
+			if err != nil {
+				return err
+			}
+			buf.Write(data)
+			buf.WriteByte(':')
+			if err := writeYAMLAsJSON(buf, item.Value); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	case []interface{}:
+		buf.WriteByte('[')
+		for i, item := range value {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeYAMLAsJSON(buf, item); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	default:
+		data, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		buf.Write(data)
+	}
+	return nil
+}
+
+// yamlKeyString converts a key of a YAML mapping to a string like github.com/ghodss/yaml.
+func yamlKeyString(key interface{}) (string, error) {
+	switch key := key.(type) {
+	case string:
+		return key, nil
+	case int:
+		return strconv.Itoa(key), nil
+	case int64:
+		return strconv.FormatInt(key, 10), nil
+	case uint64:
+		return strconv.FormatUint(key, 10), nil
+	case float64:
+		s := strconv.FormatFloat(key, 'g', -1, 32)
+		switch s {
+		case "+Inf":
+			s = ".inf"
+		case "-Inf":
+			s = "-.inf"
+		case "NaN":
+			s = ".nan"
+		}
+		return s, nil
+	case bool:
+		return strconv.FormatBool(key), nil
+	default:
+		return "", fmt.Errorf("Unsupported map key of type: %T, key: %+#v", key, key)
+	}
+}
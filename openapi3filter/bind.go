@@ -0,0 +1,167 @@
+package openapi3filter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// BindRequest populates fields of a struct with decoded values of parameters and the body of a request.
+// The request should be validated with ValidateRequest first.
+//
+// Fields are bound by tags:
+//
+//	type GetItemInput struct {
+//		ID      int64     `path:"id"`
+//		Limit   *int      `query:"limit"`
+//		Request string    `header:"X-Request-ID"`
+//		Session string    `cookie:"session"`
+//		Body    *ItemBody `body:""`
+//	}
+//
+// Values are converted to types of fields like values of JSON, so fields may be any type
+// that encoding/json can decode, including pointers, slices, structs, and encoding.TextUnmarshaler.
+// Fields of absent parameters are left unchanged.
+//
+// The function returns RequestError when the request has a value that can't be decoded.
+func BindRequest(input *RequestValidationInput, target interface{}) error {
+	route := input.Route
+	if route == nil || route.Operation == nil {
+		return errRouteMissingOperation
+	}
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("Binding target must be a non-nil pointer to a struct, not %T", target)
+	}
+	return bindStruct(input, v.Elem())
+}
+
+var bindParameterTags = []string{
+	openapi3.ParameterInPath,
+	openapi3.ParameterInQuery,
+	openapi3.ParameterInHeader,
+	openapi3.ParameterInCookie,
+}
+
+func bindStruct(input *RequestValidationInput, v reflect.Value) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		fieldValue := v.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			// Exported fields of an embedded struct are settable even if the struct is unexported.
+			if err := bindStruct(input, fieldValue); err != nil {
+				return err
+			}
+			continue
+		}
+		if field.PkgPath != "" {
+			// An unexported field
+			continue
+		}
+		if _, ok := field.Tag.Lookup("body"); ok {
+			if err := bindBody(input, field, fieldValue); err != nil {
+				return err
+			}
+			continue
+		}
+		for _, in := range bindParameterTags {
+			name, ok := field.Tag.Lookup(in)
+			if !ok {
+				continue
+			}
+			if err := bindParameter(input, in, name, field, fieldValue); err != nil {
+				return err
+			}
+			break
+		}
+	}
+	return nil
+}
+
+func bindParameter(input *RequestValidationInput, in string, name string, field reflect.StructField, fieldValue reflect.Value) error {
+	parameter := findBindParameter(input.Route, in, name)
+	if parameter == nil {
+		return fmt.Errorf("Field '%s' refers to %s parameter '%s' that is not declared", field.Name, in, name)
+	}
+	if schema := parameterSchema(parameter); schema == nil || schema.Value == nil {
+		return fmt.Errorf("Field '%s' refers to %s parameter '%s' that doesn't have a schema", field.Name, in, name)
+	}
+	value, err := decodeParameter(parameter, input)
+	if err != nil {
+		return &RequestError{Input: input, Parameter: parameter, Err: err}
+	}
+	if value == nil {
+		return nil
+	}
+	if err := bindValue(fieldValue, value); err != nil {
+		return fmt.Errorf("Parameter '%s' in %s can't be bound to field '%s': %v", parameter.Name, in, field.Name, err)
+	}
+	return nil
+}
+
+func findBindParameter(route *Route, in string, name string) *openapi3.Parameter {
+	for _, parameter := range routeParameters(route) {
+		if parameter.In != in {
+			continue
+		}
+		if parameter.Name == name || (in == openapi3.ParameterInHeader && strings.EqualFold(parameter.Name, name)) {
+			return parameter
+		}
+	}
+	return nil
+}
+
+func bindBody(input *RequestValidationInput, field reflect.StructField, fieldValue reflect.Value) error {
+	req := input.Request
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil
+	}
+	data, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return &RequestError{Input: input, Reason: "reading failed", Err: err}
+	}
+	// Put the data back into the input
+	req.Body = ioutil.NopCloser(bytes.NewReader(data))
+	if len(data) == 0 {
+		return nil
+	}
+	var requestBody *openapi3.RequestBody
+	var content openapi3.Content
+	if ref := input.Route.Operation.RequestBody; ref != nil && ref.Value != nil {
+		requestBody = ref.Value
+		content = requestBody.Content
+	}
+	// The body is decoded like by ValidateRequestBody.
+	inputMIME := req.Header.Get("Content-Type")
+	mediaType := requestMediaType(inputMIME, content)
+	value, err := decodeRequestBody(req.Context(), input, requestBody, findMediaType(content, mediaType), inputMIME, mediaType, data)
+	if err != nil {
+		return err
+	}
+	if err := bindValue(fieldValue, value); err != nil {
+		return fmt.Errorf("Request body can't be bound to field '%s': %v", field.Name, err)
+	}
+	return nil
+}
+
+// bindValue converts a decoded value to the type of the field through JSON.
+func bindValue(fieldValue reflect.Value, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	result := reflect.New(fieldValue.Type())
+	if err := json.Unmarshal(data, result.Interface()); err != nil {
+		return err
+	}
+	fieldValue.Set(result.Elem())
+	return nil
+}
@@ -0,0 +1,197 @@
+package openapi3filter_test
+
+import (
+	"bytes"
+	"context"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/stretchr/testify/require"
+)
+
+const bindSpec = `
+openapi: 3.0.0
+info:
+  title: Items
+  version: "1.0"
+paths:
+  /items/{id}:
+    parameters:
+      - name: id
+        in: path
+        required: true
+        schema:
+          type: integer
+    put:
+      parameters:
+        - name: tags
+          in: query
+          schema:
+            type: array
+            items:
+              type: string
+        - name: since
+          in: query
+          schema:
+            type: string
+            format: date-time
+        - name: limit
+          in: query
+          schema:
+            type: integer
+        - name: X-Request-ID
+          in: header
+          schema:
+            type: string
+        - name: session
+          in: cookie
+          schema:
+            type: string
+        - name: filter
+          in: query
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  color:
+                    type: string
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                name:
+                  type: string
+                price:
+                  type: number
+      responses:
+        "200":
+          description: item
+  /uploads:
+    post:
+      requestBody:
+        content:
+          multipart/form-data:
+            schema:
+              type: object
+              properties:
+                name:
+                  type: string
+                count:
+                  type: integer
+      responses:
+        "200":
+          description: uploaded
+`
+
+type bindItemBody struct {
+	Name  string  `json:"name"`
+	Price float64 `json:"price"`
+}
+
+type bindTracing struct {
+	RequestID string `header:"x-request-id"`
+}
+
+type bindItemInput struct {
+	bindTracing
+	ID      int64         `path:"id"`
+	Tags    []string      `query:"tags"`
+	Since   time.Time     `query:"since"`
+	Limit   *int          `query:"limit"`
+	Session string        `cookie:"session"`
+	Body    *bindItemBody `body:""`
+	ignored string
+}
+
+func TestBindRequest(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(bindSpec))
+	require.NoError(t, err)
+	router := openapi3filter.NewRouter().WithSwagger(swagger)
+
+	newInput := func(req *http.Request) *openapi3filter.RequestValidationInput {
+		route, pathParams, err := router.FindRoute(req.Method, req.URL)
+		require.NoError(t, err)
+		input := &openapi3filter.RequestValidationInput{
+			Request:    req,
+			PathParams: pathParams,
+			Route:      route,
+		}
+		require.NoError(t, openapi3filter.ValidateRequest(context.Background(), input))
+		return input
+	}
+
+	req := httptest.NewRequest(http.MethodPut, "/items/42?tags=a&tags=b&since=2020-01-02T03:04:05Z", strings.NewReader(`{"name":"pen","price":1.5}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Request-ID", "abc")
+	req.AddCookie(&http.Cookie{Name: "session", Value: "s1"})
+	input := bindItemInput{ignored: "unchanged"}
+	require.NoError(t, openapi3filter.BindRequest(newInput(req), &input))
+	require.Equal(t, bindItemInput{
+		bindTracing: bindTracing{RequestID: "abc"},
+		ID:          42,
+		Tags:        []string{"a", "b"},
+		Since:       time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+		Session:     "s1",
+		Body:        &bindItemBody{Name: "pen", Price: 1.5},
+		ignored:     "unchanged",
+	}, input)
+
+	req = httptest.NewRequest(http.MethodPut, "/items/1?limit=10", nil)
+	input = bindItemInput{}
+	require.NoError(t, openapi3filter.BindRequest(newInput(req), &input))
+	require.NotNil(t, input.Limit)
+	require.Equal(t, 10, *input.Limit)
+	require.Nil(t, input.Body)
+
+	var undeclared struct {
+		Page int `query:"page"`
+	}
+	err = openapi3filter.BindRequest(newInput(req), &undeclared)
+	require.EqualError(t, err, "Field 'Page' refers to query parameter 'page' that is not declared")
+
+	var mismatched struct {
+		ID bool `path:"id"`
+	}
+	err = openapi3filter.BindRequest(newInput(req), &mismatched)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Parameter 'id' in path can't be bound to field 'ID'")
+
+	err = openapi3filter.BindRequest(newInput(req), input)
+	require.EqualError(t, err, "Binding target must be a non-nil pointer to a struct, not openapi3filter_test.bindItemInput")
+
+	req = httptest.NewRequest(http.MethodPut, "/items/1?filter="+url.QueryEscape(`{"color":"red"}`), nil)
+	var filtered struct {
+		Filter struct {
+			Color string `json:"color"`
+		} `query:"filter"`
+	}
+	require.NoError(t, openapi3filter.BindRequest(newInput(req), &filtered))
+	require.Equal(t, "red", filtered.Filter.Color)
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	require.NoError(t, writer.WriteField("name", "report"))
+	require.NoError(t, writer.WriteField("count", "3"))
+	require.NoError(t, writer.Close())
+	req = httptest.NewRequest(http.MethodPost, "/uploads", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	var upload struct {
+		Body struct {
+			Name  string `json:"name"`
+			Count int    `json:"count"`
+		} `body:""`
+	}
+	require.NoError(t, openapi3filter.BindRequest(newInput(req), &upload))
+	require.Equal(t, "report", upload.Body.Name)
+	require.Equal(t, 3, upload.Body.Count)
+}
@@ -0,0 +1,119 @@
+package openapi3filter
+
+import (
+	"fmt"
+	"strings"
+)
+
+// isJSONMediaType returns true if bodies of the media type are JSON, like "application/json" or "application/problem+json".
+func isJSONMediaType(mediaType string) bool {
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+func (options *Options) hasBodyLimits() bool {
+	return options.MaxBodyDepth > 0 || options.MaxBodyArrayLength > 0 || options.MaxBodyObjectProperties > 0
+}
+
+// checkJSONBodyLimits scans JSON without decoding it, and returns a ParseError if it exceeds a limit.
+// Invalid JSON is left to the decoder.
+func (options *Options) checkJSONBodyLimits(data []byte) error {
+	if !options.hasBodyLimits() {
+		return nil
+	}
+	type container struct {
+		object bool
+		items  int
+	}
+	var stack []container
+	for i := 0; i < len(data); i++ {
+		switch c := data[i]; c {
+		case '"':
+			// Skip the string, including escaped quotes.
+			for i++; i < len(data) && data[i] != '"'; i++ {
+				if data[i] == '\\' {
+					i++
+				}
+			}
+			if len(stack) > 0 && stack[len(stack)-1].items == 0 {
+				stack[len(stack)-1].items = 1
+			}
+		case '[', '{':
+			if len(stack) > 0 && stack[len(stack)-1].items == 0 {
+				stack[len(stack)-1].items = 1
+			}
+			if err := options.checkBodyDepth(len(stack) + 1); err != nil {
+				return err
+			}
+			stack = append(stack, container{object: c == '{'})
+		case ']', '}':
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		case ',':
+			if len(stack) > 0 {
+				top := &stack[len(stack)-1]
+				top.items++
+				if err := options.checkBodyLength(top.object, top.items); err != nil {
+					return err
+				}
+			}
+		case ' ', '\t', '\r', '\n', ':':
+		default:
+			if len(stack) > 0 && stack[len(stack)-1].items == 0 {
+				stack[len(stack)-1].items = 1
+			}
+		}
+	}
+	return nil
+}
+
+// checkBodyValueLimits returns a ParseError if a decoded value exceeds a limit.
+func (options *Options) checkBodyValueLimits(value interface{}, depth int) error {
+	if !options.hasBodyLimits() {
+		return nil
+	}
+	switch value := value.(type) {
+	case []interface{}:
+		if err := options.checkBodyDepth(depth + 1); err != nil {
+			return err
+		}
+		if err := options.checkBodyLength(false, len(value)); err != nil {
+			return err
+		}
+		for i, item := range value {
+			if err := options.checkBodyValueLimits(item, depth+1); err != nil {
+				return wrapParseError(i, err)
+			}
+		}
+	case map[string]interface{}:
+		if err := options.checkBodyDepth(depth + 1); err != nil {
+			return err
+		}
+		if err := options.checkBodyLength(true, len(value)); err != nil {
+			return err
+		}
+		for name, property := range value {
+			if err := options.checkBodyValueLimits(property, depth+1); err != nil {
+				return wrapParseError(name, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (options *Options) checkBodyDepth(depth int) error {
+	if max := options.MaxBodyDepth; max > 0 && depth > max {
+		return &ParseError{Kind: KindLimitExceeded, Reason: fmt.Sprintf("nesting depth exceeds %d", max)}
+	}
+	return nil
+}
+
+func (options *Options) checkBodyLength(object bool, n int) error {
+	if max := options.MaxBodyObjectProperties; object && max > 0 && n > max {
+		return &ParseError{Kind: KindLimitExceeded, Reason: fmt.Sprintf("object has more than %d properties", max)}
+	}
+	if max := options.MaxBodyArrayLength; !object && max > 0 && n > max {
+		return &ParseError{Kind: KindLimitExceeded, Reason: fmt.Sprintf("array has more than %d items", max)}
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package openapi3filter_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBodyLimits(t *testing.T) {
+	requestBody := openapi3.NewRequestBody().WithContent(openapi3.Content{
+		"application/json":     openapi3.NewMediaType().WithSchema(openapi3.NewSchema()),
+		"application/x-ndjson": openapi3.NewMediaType().WithSchema(openapi3.NewSchema()),
+	})
+	options := &openapi3filter.Options{
+		MaxBodyDepth:            3,
+		MaxBodyArrayLength:      3,
+		MaxBodyObjectProperties: 2,
+	}
+	for _, test := range []struct {
+		contentType string
+		body        string
+		err         string
+	}{
+		{"application/json", `{"a":[1,2,3],"b":{"c":[]}}`, ""},
+		{"application/json", `[[[[]]]]`, "nesting depth exceeds 3"},
+		{"application/json", `[1,2,3,4]`, "array has more than 3 items"},
+		{"application/json", `{"a":1,"b":2,"c":3}`, "object has more than 2 properties"},
+		{"application/json", `{"a":"x,y,z","b":"[[[[\"]]]]"}`, ""},
+		{"application/json", `[[], [], []]`, ""},
+		{"application/x-ndjson", "{\"a\":1}\n{\"a\":1,\"b\":2,\"c\":3}\n", "object has more than 2 properties"},
+		{"application/x-ndjson", "1\n2\n3\n4\n", "array has more than 3 items"},
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/items", strings.NewReader(test.body))
+		req.Header.Set("Content-Type", test.contentType)
+		input := &openapi3filter.RequestValidationInput{Request: req, Options: options}
+		err := openapi3filter.ValidateRequestBody(context.Background(), input, requestBody)
+		if test.err == "" {
+			require.NoError(t, err, test.body)
+			continue
+		}
+		require.Error(t, err, test.body)
+		require.True(t, errors.Is(err, openapi3filter.KindLimitExceeded), test.body)
+		require.Contains(t, err.Error(), test.err, test.body)
+	}
+}
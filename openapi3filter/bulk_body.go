@@ -0,0 +1,151 @@
+package openapi3filter
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// BodyRecordError is an error of a record of a body in a format with many records,
+// like a row of text/csv or a line of application/x-ndjson.
+type BodyRecordError struct {
+	// Record is the number of the record, starting at 1.
+	// A header row of CSV is not a record.
+	Record int
+	Err    error
+}
+
+func (err *BodyRecordError) Error() string {
+	return fmt.Sprintf("record %d: %v", err.Record, err.Err)
+}
+
+func (err *BodyRecordError) Unwrap() error {
+	return err.Err
+}
+
+// csvBodyDecoder decodes rows of CSV to objects whose property names are in the header row.
+// Values are strings until they are converted by types of properties of the schema.
+func csvBodyDecoder(c context.Context, body []byte) (interface{}, error) {
+	r := csv.NewReader(bytes.NewReader(body))
+	header, err := r.Read()
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool, len(header))
+	for _, name := range header {
+		if seen[name] {
+			return nil, fmt.Errorf("Header row has duplicate column '%s'", name)
+		}
+		seen[name] = true
+	}
+	records := make([]interface{}, 0)
+	for {
+		row, err := r.Read()
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := c.Err(); err != nil {
+			return nil, err
+		}
+		record := make(map[string]interface{}, len(row))
+		for i, value := range row {
+			record[header[i]] = value
+		}
+		records = append(records, record)
+	}
+}
+
+// ndjsonBodyDecoder decodes every line of newline-delimited JSON.
+func ndjsonBodyDecoder(c context.Context, body []byte) (interface{}, error) {
+	lines := bytes.Split(bytes.TrimSuffix(body, []byte("\n")), []byte("\n"))
+	records := make([]interface{}, 0, len(lines))
+	for i, line := range lines {
+		if err := c.Err(); err != nil {
+			return nil, err
+		}
+		var value interface{}
+		if err := json.Unmarshal(bytes.TrimSuffix(line, []byte("\r")), &value); err != nil {
+			return nil, &BodyRecordError{Record: i + 1, Err: err}
+		}
+		records = append(records, value)
+	}
+	return records, nil
+}
+
+// isRecordsMediaType returns true if bodies of the media type are decoded to arrays of records.
+func isRecordsMediaType(mediaType string) bool {
+	return mediaType == "text/csv" || mediaType == "application/x-ndjson"
+}
+
+// recordSchema returns the schema of records of a body, which is either the items schema
+// of an array schema, or the schema itself.
+func recordSchema(schema *openapi3.Schema) *openapi3.Schema {
+	if schema.Type == "array" && schema.Items != nil && schema.Items.Value != nil {
+		return schema.Items.Value
+	}
+	return schema
+}
+
+// coerceBodyRecords converts values of CSV rows to types of properties of the record schema.
+// Empty values are removed, so they're validated as absent properties.
+func coerceBodyRecords(mediaType string, value interface{}, schema *openapi3.Schema) (interface{}, error) {
+	records, ok := value.([]interface{})
+	if mediaType != "text/csv" || !ok {
+		return value, nil
+	}
+	properties := recordSchema(schema).Properties
+	for i, record := range records {
+		record := record.(map[string]interface{})
+		for name, raw := range record {
+			if raw == "" {
+				delete(record, name)
+				continue
+			}
+			property := properties[name]
+			if property == nil || property.Value == nil {
+				continue
+			}
+			switch property.Value.Type {
+			case "integer", "number", "boolean":
+			default:
+				continue
+			}
+			v, err := parsePrimitive(raw.(string), property)
+			if err != nil {
+				return nil, &BodyRecordError{Record: i + 1, Err: wrapParseError(name, err)}
+			}
+			record[name] = v
+		}
+	}
+	return records, nil
+}
+
+// visitBodyValue validates a decoded body with the schema.
+// Opaque bodies are validated by their length only.
+// Records of a body are validated independently unless the schema is an array schema.
+func visitBodyValue(c context.Context, mediaType string, schema *openapi3.Schema, value interface{}) error {
+	if body, ok := value.(OpaqueBody); ok {
+		return validateOpaqueBody(schema, body)
+	}
+	records, ok := value.([]interface{})
+	if !ok || !isRecordsMediaType(mediaType) || schema.Type == "array" {
+		return schema.VisitJSONContext(c, value)
+	}
+	for i, record := range records {
+		if err := schema.VisitJSONContext(c, record); err != nil {
+			if c.Err() != nil {
+				return err
+			}
+			return &BodyRecordError{Record: i + 1, Err: err}
+		}
+	}
+	return nil
+}
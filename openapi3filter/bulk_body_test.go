@@ -0,0 +1,108 @@
+package openapi3filter_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/stretchr/testify/require"
+)
+
+const bulkBodySpec = `
+openapi: 3.0.0
+info:
+  title: Bulk
+  version: "1.0"
+components:
+  schemas:
+    Product:
+      type: object
+      required: [sku, price]
+      properties:
+        sku:
+          type: string
+        price:
+          type: number
+          minimum: 0
+        stock:
+          type: integer
+        active:
+          type: boolean
+paths:
+  /products/import:
+    post:
+      requestBody:
+        content:
+          text/csv:
+            schema:
+              type: array
+              items:
+                $ref: "#/components/schemas/Product"
+          application/x-ndjson:
+            schema:
+              $ref: "#/components/schemas/Product"
+      responses:
+        "204":
+          description: imported
+`
+
+func TestBulkBodies(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(bulkBodySpec))
+	require.NoError(t, err)
+	router := openapi3filter.NewRouter().WithSwagger(swagger)
+
+	validate := func(contentType string, body string) error {
+		req := httptest.NewRequest(http.MethodPost, "/products/import", strings.NewReader(body))
+		req.Header.Set("Content-Type", contentType)
+		route, pathParams, err := router.FindRoute(req.Method, req.URL)
+		require.NoError(t, err)
+		return openapi3filter.ValidateRequest(context.Background(), &openapi3filter.RequestValidationInput{
+			Request:    req,
+			PathParams: pathParams,
+			Route:      route,
+		})
+	}
+
+	t.Run("csv", func(t *testing.T) {
+		require.NoError(t, validate("text/csv", "sku,price,stock,active\na1,9.99,3,true\na2,0,,false\n"))
+
+		err := validate("text/csv", "sku,price\na1,9.99\na2,-1\n")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "doesn't match the schema")
+
+		err = validate("text/csv", "sku,price,stock\na1,9.99,3\na2,1,many\n")
+		var recordErr *openapi3filter.BodyRecordError
+		require.True(t, errors.As(err, &recordErr))
+		require.Equal(t, 2, recordErr.Record)
+		require.True(t, errors.Is(err, openapi3filter.KindInvalidInt))
+
+		err = validate("text/csv", "sku,price\na1\n")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to decode request body")
+
+		err = validate("text/csv", "sku,sku\na1,a2\n")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "duplicate column 'sku'")
+	})
+
+	t.Run("ndjson", func(t *testing.T) {
+		require.NoError(t, validate("application/x-ndjson", "{\"sku\":\"a1\",\"price\":1}\n{\"sku\":\"a2\",\"price\":2,\"stock\":5}\n"))
+
+		err := validate("application/x-ndjson", "{\"sku\":\"a1\",\"price\":1}\n{\"sku\":\"a2\"}\n")
+		var recordErr *openapi3filter.BodyRecordError
+		require.True(t, errors.As(err, &recordErr))
+		require.Equal(t, 2, recordErr.Record)
+		var schemaErr *openapi3.SchemaError
+		require.True(t, errors.As(err, &schemaErr))
+
+		err = validate("application/x-ndjson", "{\"sku\":\"a1\",\"price\":1}\n\n{\"sku\":\"a3\",\"price\":3}")
+		require.True(t, errors.As(err, &recordErr))
+		require.Equal(t, 2, recordErr.Record)
+		require.True(t, errors.Is(err, openapi3filter.KindInvalidFormat))
+	})
+}
@@ -0,0 +1,226 @@
+package openapi3filter
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+const (
+	headerCacheControl = "Cache-Control"
+	headerExpires      = "Expires"
+)
+
+// CacheControl contains directives of a header Cache-Control, such as "max-age" with value "60",
+// or "no-store" with an empty value.
+type CacheControl map[string]string
+
+// ParseCacheControl returns directives of a value of a header Cache-Control (RFC 7234).
+// Directives with seconds like "max-age" must have non-negative integers.
+func ParseCacheControl(value string) (CacheControl, error) {
+	directives := make(CacheControl)
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, arg := part, ""
+		if i := strings.IndexByte(part, '='); i >= 0 {
+			name, arg = strings.TrimSpace(part[:i]), strings.TrimSpace(part[i+1:])
+			if unquoted, err := strconv.Unquote(arg); err == nil && strings.HasPrefix(arg, `"`) {
+				arg = unquoted
+			} else if !isHTTPToken(arg) {
+				return nil, fmt.Errorf("Directive '%s' has invalid value %q", name, arg)
+			}
+		}
+		if !isHTTPToken(name) {
+			return nil, fmt.Errorf("Directive %q is invalid", name)
+		}
+		name = strings.ToLower(name)
+		switch name {
+		case "max-age", "s-maxage", "min-fresh", "stale-while-revalidate", "stale-if-error":
+			if _, err := strconv.ParseUint(arg, 10, 63); err != nil {
+				return nil, fmt.Errorf("Directive '%s' must have seconds, not %q", name, arg)
+			}
+		case "max-stale":
+			if _, err := strconv.ParseUint(arg, 10, 63); arg != "" && err != nil {
+				return nil, fmt.Errorf("Directive '%s' must have seconds, not %q", name, arg)
+			}
+		}
+		directives[name] = arg
+	}
+	if len(directives) == 0 {
+		return nil, fmt.Errorf("Header 'Cache-Control' doesn't have directives")
+	}
+	return directives, nil
+}
+
+// Has returns true if the directive is present.
+func (directives CacheControl) Has(name string) bool {
+	_, ok := directives[name]
+	return ok
+}
+
+// Seconds returns the seconds of a directive like "max-age".
+func (directives CacheControl) Seconds(name string) (int64, bool) {
+	n, err := strconv.ParseInt(directives[name], 10, 64)
+	return n, err == nil
+}
+
+// ResponseCaching describes headers Cache-Control and Expires that a response of an operation declares.
+type ResponseCaching struct {
+	Method    string
+	Path      string
+	Operation *openapi3.Operation
+
+	// Status is a status like "200" or "default".
+	Status string
+
+	DeclaresCacheControl bool
+	DeclaresExpires      bool
+
+	// CacheControl contains directives of the example, the default, or the only enum value of the header Cache-Control.
+	// It's nil if the declaration doesn't have such a value.
+	CacheControl CacheControl
+}
+
+// DeclaredCaching returns responses that declare headers Cache-Control or Expires,
+// for example to configure caches of a CDN. Responses are sorted by path, method, and status.
+//
+// The function returns an error if a declared value of the header Cache-Control is invalid.
+func DeclaredCaching(swagger *openapi3.Swagger) ([]*ResponseCaching, error) {
+	var result []*ResponseCaching
+	paths := make([]string, 0, len(swagger.Paths))
+	for path := range swagger.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		operations := swagger.Paths[path].Operations()
+		methods := make([]string, 0, len(operations))
+		for method := range operations {
+			methods = append(methods, method)
+		}
+		sort.Strings(methods)
+		for _, method := range methods {
+			operation := operations[method]
+			statuses := make([]string, 0, len(operation.Responses))
+			for status := range operation.Responses {
+				statuses = append(statuses, status)
+			}
+			sort.Strings(statuses)
+			for _, status := range statuses {
+				response := operation.Responses[status].Value
+				if response == nil {
+					continue
+				}
+				cacheControl := findResponseHeader(response, headerCacheControl)
+				expires := findResponseHeader(response, headerExpires)
+				if cacheControl == nil && expires == nil {
+					continue
+				}
+				caching := &ResponseCaching{
+					Method:               method,
+					Path:                 path,
+					Operation:            operation,
+					Status:               status,
+					DeclaresCacheControl: cacheControl != nil,
+					DeclaresExpires:      expires != nil,
+				}
+				if value := declaredHeaderValue(cacheControl); value != "" {
+					directives, err := ParseCacheControl(value)
+					if err != nil {
+						return nil, fmt.Errorf("Response %s of operation %s %s declares invalid header 'Cache-Control': %v", status, method, path, err)
+					}
+					caching.CacheControl = directives
+				}
+				result = append(result, caching)
+			}
+		}
+	}
+	return result, nil
+}
+
+// findResponseHeader returns the declaration of a header of the response, or nil.
+func findResponseHeader(response *openapi3.Response, name string) *openapi3.Header {
+	for key, headerRef := range response.Headers {
+		if http.CanonicalHeaderKey(key) == name && headerRef != nil && headerRef.Value != nil {
+			return headerRef.Value
+		}
+	}
+	return nil
+}
+
+// declaredHeaderValue returns the example, the default, or the only enum value of the header.
+func declaredHeaderValue(header *openapi3.Header) string {
+	if header == nil {
+		return ""
+	}
+	if s, ok := header.Example.(string); ok {
+		return s
+	}
+	if schema := header.Schema; schema != nil && schema.Value != nil {
+		if s, ok := schema.Value.Default.(string); ok {
+			return s
+		}
+		if len(schema.Value.Enum) == 1 {
+			if s, ok := schema.Value.Enum[0].(string); ok {
+				return s
+			}
+		}
+	}
+	return ""
+}
+
+// validateCacheControlHeader checks that a response has the header Cache-Control with valid directives
+// if the response declares it.
+func validateCacheControlHeader(input *ResponseValidationInput, response *openapi3.Response) error {
+	if findResponseHeader(response, headerCacheControl) == nil {
+		return nil
+	}
+	value := input.Header.Get(headerCacheControl)
+	if value == "" {
+		return &ResponseError{Input: input, Reason: "header 'Cache-Control' is declared but missing"}
+	}
+	if _, err := ParseCacheControl(value); err != nil {
+		return &ResponseError{Input: input, Reason: fmt.Sprintf("header 'Cache-Control' has invalid value %q", value), Err: err}
+	}
+	return nil
+}
+
+// validateExpiresHeader checks that a response has the header Expires with an HTTP date
+// if the response declares it.
+func validateExpiresHeader(input *ResponseValidationInput, response *openapi3.Response) error {
+	if findResponseHeader(response, headerExpires) == nil {
+		return nil
+	}
+	value := input.Header.Get(headerExpires)
+	if value == "" {
+		return &ResponseError{Input: input, Reason: "header 'Expires' is declared but missing"}
+	}
+	if _, err := http.ParseTime(value); err != nil {
+		return &ResponseError{Input: input, Reason: fmt.Sprintf("header 'Expires' has invalid value %q", value), Err: err}
+	}
+	return nil
+}
+
+// isHTTPToken returns true if the value is a token (RFC 7230).
+func isHTTPToken(value string) bool {
+	if value == "" {
+		return false
+	}
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		case strings.IndexByte("!#$%&'*+-.^_`|~", c) >= 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,117 @@
+package openapi3filter_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/stretchr/testify/require"
+)
+
+const cacheControlSpec = `
+openapi: 3.0.0
+info:
+  title: Articles
+  version: "1.0"
+paths:
+  /articles:
+    get:
+      responses:
+        "200":
+          description: articles
+          headers:
+            Cache-Control:
+              schema:
+                type: string
+              example: public, max-age=60, s-maxage=300
+            Expires:
+              schema:
+                type: string
+        "404":
+          description: not found
+  /articles/{id}:
+    get:
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        "200":
+          description: article
+          headers:
+            cache-control:
+              schema:
+                type: string
+                enum: [no-store]
+`
+
+func TestDeclaredCaching(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(cacheControlSpec))
+	require.NoError(t, err)
+	caching, err := openapi3filter.DeclaredCaching(swagger)
+	require.NoError(t, err)
+	require.Len(t, caching, 2)
+
+	require.Equal(t, "/articles", caching[0].Path)
+	require.Equal(t, "200", caching[0].Status)
+	require.True(t, caching[0].DeclaresCacheControl)
+	require.True(t, caching[0].DeclaresExpires)
+	require.True(t, caching[0].CacheControl.Has("public"))
+	maxAge, ok := caching[0].CacheControl.Seconds("s-maxage")
+	require.True(t, ok)
+	require.Equal(t, int64(300), maxAge)
+
+	require.Equal(t, "/articles/{id}", caching[1].Path)
+	require.False(t, caching[1].DeclaresExpires)
+	require.Equal(t, openapi3filter.CacheControl{"no-store": ""}, caching[1].CacheControl)
+}
+
+func TestParseCacheControl(t *testing.T) {
+	directives, err := openapi3filter.ParseCacheControl(`private, Max-Age=0, no-cache="Set-Cookie", max-stale`)
+	require.NoError(t, err)
+	require.Equal(t, openapi3filter.CacheControl{"private": "", "max-age": "0", "no-cache": "Set-Cookie", "max-stale": ""}, directives)
+
+	for _, value := range []string{"", "max-age", "max-age=-1", "max-age=soon", "no cache", "public;private"} {
+		_, err := openapi3filter.ParseCacheControl(value)
+		require.Error(t, err, value)
+	}
+}
+
+func TestStrictCacheHeaders(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(cacheControlSpec))
+	require.NoError(t, err)
+	router := openapi3filter.NewRouter().WithSwagger(swagger)
+	req := httptest.NewRequest(http.MethodGet, "/articles", nil)
+	route, pathParams, err := router.FindRoute(req.Method, req.URL)
+	require.NoError(t, err)
+	options := &openapi3filter.Options{StrictCacheHeaders: true}
+	validate := func(status int, header http.Header) error {
+		return openapi3filter.ValidateResponse(context.Background(), &openapi3filter.ResponseValidationInput{
+			RequestValidationInput: &openapi3filter.RequestValidationInput{Request: req, PathParams: pathParams, Route: route},
+			Status:                 status,
+			Header:                 header,
+			Options:                options,
+		})
+	}
+
+	require.NoError(t, validate(http.StatusOK, http.Header{
+		"Cache-Control": {"public, max-age=60"},
+		"Expires":       {"Wed, 21 Oct 2015 07:28:00 GMT"},
+	}))
+	require.NoError(t, validate(http.StatusNotFound, http.Header{}))
+
+	err = validate(http.StatusOK, http.Header{"Expires": {"Wed, 21 Oct 2015 07:28:00 GMT"}})
+	require.EqualError(t, err, "header 'Cache-Control' is declared but missing")
+	err = validate(http.StatusOK, http.Header{"Cache-Control": {"max-age=soon"}, "Expires": {"Wed, 21 Oct 2015 07:28:00 GMT"}})
+	require.IsType(t, &openapi3filter.ResponseError{}, err)
+	err = validate(http.StatusOK, http.Header{"Cache-Control": {"no-cache"}, "Expires": {"tomorrow"}})
+	require.IsType(t, &openapi3filter.ResponseError{}, err)
+
+	options.StrictCacheHeaders = false
+	require.NoError(t, validate(http.StatusOK, http.Header{}))
+}
@@ -0,0 +1,247 @@
+package openapi3filter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// CallbackValidationInput is an input for validation of a request sent to a callback of an operation.
+type CallbackValidationInput struct {
+	// RequestValidationInput is the input of the request that the callback is defined for.
+	RequestValidationInput *RequestValidationInput
+
+	// Status, Header, and Body of the response to the request
+	// are needed when callback expressions refer to the response.
+	Status int
+	Header http.Header
+	Body   []byte
+
+	// Callback is the name of the callback in the operation.
+	Callback string
+
+	// Request is the request sent to the callback.
+	Request *http.Request
+	Options *Options
+}
+
+// ValidateCallbackRequest validates a request sent to a callback of an operation.
+//
+// Runtime expressions of the callback are evaluated using the request and response of the operation.
+// The callback request must be sent to the URL of one of the expressions, and is then validated against
+// the callback's operation. Braces that don't contain a runtime expression, like "{id}", are path parameters
+// of the callback, and take their values from the path of the callback request.
+//
+// The function returns RouteError if the callback request doesn't match the callback.
+func ValidateCallbackRequest(c context.Context, input *CallbackValidationInput) error {
+	route := input.RequestValidationInput.Route
+	if route == nil || route.Operation == nil {
+		return errRouteMissingOperation
+	}
+	callbackRef := route.Operation.Callbacks[input.Callback]
+	if callbackRef == nil || callbackRef.Value == nil {
+		return fmt.Errorf("Operation doesn't have callback '%s'", input.Callback)
+	}
+	callback := *callbackRef.Value
+	expressions := make([]string, 0, len(callback))
+	for expression := range callback {
+		expressions = append(expressions, expression)
+	}
+	sort.Strings(expressions)
+	req := input.Request
+	for _, expression := range expressions {
+		callbackURL, err := input.expand(expression)
+		if err != nil {
+			return fmt.Errorf("Callback expression '%s' can't be evaluated: %v", expression, err)
+		}
+		pathParams, ok := matchCallbackURL(callbackURL, req.URL)
+		if !ok {
+			continue
+		}
+		pathItem := callback[expression]
+		operation := pathItem.GetOperation(req.Method)
+		if operation == nil {
+			return &RouteError{
+				Route: Route{
+					Swagger: route.Swagger,
+					Path:    expression,
+				},
+				Reason: "Callback doesn't support the HTTP method",
+			}
+		}
+		return ValidateRequest(c, &RequestValidationInput{
+			Request:    req,
+			PathParams: pathParams,
+			Route: &Route{
+				Swagger:   route.Swagger,
+				Path:      expression,
+				PathItem:  pathItem,
+				Method:    req.Method,
+				Operation: operation,
+			},
+			Options: input.Options,
+		})
+	}
+	return &RouteError{
+		Route: Route{
+			Swagger: route.Swagger,
+		},
+		Reason: "Callback URL doesn't match any expression",
+	}
+}
+
+// expand evaluates runtime expressions embedded in braces in the callback expression,
+// and keeps path parameters like "{id}".
+func (input *CallbackValidationInput) expand(expression string) (string, error) {
+	if strings.HasPrefix(expression, "$") {
+		return openapi3.ExpandRuntimeExpressionTemplate(expression, input.evaluate)
+	}
+	var buf strings.Builder
+	remaining := expression
+	for {
+		start := strings.IndexByte(remaining, '{')
+		if start < 0 {
+			buf.WriteString(remaining)
+			return buf.String(), nil
+		}
+		end := strings.IndexByte(remaining[start:], '}')
+		if end < 0 {
+			return "", fmt.Errorf("Missing '}' in '%s'", expression)
+		}
+		end += start + 1
+		buf.WriteString(remaining[:start])
+		if part := remaining[start:end]; strings.HasPrefix(part, "{$") {
+			value, err := openapi3.ExpandRuntimeExpressionTemplate(part, input.evaluate)
+			if err != nil {
+				return "", err
+			}
+			buf.WriteString(value)
+		} else {
+			buf.WriteString(part)
+		}
+		remaining = remaining[end:]
+	}
+}
+
+// matchCallbackURL returns values of path parameters if the URL matches the expanded callback expression.
+func matchCallbackURL(callbackURL string, u *url.URL) (map[string]string, bool) {
+	expected, err := url.Parse(callbackURL)
+	if err != nil || expected.Scheme != u.Scheme || expected.Host != u.Host {
+		return nil, false
+	}
+	templateSegments := strings.Split(expected.Path, "/")
+	segments := strings.Split(u.Path, "/")
+	if len(templateSegments) != len(segments) {
+		return nil, false
+	}
+	pathParams := make(map[string]string)
+	for i, templateSegment := range templateSegments {
+		segment := segments[i]
+		start := strings.IndexByte(templateSegment, '{')
+		end := strings.IndexByte(templateSegment, '}')
+		if start < 0 || end < start {
+			if templateSegment != segment {
+				return nil, false
+			}
+			continue
+		}
+		prefix, suffix := templateSegment[:start], templateSegment[end+1:]
+		if len(segment) <= len(prefix)+len(suffix) || !strings.HasPrefix(segment, prefix) || !strings.HasSuffix(segment, suffix) {
+			return nil, false
+		}
+		pathParams[templateSegment[start+1:end]] = segment[len(prefix) : len(segment)-len(suffix)]
+	}
+	return pathParams, true
+}
+
+// evaluate returns the value of the runtime expression.
+func (input *CallbackValidationInput) evaluate(expression *openapi3.RuntimeExpression) (string, error) {
+	return evaluateRuntimeExpression(input.RequestValidationInput, input.Status, input.Header, input.Body, expression)
+}
+
+// evaluateRuntimeExpression returns the value of the runtime expression
+// for a request and the response to it.
+func evaluateRuntimeExpression(requestInput *RequestValidationInput, status int, header http.Header, body []byte, expression *openapi3.RuntimeExpression) (string, error) {
+	req := requestInput.Request
+	switch expression.Kind {
+	case openapi3.RuntimeExpressionURL:
+		return req.URL.String(), nil
+	case openapi3.RuntimeExpressionMethod:
+		return req.Method, nil
+	case openapi3.RuntimeExpressionStatusCode:
+		return strconv.Itoa(status), nil
+	case openapi3.RuntimeExpressionRequest:
+		switch expression.Source {
+		case openapi3.RuntimeExpressionSourceHeader:
+			return req.Header.Get(expression.Name), nil
+		case openapi3.RuntimeExpressionSourceQuery:
+			return requestInput.GetQueryParams().Get(expression.Name), nil
+		case openapi3.RuntimeExpressionSourcePath:
+			return requestInput.PathParams[expression.Name], nil
+		default:
+			var data []byte
+			if req.Body != nil && req.Body != http.NoBody {
+				var err error
+				if data, err = ioutil.ReadAll(req.Body); err != nil {
+					return "", err
+				}
+				req.Body.Close()
+				// Put the data back into the request
+				req.Body = ioutil.NopCloser(bytes.NewReader(data))
+			}
+			return evaluateBodyPointer(data, expression)
+		}
+	default:
+		switch expression.Source {
+		case openapi3.RuntimeExpressionSourceHeader:
+			return header.Get(expression.Name), nil
+		case openapi3.RuntimeExpressionSourceBody:
+			return evaluateBodyPointer(body, expression)
+		default:
+			return "", fmt.Errorf("Expression '%s' refers to a response %s, which doesn't exist", expression, expression.Source)
+		}
+	}
+}
+
+// evaluateBodyPointer returns the value that the JSON pointer of the expression points to in a JSON body.
+func evaluateBodyPointer(data []byte, expression *openapi3.RuntimeExpression) (string, error) {
+	tokens := expression.PointerTokens()
+	if len(tokens) == 0 {
+		return string(data), nil
+	}
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return "", err
+	}
+	for _, token := range tokens {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			value = v[token]
+		case []interface{}:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(v) {
+				return "", fmt.Errorf("Expression '%s' points to a missing value", expression)
+			}
+			value = v[i]
+		default:
+			value = nil
+		}
+		if value == nil {
+			return "", fmt.Errorf("Expression '%s' points to a missing value", expression)
+		}
+	}
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+	data, err := json.Marshal(value)
+	return string(data), err
+}
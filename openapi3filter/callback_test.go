@@ -0,0 +1,126 @@
+package openapi3filter_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/stretchr/testify/require"
+)
+
+const callbackSpec = `
+openapi: 3.0.0
+info:
+  title: Webhooks
+  version: "1.0"
+paths:
+  /subscriptions/{topic}:
+    post:
+      parameters:
+        - name: topic
+          in: path
+          required: true
+          schema:
+            type: string
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                callbackUrl:
+                  type: string
+      callbacks:
+        onEvent:
+          "{$request.body#/callbackUrl}/events/{$request.path.topic}":
+            post:
+              requestBody:
+                required: true
+                content:
+                  application/json:
+                    schema:
+                      type: object
+                      required: [id]
+                      properties:
+                        id:
+                          type: integer
+              responses:
+                "200":
+                  description: received
+        onItem:
+          "{$request.body#/callbackUrl}/items/{id}":
+            delete:
+              parameters:
+                - name: id
+                  in: path
+                  required: true
+                  schema:
+                    type: integer
+              responses:
+                "204":
+                  description: deleted
+      responses:
+        "201":
+          description: subscribed
+`
+
+func TestValidateCallbackRequest(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(callbackSpec))
+	require.NoError(t, err)
+	router := openapi3filter.NewRouter().WithSwagger(swagger)
+
+	req := httptest.NewRequest(http.MethodPost, "/subscriptions/orders", bytes.NewBufferString(`{"callbackUrl": "https://client.example.com/hooks"}`))
+	req.Header.Set("Content-Type", "application/json")
+	route, pathParams, err := router.FindRoute(req.Method, req.URL)
+	require.NoError(t, err)
+	requestInput := &openapi3filter.RequestValidationInput{
+		Request:    req,
+		PathParams: pathParams,
+		Route:      route,
+	}
+	require.NoError(t, openapi3filter.ValidateRequest(context.Background(), requestInput))
+
+	validate := func(method string, url string, body string) error {
+		callbackReq := httptest.NewRequest(method, url, bytes.NewBufferString(body))
+		callbackReq.Header.Set("Content-Type", "application/json")
+		return openapi3filter.ValidateCallbackRequest(context.Background(), &openapi3filter.CallbackValidationInput{
+			RequestValidationInput: requestInput,
+			Callback:               "onEvent",
+			Request:                callbackReq,
+		})
+	}
+	require.NoError(t, validate(http.MethodPost, "https://client.example.com/hooks/events/orders", `{"id": 1}`))
+
+	err = validate(http.MethodPost, "https://client.example.com/hooks/events/orders", `{"id": "x"}`)
+	require.IsType(t, &openapi3filter.RequestError{}, err)
+
+	err = validate(http.MethodPost, "https://client.example.com/hooks/events/users", `{"id": 1}`)
+	require.IsType(t, &openapi3filter.RouteError{}, err)
+
+	err = validate(http.MethodPut, "https://client.example.com/hooks/events/orders", `{"id": 1}`)
+	require.IsType(t, &openapi3filter.RouteError{}, err)
+
+	err = openapi3filter.ValidateCallbackRequest(context.Background(), &openapi3filter.CallbackValidationInput{
+		RequestValidationInput: requestInput,
+		Callback:               "onOther",
+		Request:                httptest.NewRequest(http.MethodPost, "https://client.example.com/hooks/events/orders", nil),
+	})
+	require.Error(t, err)
+
+	validateItem := func(url string) error {
+		return openapi3filter.ValidateCallbackRequest(context.Background(), &openapi3filter.CallbackValidationInput{
+			RequestValidationInput: requestInput,
+			Callback:               "onItem",
+			Request:                httptest.NewRequest(http.MethodDelete, url, nil),
+		})
+	}
+	require.NoError(t, validateItem("https://client.example.com/hooks/items/42"))
+	err = validateItem("https://client.example.com/hooks/items/x")
+	require.IsType(t, &openapi3filter.RequestError{}, err)
+	err = validateItem("https://client.example.com/hooks/items/42/parts")
+	require.IsType(t, &openapi3filter.RouteError{}, err)
+}
@@ -0,0 +1,253 @@
+package openapi3filter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+var errCompiledRouterImmutable = errors.New("Compiled router can't be modified")
+
+// NewCompiledRouter returns a router with all path templates of the Swagger compiled into
+// a trie of path segments, so finding a route takes time proportional to the number of segments
+// in the path instead of the number of paths.
+//
+// Paths match like in routers of NewRouter: trailing slashes are ignored,
+// and a variable like "{path*}" that matches all remaining segments must be the last segment.
+// The router can't be modified after it's created, which makes it safe for concurrent use.
+func NewCompiledRouter(swagger *openapi3.Swagger) (*Router, error) {
+	if err := swagger.Validate(context.TODO()); err != nil {
+		return nil, fmt.Errorf("Validating Swagger failed: %v", err)
+	}
+	compiled := &compiledRoutes{
+		root:                 &segmentNode{},
+		serverParameterNames: make(map[*openapi3.Server][]string, len(swagger.Servers)),
+	}
+	for _, server := range swagger.Servers {
+		names, err := server.ParameterNames()
+		if err != nil {
+			return nil, fmt.Errorf("Server '%s' is invalid: %v", server.URL, err)
+		}
+		compiled.serverParameterNames[server] = names
+	}
+	router := &Router{
+		swagger:  swagger,
+		compiled: compiled,
+	}
+	for path, pathItem := range swagger.Paths {
+		routes, err := compiled.add(swagger, path, pathItem)
+		if err != nil {
+			return nil, err
+		}
+		for _, route := range routes {
+			if err := router.addServerOverride(route); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return router, nil
+}
+
+type compiledRoutes struct {
+	root                 *segmentNode
+	serverParameterNames map[*openapi3.Server][]string
+}
+
+// segmentNode matches one segment of a path.
+type segmentNode struct {
+	constants map[string]*segmentNode
+
+	// patterns match segments like "{name}.{format}"
+	patterns []*segmentPattern
+
+	// variable matches any segment
+	variable *segmentNode
+
+	// everything matches all remaining segments, including none
+	everything *segmentNode
+
+	// leaf is defined if a path ends at this node
+	leaf *segmentLeaf
+}
+
+type segmentPattern struct {
+	template string
+	regExp   *regexp.Regexp
+	node     *segmentNode
+}
+
+type segmentLeaf struct {
+	path          string
+	variableNames []string
+	routes        map[string]*Route
+}
+
+var segmentVariableRegExp = regexp.MustCompile(`\{[^}]*\}`)
+
+func (compiled *compiledRoutes) add(swagger *openapi3.Swagger, path string, pathItem *openapi3.PathItem) (map[string]*Route, error) {
+	node := compiled.root
+	var variableNames []string
+	segments := splitPath(path)
+	for i, segment := range segments {
+		switch {
+		case !strings.Contains(segment, "{"):
+			child := node.constants[segment]
+			if child == nil {
+				if node.constants == nil {
+					node.constants = make(map[string]*segmentNode)
+				}
+				child = &segmentNode{}
+				node.constants[segment] = child
+			}
+			node = child
+		case segmentVariableRegExp.FindString(segment) == segment:
+			name := strings.TrimSpace(segment[1 : len(segment)-1])
+			if strings.HasSuffix(name, "*") {
+				if i != len(segments)-1 {
+					return nil, fmt.Errorf("Path '%s' has variable '%s' that matches all remaining segments before its last segment", path, segment)
+				}
+				if node.everything == nil {
+					node.everything = &segmentNode{}
+				}
+				node = node.everything
+				name = name[:len(name)-1]
+			} else {
+				if node.variable == nil {
+					node.variable = &segmentNode{}
+				}
+				node = node.variable
+			}
+			variableNames = append(variableNames, name)
+		default:
+			template := segmentVariableRegExp.ReplaceAllString(segment, "{}")
+			var pattern *segmentPattern
+			for _, existing := range node.patterns {
+				if existing.template == template {
+					pattern = existing
+					break
+				}
+			}
+			if pattern == nil {
+				parts := strings.Split(template, "{}")
+				for i, part := range parts {
+					parts[i] = regexp.QuoteMeta(part)
+				}
+				pattern = &segmentPattern{
+					template: template,
+					regExp:   regexp.MustCompile("^" + strings.Join(parts, "(.*?)") + "$"),
+					node:     &segmentNode{},
+				}
+				node.patterns = append(node.patterns, pattern)
+			}
+			node = pattern.node
+			for _, variable := range segmentVariableRegExp.FindAllString(segment, -1) {
+				variableNames = append(variableNames, strings.TrimSpace(variable[1:len(variable)-1]))
+			}
+		}
+	}
+	if leaf := node.leaf; leaf != nil {
+		return nil, fmt.Errorf("Paths '%s' and '%s' are ambiguous", leaf.path, path)
+	}
+	leaf := &segmentLeaf{
+		path:          path,
+		variableNames: variableNames,
+		routes:        make(map[string]*Route),
+	}
+	for method, operation := range pathItem.Operations() {
+		method = strings.ToUpper(method)
+		route := &Route{
+			Swagger:   swagger,
+			Path:      path,
+			PathItem:  pathItem,
+			Method:    method,
+			Operation: operation,
+		}
+		route.parseIdempotencyKey()
+		leaf.routes[method] = route
+	}
+	node.leaf = leaf
+	return leaf.routes, nil
+}
+
+func (compiled *compiledRoutes) findRoute(swagger *openapi3.Swagger, server *openapi3.Server, method string, path string) (*Route, map[string]string, error) {
+	leaf, values := compiled.root.match(splitPath(path), make([]string, 0, 8))
+	if leaf == nil {
+		return nil, nil, &RouteError{
+			Route: Route{
+				Swagger: swagger,
+				Server:  server,
+			},
+			Reason: "Path was not found",
+		}
+	}
+	route := leaf.routes[method]
+	if route == nil {
+		return nil, nil, &RouteError{
+			Route: Route{
+				Swagger: swagger,
+				Server:  server,
+			},
+			Reason: "Path doesn't support the HTTP method",
+		}
+	}
+	pathParams := make(map[string]string, len(values))
+	for i, value := range values {
+		pathParams[leaf.variableNames[i]] = value
+	}
+	return route, pathParams, nil
+}
+
+// match returns the leaf that the segments lead to and values of path variables.
+// Constant segments take precedence over patterns, which take precedence over variables.
+func (node *segmentNode) match(segments []string, values []string) (*segmentLeaf, []string) {
+	if len(segments) == 0 {
+		if node.leaf != nil {
+			return node.leaf, values
+		}
+		if everything := node.everything; everything != nil && everything.leaf != nil {
+			return everything.leaf, append(values, "")
+		}
+		return nil, nil
+	}
+	segment, remaining := segments[0], segments[1:]
+	if child := node.constants[segment]; child != nil {
+		if leaf, result := child.match(remaining, values); leaf != nil {
+			return leaf, result
+		}
+	}
+	for _, pattern := range node.patterns {
+		matches := pattern.regExp.FindStringSubmatch(segment)
+		if matches == nil {
+			continue
+		}
+		if leaf, result := pattern.node.match(remaining, append(values, matches[1:]...)); leaf != nil {
+			return leaf, result
+		}
+	}
+	if child := node.variable; child != nil {
+		if leaf, result := child.match(remaining, append(values, segment)); leaf != nil {
+			return leaf, result
+		}
+	}
+	if everything := node.everything; everything != nil && everything.leaf != nil {
+		return everything.leaf, append(values, strings.Join(segments, "/"))
+	}
+	return nil, nil
+}
+
+// splitPath returns segments of a path without the leading slash and trailing slashes,
+// so "/pets/" is "/pets" like in routers of NewRouter, but "//pets" isn't.
+func splitPath(path string) []string {
+	for strings.HasSuffix(path, "/") {
+		path = path[:len(path)-1]
+	}
+	path = strings.TrimPrefix(path, "/")
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, "/")
+}
@@ -0,0 +1,176 @@
+package openapi3filter_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompiledRouter(t *testing.T) {
+	usersGET := &openapi3.Operation{}
+	meGET := &openapi3.Operation{}
+	userGET := &openapi3.Operation{}
+	userDELETE := &openapi3.Operation{}
+	reportGET := &openapi3.Operation{}
+	filesGET := &openapi3.Operation{}
+	swagger := &openapi3.Swagger{
+		Servers: openapi3.Servers{
+			{URL: "https://{tenant}.example.com/api"},
+		},
+		Paths: openapi3.Paths{
+			"/users":                     &openapi3.PathItem{Get: usersGET},
+			"/users/me":                  &openapi3.PathItem{Get: meGET},
+			"/users/{id}":                &openapi3.PathItem{Get: userGET, Delete: userDELETE},
+			"/users/{id}/report.{ext}":   &openapi3.PathItem{Get: reportGET},
+			"/users/{userId}/files/{p*}": &openapi3.PathItem{Get: filesGET},
+		},
+	}
+	router, err := openapi3filter.NewCompiledRouter(swagger)
+	require.NoError(t, err)
+
+	expect := func(method string, uri string, operation *openapi3.Operation, params map[string]string) {
+		u, err := url.Parse(uri)
+		require.NoError(t, err)
+		route, pathParams, err := router.FindRoute(method, u)
+		if operation == nil {
+			require.Error(t, err, "%s %s", method, uri)
+			return
+		}
+		require.NoError(t, err, "%s %s", method, uri)
+		require.True(t, route.Operation == operation, "%s %s", method, uri)
+		require.Equal(t, params, pathParams, "%s %s", method, uri)
+	}
+	expect("GET", "https://acme.example.com/api/users", usersGET, map[string]string{"tenant": "acme"})
+	expect("GET", "https://acme.example.com/api/users/", usersGET, map[string]string{"tenant": "acme"})
+	expect("GET", "https://acme.example.com/api/users/me", meGET, map[string]string{"tenant": "acme"})
+	expect("GET", "https://acme.example.com/api/users/42", userGET, map[string]string{"tenant": "acme", "id": "42"})
+	expect("DELETE", "https://acme.example.com/api/users/42", userDELETE, map[string]string{"tenant": "acme", "id": "42"})
+	expect("PUT", "https://acme.example.com/api/users/42", nil, nil)
+	expect("GET", "https://acme.example.com/api/users/42/report.csv", reportGET, map[string]string{"tenant": "acme", "id": "42", "ext": "csv"})
+	expect("GET", "https://acme.example.com/api/users/42/files/a/b.txt", filesGET, map[string]string{"tenant": "acme", "userId": "42", "p": "a/b.txt"})
+	expect("GET", "https://acme.example.com/api/users/42/files", filesGET, map[string]string{"tenant": "acme", "userId": "42", "p": ""})
+	expect("GET", "https://acme.example.com/api/users/42/unknown", nil, nil)
+	expect("GET", "https://acme.example.com/users", nil, nil)
+
+	_, route, _, err := openapi3filter.Routers{router}.FindRoute("GET", &url.URL{Scheme: "https", Host: "x.example.com", Path: "/api/users/me"})
+	require.NoError(t, err)
+	require.True(t, route.Operation == meGET)
+
+	err = router.AddRoute(&openapi3filter.Route{Method: "GET", Path: "/other"})
+	require.Error(t, err)
+}
+
+func TestCompiledRouterAmbiguousPaths(t *testing.T) {
+	swagger := &openapi3.Swagger{
+		Paths: openapi3.Paths{
+			"/users/{id}":   &openapi3.PathItem{Get: &openapi3.Operation{}},
+			"/users/{name}": &openapi3.PathItem{Get: &openapi3.Operation{}},
+		},
+	}
+	_, err := openapi3filter.NewCompiledRouter(swagger)
+	require.Error(t, err)
+}
+
+func TestCompiledRouterMatchesLikeRouter(t *testing.T) {
+	swagger := &openapi3.Swagger{
+		Paths: openapi3.Paths{
+			"/pets":                &openapi3.PathItem{Get: &openapi3.Operation{}},
+			"/pets/{id}/toys/":     &openapi3.PathItem{Get: &openapi3.Operation{}},
+			"/files/{p*}":          &openapi3.PathItem{Get: &openapi3.Operation{}},
+			"/files/readme":        &openapi3.PathItem{Get: &openapi3.Operation{}},
+			"/owners/{id}/address": &openapi3.PathItem{Get: &openapi3.Operation{}},
+		},
+	}
+	router := openapi3filter.NewRouter().WithSwagger(swagger)
+	compiledRouter, err := openapi3filter.NewCompiledRouter(swagger)
+	require.NoError(t, err)
+	for _, path := range []string{
+		"/pets", "/pets/", "/pets//", "//pets", "/pets/1/toys", "/pets/1/toys/", "/pets//toys",
+		"/files/a/b", "/files/a/b/", "/files/readme", "/files/readme/", "//files/readme",
+		"/owners/1/address", "/owners/1/address/", "/owners//address",
+	} {
+		u := &url.URL{Path: path}
+		want, wantParams, wantErr := router.FindRoute("GET", u)
+		got, gotParams, gotErr := compiledRouter.FindRoute("GET", u)
+		if wantErr != nil {
+			require.Error(t, gotErr, path)
+			continue
+		}
+		require.NoError(t, gotErr, path)
+		require.Equal(t, want.Path, got.Path, path)
+		require.Equal(t, wantParams, gotParams, path)
+	}
+}
+
+func TestCompiledRouterInvalidPaths(t *testing.T) {
+	swagger := &openapi3.Swagger{
+		Paths: openapi3.Paths{
+			"/files/{p*}/meta": &openapi3.PathItem{Get: &openapi3.Operation{}},
+		},
+	}
+	_, err := openapi3filter.NewCompiledRouter(swagger)
+	require.Error(t, err)
+}
+
+func TestCompiledRouterConcurrency(t *testing.T) {
+	swagger := benchmarkSwagger(100)
+	router, err := openapi3filter.NewCompiledRouter(swagger)
+	require.NoError(t, err)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				u := &url.URL{Path: fmt.Sprintf("/resources%d/%d/items/%d", (i*j)%100, j, i)}
+				route, pathParams, err := router.FindRoute(http.MethodGet, u)
+				if err != nil || route == nil || pathParams["item"] != fmt.Sprint(i) {
+					t.Errorf("unexpected route for %s: %v", u.Path, err)
+					return
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func BenchmarkRouter(b *testing.B) {
+	swagger := benchmarkSwagger(500)
+	u := &url.URL{Path: "/resources499/1/items/2"}
+	b.Run("NewRouter", func(b *testing.B) {
+		router := openapi3filter.NewRouter().WithSwagger(swagger)
+		benchmarkFindRoute(b, router, u)
+	})
+	b.Run("NewCompiledRouter", func(b *testing.B) {
+		router, err := openapi3filter.NewCompiledRouter(swagger)
+		require.NoError(b, err)
+		benchmarkFindRoute(b, router, u)
+	})
+}
+
+func benchmarkFindRoute(b *testing.B, router *openapi3filter.Router, u *url.URL) {
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, _, err := router.FindRoute(http.MethodGet, u); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func benchmarkSwagger(n int) *openapi3.Swagger {
+	swagger := &openapi3.Swagger{
+		Paths: make(openapi3.Paths, 2*n),
+	}
+	for i := 0; i < n; i++ {
+		swagger.Paths[fmt.Sprintf("/resources%d", i)] = &openapi3.PathItem{Get: &openapi3.Operation{}}
+		swagger.Paths[fmt.Sprintf("/resources%d/{id}/items/{item}", i)] = &openapi3.PathItem{Get: &openapi3.Operation{}}
+	}
+	return swagger
+}
@@ -0,0 +1,84 @@
+package openapi3filter
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// compressibleMediaTypes are media types that aren't compressed by their format.
+var compressibleMediaTypes = map[string]bool{
+	"application/javascript":            true,
+	"application/json":                  true,
+	"application/x-ndjson":              true,
+	"application/x-www-form-urlencoded": true,
+	"application/xml":                   true,
+	"application/yaml":                  true,
+	"image/svg+xml":                     true,
+}
+
+// IsCompressibleMediaType tells whether responses of the media type benefit from compression.
+// Text media types and ones with a "+json" or "+xml" suffix are compressible.
+func IsCompressibleMediaType(mediaType string) bool {
+	mediaType = strings.ToLower(strings.TrimSpace(parseMediaType(mediaType)))
+	if compressibleMediaTypes[mediaType] || strings.HasPrefix(mediaType, "text/") {
+		return true
+	}
+	return strings.HasSuffix(mediaType, "+json") || strings.HasSuffix(mediaType, "+xml")
+}
+
+// CompressibleMediaTypes returns sorted media types of the operation's responses that are compressible.
+// The result can be used to configure compression middleware for the operation.
+func CompressibleMediaTypes(operation *openapi3.Operation) []string {
+	mediaTypes := make(map[string]bool)
+	for _, responseRef := range operation.Responses {
+		if response := responseRef.Value; response != nil {
+			for mediaType := range response.Content {
+				if IsCompressibleMediaType(mediaType) {
+					mediaTypes[mediaType] = true
+				}
+			}
+		}
+	}
+	result := make([]string, 0, len(mediaTypes))
+	for mediaType := range mediaTypes {
+		result = append(result, mediaType)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// validateContentEncoding validates that a response has a declared header Content-Encoding.
+func validateContentEncoding(c context.Context, input *ResponseValidationInput, response *openapi3.Response) error {
+	value := input.Header.Get("Content-Encoding")
+	if value == "" || value == "identity" {
+		return nil
+	}
+	var header *openapi3.Header
+	for name, headerRef := range response.Headers {
+		if http.CanonicalHeaderKey(name) == "Content-Encoding" {
+			header = headerRef.Value
+			break
+		}
+	}
+	if header == nil {
+		return &ResponseError{
+			Input:  input,
+			Reason: fmt.Sprintf("header 'Content-Encoding' with value %q is not declared", value),
+		}
+	}
+	if schema := header.Schema; schema != nil && schema.Value != nil {
+		if err := schema.Value.VisitJSONContext(c, value); err != nil {
+			return &ResponseError{
+				Input:  input,
+				Reason: "header 'Content-Encoding' doesn't match the schema",
+				Err:    err,
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package openapi3filter_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/stretchr/testify/require"
+)
+
+const compressionSpec = `
+openapi: 3.0.0
+info:
+  title: Reports
+  version: "1.0"
+paths:
+  /reports/{id}:
+    get:
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        "200":
+          description: report
+          headers:
+            Content-Encoding:
+              schema:
+                type: string
+                enum: [gzip]
+          content:
+            application/json:
+              schema:
+                type: object
+            text/csv:
+              schema:
+                type: string
+            application/pdf:
+              schema:
+                type: string
+                format: binary
+        "404":
+          description: not found
+          content:
+            application/problem+json:
+              schema:
+                type: object
+            image/png:
+              schema:
+                type: string
+                format: binary
+`
+
+func TestCompressibleMediaTypes(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(compressionSpec))
+	require.NoError(t, err)
+	operation := swagger.Paths["/reports/{id}"].Get
+	require.Equal(t, []string{"application/json", "application/problem+json", "text/csv"}, openapi3filter.CompressibleMediaTypes(operation))
+	require.True(t, openapi3filter.IsCompressibleMediaType("Text/HTML; charset=utf-8"))
+	require.False(t, openapi3filter.IsCompressibleMediaType("application/octet-stream"))
+}
+
+func TestStrictContentEncoding(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(compressionSpec))
+	require.NoError(t, err)
+	router := openapi3filter.NewRouter().WithSwagger(swagger)
+	req := httptest.NewRequest(http.MethodGet, "/reports/1", nil)
+	route, pathParams, err := router.FindRoute(req.Method, req.URL)
+	require.NoError(t, err)
+
+	validate := func(status int, contentEncoding string, options *openapi3filter.Options) error {
+		header := http.Header{}
+		header.Set("Content-Type", "application/json")
+		header.Set("Content-Encoding", contentEncoding)
+		input := &openapi3filter.ResponseValidationInput{
+			RequestValidationInput: &openapi3filter.RequestValidationInput{
+				Request:    req,
+				PathParams: pathParams,
+				Route:      route,
+			},
+			Status:  status,
+			Header:  header,
+			Options: options,
+		}
+		return openapi3filter.ValidateResponse(context.Background(), input.SetBodyBytes([]byte("{}")))
+	}
+	strict := &openapi3filter.Options{StrictContentEncoding: true, ExcludeResponseBody: true}
+	require.NoError(t, validate(http.StatusOK, "gzip", strict))
+	require.NoError(t, validate(http.StatusOK, "identity", strict))
+	require.Error(t, validate(http.StatusOK, "br", strict))
+	require.Error(t, validate(http.StatusNotFound, "gzip", strict))
+	require.NoError(t, validate(http.StatusNotFound, "gzip", &openapi3filter.Options{ExcludeResponseBody: true}))
+}
@@ -0,0 +1,165 @@
+package openapi3filter
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+const (
+	headerETag        = "ETag"
+	headerIfMatch     = "If-Match"
+	headerIfNoneMatch = "If-None-Match"
+)
+
+// ErrInvalidEntityTag is an error that happens when a header doesn't contain valid entity tags.
+var ErrInvalidEntityTag = errors.New("must be an entity tag")
+
+// ValidateConditionalRequests checks that operations with responses that declare the header ETag
+// accept conditional requests.
+//
+// GET and HEAD operations must accept the header If-None-Match.
+// Other operations must accept the header If-Match, as must PUT, PATCH, and DELETE operations
+// of a path which GET operation declares the header ETag.
+func ValidateConditionalRequests(swagger *openapi3.Swagger) error {
+	paths := make([]string, 0, len(swagger.Paths))
+	for path := range swagger.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		pathItem := swagger.Paths[path]
+		getHasETag := pathItem.Get != nil && declaresETag(pathItem.Get)
+		operations := pathItem.Operations()
+		methods := make([]string, 0, len(operations))
+		for method := range operations {
+			methods = append(methods, method)
+		}
+		sort.Strings(methods)
+		for _, method := range methods {
+			operation := operations[method]
+			var header string
+			switch method {
+			case http.MethodGet, http.MethodHead:
+				if declaresETag(operation) {
+					header = headerIfNoneMatch
+				}
+			case http.MethodPut, http.MethodPatch, http.MethodDelete:
+				if getHasETag || declaresETag(operation) {
+					header = headerIfMatch
+				}
+			default:
+				if declaresETag(operation) {
+					header = headerIfMatch
+				}
+			}
+			if header != "" && !acceptsHeader(pathItem, operation, header) {
+				return fmt.Errorf("Operation %s %s doesn't accept header '%s' of conditional requests", method, path, header)
+			}
+		}
+	}
+	return nil
+}
+
+func declaresETag(operation *openapi3.Operation) bool {
+	for _, responseRef := range operation.Responses {
+		if response := responseRef.Value; response != nil {
+			for name := range response.Headers {
+				if http.CanonicalHeaderKey(name) == "Etag" {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func acceptsHeader(pathItem *openapi3.PathItem, operation *openapi3.Operation, header string) bool {
+	for _, parameterRef := range pathItem.OperationParameters(operation) {
+		parameter := parameterRef.Value
+		if parameter.In == openapi3.ParameterInHeader && strings.EqualFold(parameter.Name, header) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateConditionalRequestHeaders validates formats of headers If-Match and If-None-Match.
+func validateConditionalRequestHeaders(input *RequestValidationInput) error {
+	for _, header := range []string{headerIfMatch, headerIfNoneMatch} {
+		value := input.Request.Header.Get(header)
+		if value == "" || value == "*" {
+			continue
+		}
+		if !isEntityTagList(value) {
+			return &RequestError{
+				Input:     input,
+				Parameter: openapi3.NewHeaderParameter(header),
+				Reason:    fmt.Sprintf("invalid entity tags %q", value),
+				Err:       ErrInvalidEntityTag,
+			}
+		}
+	}
+	return nil
+}
+
+// validateETagHeader validates the format of the header ETag of a response.
+func validateETagHeader(input *ResponseValidationInput) error {
+	value := input.Header.Get(headerETag)
+	if value == "" || isEntityTag(value) {
+		return nil
+	}
+	return &ResponseError{
+		Input:  input,
+		Reason: fmt.Sprintf("header 'ETag' has invalid value %q", value),
+		Err:    ErrInvalidEntityTag,
+	}
+}
+
+// isEntityTagList checks that the value is a comma-separated list of entity tags (RFC 7232).
+func isEntityTagList(value string) bool {
+	for {
+		value = strings.TrimLeft(value, " \t")
+		n := entityTagLength(value)
+		if n == 0 {
+			return false
+		}
+		value = strings.TrimLeft(value[n:], " \t")
+		if value == "" {
+			return true
+		}
+		if value[0] != ',' {
+			return false
+		}
+		value = value[1:]
+	}
+}
+
+func isEntityTag(value string) bool {
+	n := entityTagLength(value)
+	return n > 0 && n == len(value)
+}
+
+// entityTagLength returns the length of the entity tag at the beginning of the value or 0.
+func entityTagLength(value string) int {
+	n := 0
+	if strings.HasPrefix(value, "W/") {
+		n = 2
+	}
+	if len(value) <= n || value[n] != '"' {
+		return 0
+	}
+	for i := n + 1; i < len(value); i++ {
+		switch c := value[i]; {
+		case c == '"':
+			return i + 1
+		case c < 0x21 || c == 0x7F:
+			return 0
+		}
+	}
+	return 0
+}
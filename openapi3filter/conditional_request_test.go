@@ -0,0 +1,117 @@
+package openapi3filter_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/stretchr/testify/require"
+)
+
+const conditionalRequestSpec = `
+openapi: 3.0.0
+info:
+  title: Documents
+  version: "1.0"
+paths:
+  /documents/{id}:
+    parameters:
+      - name: id
+        in: path
+        required: true
+        schema:
+          type: string
+    get:
+      parameters:
+        - name: If-None-Match
+          in: header
+          schema:
+            type: string
+      responses:
+        "200":
+          description: document
+          headers:
+            ETag:
+              schema:
+                type: string
+    put:
+      parameters:
+        - name: If-Match
+          in: header
+          schema:
+            type: string
+      responses:
+        "204":
+          description: updated
+    delete:
+      responses:
+        "204":
+          description: deleted
+`
+
+func TestValidateConditionalRequests(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(conditionalRequestSpec))
+	require.NoError(t, err)
+	err = openapi3filter.ValidateConditionalRequests(swagger)
+	require.EqualError(t, err, "Operation DELETE /documents/{id} doesn't accept header 'If-Match' of conditional requests")
+
+	swagger.Paths["/documents/{id}"].Delete.AddParameter(openapi3.NewHeaderParameter("if-match"))
+	err = openapi3filter.ValidateConditionalRequests(swagger)
+	require.NoError(t, err)
+
+	swagger.Paths["/documents/{id}"].Get.Parameters = nil
+	err = openapi3filter.ValidateConditionalRequests(swagger)
+	require.EqualError(t, err, "Operation GET /documents/{id} doesn't accept header 'If-None-Match' of conditional requests")
+}
+
+func TestValidateConditionalHeaders(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(conditionalRequestSpec))
+	require.NoError(t, err)
+	router := openapi3filter.NewRouter().WithSwagger(swagger)
+	options := &openapi3filter.Options{ValidateConditionalHeaders: true}
+
+	newInput := func(method string, header string, value string) *openapi3filter.RequestValidationInput {
+		req := httptest.NewRequest(method, "/documents/1", nil)
+		req.Header.Set(header, value)
+		route, pathParams, err := router.FindRoute(req.Method, req.URL)
+		require.NoError(t, err)
+		return &openapi3filter.RequestValidationInput{
+			Request:    req,
+			PathParams: pathParams,
+			Route:      route,
+			Options:    options,
+		}
+	}
+
+	for _, value := range []string{`*`, `"abc"`, `W/"abc"`, `"a,b", W/"c" ,""`} {
+		err = openapi3filter.ValidateRequest(context.Background(), newInput(http.MethodGet, "If-None-Match", value))
+		require.NoError(t, err, value)
+	}
+	for _, value := range []string{`abc`, `"abc`, `"a" "b"`, `"a",`, `w/"a"`} {
+		err = openapi3filter.ValidateRequest(context.Background(), newInput(http.MethodPut, "If-Match", value))
+		require.Error(t, err, value)
+		require.Equal(t, openapi3filter.ErrInvalidEntityTag, err.(*openapi3filter.RequestError).Err, value)
+	}
+
+	// The option is disabled by default.
+	input := newInput(http.MethodPut, "If-Match", "abc")
+	input.Options = nil
+	err = openapi3filter.ValidateRequest(context.Background(), input)
+	require.NoError(t, err)
+
+	validateResponse := func(etag string) error {
+		header := http.Header{}
+		header.Set("ETag", etag)
+		return openapi3filter.ValidateResponse(context.Background(), &openapi3filter.ResponseValidationInput{
+			RequestValidationInput: newInput(http.MethodGet, "If-None-Match", "*"),
+			Status:                 http.StatusNotModified,
+			Header:                 header,
+			Options:                options,
+		})
+	}
+	require.NoError(t, validateResponse(`W/"1"`))
+	require.Error(t, validateResponse(`1`))
+}
@@ -0,0 +1,94 @@
+package openapi3filter_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/stretchr/testify/require"
+)
+
+const contextSpec = `
+openapi: 3.0.0
+info:
+  title: Uploads
+  version: "1.0"
+paths:
+  /uploads:
+    post:
+      security:
+        - token: []
+      requestBody:
+        content:
+          application/x-slow:
+            schema:
+              type: object
+          application/json:
+            schema:
+              type: array
+              items: {}
+      responses:
+        "204":
+          description: uploaded
+components:
+  securitySchemes:
+    token:
+      type: http
+      scheme: bearer
+`
+
+func TestValidationContext(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(contextSpec))
+	require.NoError(t, err)
+	router := openapi3filter.NewRouter().WithSwagger(swagger)
+
+	decoders := openapi3filter.NewBodyDecoders()
+	decoders.RegisterContext("application/x-slow", func(c context.Context, data []byte) (interface{}, error) {
+		<-c.Done()
+		return nil, c.Err()
+	})
+	authenticated := make(chan struct{})
+	options := &openapi3filter.Options{
+		BodyDecoders: decoders,
+		AuthenticationFunc: func(c context.Context, input *openapi3filter.AuthenticationInput) error {
+			select {
+			case <-authenticated:
+				return nil
+			case <-c.Done():
+				return c.Err()
+			}
+		},
+	}
+	validate := func(c context.Context, contentType string, body string) error {
+		req := httptest.NewRequest(http.MethodPost, "/uploads", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", contentType)
+		route, pathParams, err := router.FindRoute(req.Method, req.URL)
+		require.NoError(t, err)
+		return openapi3filter.ValidateRequest(c, &openapi3filter.RequestValidationInput{
+			Request:    req,
+			PathParams: pathParams,
+			Route:      route,
+			Options:    options,
+		})
+	}
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.Equal(t, context.Canceled, validate(canceled, "application/json", "[]"))
+
+	c, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	require.Equal(t, context.DeadlineExceeded, validate(c, "application/x-slow", "{}"))
+
+	c, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	require.Equal(t, context.DeadlineExceeded, validate(c, "application/json", "[]"), "authentication must be time-bound")
+
+	close(authenticated)
+	require.NoError(t, validate(context.Background(), "application/json", "[]"))
+}
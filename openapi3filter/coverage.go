@@ -0,0 +1,287 @@
+package openapi3filter
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Coverage records statuses and media types of responses that have been validated
+// with the option Coverage, so documented responses that no test exercises can be reported.
+//
+// Coverage is safe for concurrent use.
+//
+// Example:
+//
+//	coverage := openapi3filter.NewCoverage()
+//	options := &openapi3filter.Options{Coverage: coverage}
+//	// Run contract tests with the options...
+//	report := coverage.Report(swagger)
+//	if !report.Covered() {
+//		t.Fatal(report)
+//	}
+type Coverage struct {
+	mu      sync.Mutex
+	records map[coverageKey]*coverageCount
+}
+
+type coverageKey struct {
+	operation *openapi3.Operation
+	status    string
+	mediaType string
+}
+
+type coverageCount struct {
+	passed int
+	failed int
+}
+
+func NewCoverage() *Coverage {
+	return &Coverage{
+		records: make(map[coverageKey]*coverageCount),
+	}
+}
+
+// Reset forgets recorded responses.
+func (coverage *Coverage) Reset() {
+	coverage.mu.Lock()
+	coverage.records = make(map[coverageKey]*coverageCount)
+	coverage.mu.Unlock()
+}
+
+// recordResponse records the response under the status and the media type that the response declares.
+func (coverage *Coverage) recordResponse(input *ResponseValidationInput, passed bool) {
+	route := input.RequestValidationInput.Route
+	if route == nil || route.Operation == nil {
+		return
+	}
+	responses := route.Operation.Responses
+	_, status := responses.ResolveStatus(input.Status)
+	if status == "" {
+		status = strconv.Itoa(input.Status)
+	}
+	mediaType := ""
+	if responseRef := responses[status]; responseRef == nil || responseRef.Value == nil || len(responseRef.Value.Content) > 0 {
+		mediaType = parseMediaType(input.Header.Get("Content-Type"))
+	}
+	key := coverageKey{
+		operation: route.Operation,
+		status:    status,
+		mediaType: mediaType,
+	}
+	coverage.mu.Lock()
+	defer coverage.mu.Unlock()
+	count := coverage.records[key]
+	if count == nil {
+		count = &coverageCount{}
+		coverage.records[key] = count
+	}
+	if passed {
+		count.passed++
+	} else {
+		count.failed++
+	}
+}
+
+// CoverageReport lists documented responses of operations with the number of times
+// they passed or failed validation.
+type CoverageReport struct {
+	Operations []*OperationCoverage
+
+	// Undocumented lists responses with a status or a media type that the document doesn't declare.
+	Undocumented []*ResponseCoverage
+}
+
+// OperationCoverage is the coverage of responses of an operation.
+type OperationCoverage struct {
+	Method    string
+	Path      string
+	Operation *openapi3.Operation
+	Responses []*ResponseCoverage
+}
+
+// ResponseCoverage is the coverage of a media type of a response.
+// MediaType is empty for responses without content.
+type ResponseCoverage struct {
+	Method    string
+	Path      string
+	Status    string
+	MediaType string
+	Passed    int
+	Failed    int
+}
+
+// Covered returns true if the response passed validation at least once.
+func (coverage *ResponseCoverage) Covered() bool {
+	return coverage.Passed > 0
+}
+
+// Covered returns true if a response of the operation passed validation at least once.
+func (coverage *OperationCoverage) Covered() bool {
+	for _, response := range coverage.Responses {
+		if response.Covered() {
+			return true
+		}
+	}
+	return false
+}
+
+// Covered returns true if every operation is covered.
+func (report *CoverageReport) Covered() bool {
+	return len(report.Uncovered()) == 0
+}
+
+// Uncovered returns operations without a response that passed validation.
+func (report *CoverageReport) Uncovered() []*OperationCoverage {
+	var result []*OperationCoverage
+	for _, operation := range report.Operations {
+		if !operation.Covered() {
+			result = append(result, operation)
+		}
+	}
+	return result
+}
+
+// Ratio returns the ratio of covered responses to documented responses.
+func (report *CoverageReport) Ratio() float64 {
+	total, covered := 0, 0
+	for _, operation := range report.Operations {
+		for _, response := range operation.Responses {
+			total++
+			if response.Covered() {
+				covered++
+			}
+		}
+	}
+	if total == 0 {
+		return 1
+	}
+	return float64(covered) / float64(total)
+}
+
+func (report *CoverageReport) String() string {
+	var buf strings.Builder
+	for _, operation := range report.Operations {
+		for _, response := range operation.Responses {
+			mark := "    "
+			if !response.Covered() {
+				mark = "MISS"
+			}
+			fmt.Fprintf(&buf, "%s %s %s %s %s (passed %d, failed %d)\n",
+				mark, operation.Method, operation.Path, response.Status, response.MediaType, response.Passed, response.Failed)
+		}
+	}
+	for _, response := range report.Undocumented {
+		fmt.Fprintf(&buf, "UNDOCUMENTED %s %s %s %s (passed %d, failed %d)\n",
+			response.Method, response.Path, response.Status, response.MediaType, response.Passed, response.Failed)
+	}
+	fmt.Fprintf(&buf, "%.1f%% of responses covered\n", 100*report.Ratio())
+	return buf.String()
+}
+
+// Report returns coverage of responses that operations of the document declare.
+func (coverage *Coverage) Report(swagger *openapi3.Swagger) *CoverageReport {
+	coverage.mu.Lock()
+	defer coverage.mu.Unlock()
+	report := &CoverageReport{}
+	documented := make(map[coverageKey]bool)
+	locations := make(map[*openapi3.Operation]*OperationCoverage)
+	paths := make([]string, 0, len(swagger.Paths))
+	for path := range swagger.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		operations := swagger.Paths[path].Operations()
+		methods := make([]string, 0, len(operations))
+		for method := range operations {
+			methods = append(methods, method)
+		}
+		sort.Strings(methods)
+		for _, method := range methods {
+			operation := operations[method]
+			operationCoverage := &OperationCoverage{
+				Method:    method,
+				Path:      path,
+				Operation: operation,
+			}
+			locations[operation] = operationCoverage
+			for _, key := range documentedResponses(operation) {
+				documented[key] = true
+				responseCoverage := &ResponseCoverage{
+					Method:    method,
+					Path:      path,
+					Status:    key.status,
+					MediaType: key.mediaType,
+				}
+				if count := coverage.records[key]; count != nil {
+					responseCoverage.Passed = count.passed
+					responseCoverage.Failed = count.failed
+				}
+				operationCoverage.Responses = append(operationCoverage.Responses, responseCoverage)
+			}
+			report.Operations = append(report.Operations, operationCoverage)
+		}
+	}
+	for key, count := range coverage.records {
+		location := locations[key.operation]
+		if documented[key] || location == nil {
+			continue
+		}
+		report.Undocumented = append(report.Undocumented, &ResponseCoverage{
+			Method:    location.Method,
+			Path:      location.Path,
+			Status:    key.status,
+			MediaType: key.mediaType,
+			Passed:    count.passed,
+			Failed:    count.failed,
+		})
+	}
+	sort.Slice(report.Undocumented, func(i, j int) bool {
+		a, b := report.Undocumented[i], report.Undocumented[j]
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		if a.Method != b.Method {
+			return a.Method < b.Method
+		}
+		if a.Status != b.Status {
+			return a.Status < b.Status
+		}
+		return a.MediaType < b.MediaType
+	})
+	return report
+}
+
+// documentedResponses returns keys of statuses and media types of responses of the operation.
+func documentedResponses(operation *openapi3.Operation) []coverageKey {
+	statuses := make([]string, 0, len(operation.Responses))
+	for status := range operation.Responses {
+		statuses = append(statuses, status)
+	}
+	sort.Strings(statuses)
+	var keys []coverageKey
+	for _, status := range statuses {
+		response := operation.Responses[status].Value
+		if response == nil {
+			continue
+		}
+		if len(response.Content) == 0 {
+			keys = append(keys, coverageKey{operation: operation, status: status})
+			continue
+		}
+		mediaTypes := make([]string, 0, len(response.Content))
+		for mediaType := range response.Content {
+			mediaTypes = append(mediaTypes, mediaType)
+		}
+		sort.Strings(mediaTypes)
+		for _, mediaType := range mediaTypes {
+			keys = append(keys, coverageKey{operation: operation, status: status, mediaType: mediaType})
+		}
+	}
+	return keys
+}
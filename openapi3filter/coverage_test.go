@@ -0,0 +1,91 @@
+package openapi3filter_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/stretchr/testify/require"
+)
+
+const coverageSpec = `
+openapi: 3.0.0
+info:
+  title: Users
+  version: "1.0"
+paths:
+  /users:
+    get:
+      responses:
+        "200":
+          description: users
+          content:
+            application/json:
+              schema:
+                type: array
+                items: {}
+            text/csv:
+              schema:
+                type: array
+                items: {}
+        default:
+          description: error
+    delete:
+      responses:
+        "204":
+          description: deleted
+`
+
+func TestCoverage(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(coverageSpec))
+	require.NoError(t, err)
+	router := openapi3filter.NewRouter().WithSwagger(swagger)
+	coverage := openapi3filter.NewCoverage()
+	options := &openapi3filter.Options{Coverage: coverage}
+
+	validate := func(method string, status int, contentType string, body string) {
+		req := httptest.NewRequest(method, "/users", nil)
+		route, _, err := router.FindRoute(req.Method, req.URL)
+		require.NoError(t, err)
+		input := &openapi3filter.ResponseValidationInput{
+			RequestValidationInput: &openapi3filter.RequestValidationInput{
+				Request: req,
+				Route:   route,
+			},
+			Status:  status,
+			Header:  http.Header{"Content-Type": {contentType}},
+			Options: options,
+		}
+		openapi3filter.ValidateResponse(context.Background(), input.SetBodyBytes([]byte(body)))
+	}
+	validate(http.MethodGet, 200, "application/json", `[]`)
+	validate(http.MethodGet, 200, "application/json", `{}`)
+	validate(http.MethodGet, 500, "text/plain", `failed`)
+	validate(http.MethodGet, 200, "application/xml", `<users/>`)
+
+	report := coverage.Report(swagger)
+	require.False(t, report.Covered())
+	require.Len(t, report.Operations, 2)
+	uncovered := report.Uncovered()
+	require.Len(t, uncovered, 1)
+	require.Equal(t, "DELETE", uncovered[0].Method)
+
+	get := report.Operations[1]
+	require.Equal(t, "GET", get.Method)
+	require.Len(t, get.Responses, 3)
+	require.Equal(t, &openapi3filter.ResponseCoverage{Method: "GET", Path: "/users", Status: "200", MediaType: "application/json", Passed: 1, Failed: 1}, get.Responses[0])
+	require.False(t, get.Responses[1].Covered())
+	require.Equal(t, "default", get.Responses[2].Status)
+	require.True(t, get.Responses[2].Covered())
+	require.InDelta(t, 0.5, report.Ratio(), 0.001)
+
+	require.Len(t, report.Undocumented, 1)
+	require.Equal(t, "application/xml", report.Undocumented[0].MediaType)
+	require.Equal(t, 1, report.Undocumented[0].Failed)
+
+	coverage.Reset()
+	require.Equal(t, 0.0, coverage.Report(swagger).Ratio())
+}
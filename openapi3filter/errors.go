@@ -50,7 +50,7 @@ func (err *RequestError) Error() string {
 		}
 	}
 	if v := err.Parameter; v != nil {
-		return fmt.Sprintf("Parameter '%s' in %s has an error: %s", v.Name, v.In, reason)
+		return fmt.Sprintf("Parameter '%s' in %s has an error: %s", openapi3.ErrorMessages.FieldName(v.Name), v.In, reason)
 	} else if v := err.RequestBody; v != nil {
 		return fmt.Sprintf("Request body has an error: %s", reason)
 	} else {
@@ -58,6 +58,10 @@ func (err *RequestError) Error() string {
 	}
 }
 
+func (err *RequestError) Unwrap() error {
+	return err.Err
+}
+
 type ResponseError struct {
 	Input  *ResponseValidationInput
 	Reason string
@@ -76,6 +80,10 @@ func (err *ResponseError) Error() string {
 	return reason
 }
 
+func (err *ResponseError) Unwrap() error {
+	return err.Err
+}
+
 type SecurityRequirementsError struct {
 	SecurityRequirements openapi3.SecurityRequirements
 	Errors               []error
@@ -0,0 +1,168 @@
+package openapi3filter
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// exampleTokenPattern matches tokens like "{{now}}" in strings of examples.
+var exampleTokenPattern = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_.\-]+)\s*\}\}`)
+
+// ExampleValues replaces tokens in strings of examples with dynamic values, so mocked responses look real:
+//
+//	"{{now}}"             the current time like "2020-01-02T15:04:05Z"
+//	"{{now.date}}"        the current date like "2020-01-02"
+//	"{{now.unix}}"        the current Unix time in seconds
+//	"{{seq}}"             the next number of a sequence starting from 1
+//	"{{seq.users}}"       the next number of the sequence "users"
+//	"{{request.id}}"      the decoded value of the parameter "id", which may be qualified like "request.path.id"
+//
+// A string that is a single token is replaced by the value itself, so "{{seq}}" is a number,
+// while tokens inside other strings are formatted like "/users/{{seq}}".
+// A sequence has the same number everywhere in one rendered example.
+//
+// ExampleValues is safe for concurrent use.
+type ExampleValues struct {
+	// Now returns the current time. If nil, time.Now is used.
+	Now func() time.Time
+
+	mu        sync.Mutex
+	sequences map[string]int64
+}
+
+// NewExampleValues returns values with sequences starting from 1.
+func NewExampleValues() *ExampleValues {
+	return &ExampleValues{
+		sequences: make(map[string]int64),
+	}
+}
+
+// Render returns a copy of the example value where tokens are replaced.
+// The input is the validated request that the example responds to.
+func (values *ExampleValues) Render(input *RequestValidationInput, value interface{}) (interface{}, error) {
+	r := &exampleRenderer{
+		values:    values,
+		input:     input,
+		sequences: make(map[string]int64),
+	}
+	return r.render(value)
+}
+
+// next returns the next number of the sequence.
+func (values *ExampleValues) next(name string) int64 {
+	values.mu.Lock()
+	defer values.mu.Unlock()
+	if values.sequences == nil {
+		values.sequences = make(map[string]int64)
+	}
+	values.sequences[name]++
+	return values.sequences[name]
+}
+
+type exampleRenderer struct {
+	values    *ExampleValues
+	input     *RequestValidationInput
+	sequences map[string]int64
+	now       *time.Time
+}
+
+func (r *exampleRenderer) render(value interface{}) (interface{}, error) {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(value))
+		for k, v := range value {
+			rendered, err := r.render(v)
+			if err != nil {
+				return nil, err
+			}
+			result[k] = rendered
+		}
+		return result, nil
+	case []interface{}:
+		result := make([]interface{}, 0, len(value))
+		for _, v := range value {
+			rendered, err := r.render(v)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, rendered)
+		}
+		return result, nil
+	case string:
+		return r.renderString(value)
+	default:
+		return value, nil
+	}
+}
+
+func (r *exampleRenderer) renderString(s string) (interface{}, error) {
+	if !strings.Contains(s, "{{") {
+		return s, nil
+	}
+	if m := exampleTokenPattern.FindStringSubmatchIndex(s); m != nil && m[0] == 0 && m[1] == len(s) {
+		return r.token(s[m[2]:m[3]])
+	}
+	var err error
+	result := exampleTokenPattern.ReplaceAllStringFunc(s, func(token string) string {
+		value, tokenErr := r.token(exampleTokenPattern.FindStringSubmatch(token)[1])
+		if tokenErr != nil {
+			err = tokenErr
+			return token
+		}
+		if value == nil {
+			return ""
+		}
+		return fmt.Sprint(value)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// token returns the value of the token.
+func (r *exampleRenderer) token(name string) (interface{}, error) {
+	switch {
+	case name == "now":
+		return r.time().Format(time.RFC3339), nil
+	case name == "now.date":
+		return r.time().Format("2006-01-02"), nil
+	case name == "now.unix":
+		return float64(r.time().Unix()), nil
+	case name == "seq" || strings.HasPrefix(name, "seq."):
+		n, ok := r.sequences[name]
+		if !ok {
+			n = r.values.next(name)
+			r.sequences[name] = n
+		}
+		return float64(n), nil
+	case strings.HasPrefix(name, "request."):
+		key := name[len("request."):]
+		if r.input == nil || r.input.Route == nil {
+			return nil, fmt.Errorf("Token '%s' needs a request", name)
+		}
+		parameter := r.input.Route.PathItem.FindLinkParameter(r.input.Route.Operation, key)
+		if parameter == nil {
+			return nil, fmt.Errorf("Token '%s' refers to parameter '%s' that is not declared", name, key)
+		}
+		return r.input.ParameterValues[parameter], nil
+	default:
+		return nil, fmt.Errorf("Token '%s' is not supported", name)
+	}
+}
+
+// time returns the same time for every token of the example.
+func (r *exampleRenderer) time() time.Time {
+	if r.now == nil {
+		now := time.Now
+		if r.values.Now != nil {
+			now = r.values.Now
+		}
+		t := now().UTC()
+		r.now = &t
+	}
+	return *r.now
+}
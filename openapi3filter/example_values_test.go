@@ -0,0 +1,68 @@
+package openapi3filter_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/stretchr/testify/require"
+)
+
+const exampleValuesSpec = `
+openapi: 3.0.0
+info:
+  title: Orders
+  version: "1.0"
+paths:
+  /users/{user}/orders:
+    post:
+      parameters:
+        - name: user
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        "201":
+          description: order
+          content:
+            application/json:
+              example:
+                id: "{{seq}}"
+                self: "/users/{{request.user}}/orders/{{seq}}"
+                user: "{{request.path.user}}"
+                created: "{{now}}"
+                date: "{{ now.date }}"
+`
+
+func TestExampleValues(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(exampleValuesSpec))
+	require.NoError(t, err)
+	handler := openapi3filter.NewMockHandler(openapi3filter.NewRouter().WithSwagger(swagger), nil)
+	handler.Values.Now = func() time.Time {
+		return time.Date(2020, 1, 2, 15, 4, 5, 0, time.UTC)
+	}
+
+	for _, body := range []string{
+		`{"id":1,"self":"/users/alice/orders/1","user":"alice","created":"2020-01-02T15:04:05Z","date":"2020-01-02"}`,
+		`{"id":2,"self":"/users/alice/orders/2","user":"alice","created":"2020-01-02T15:04:05Z","date":"2020-01-02"}`,
+	} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/users/alice/orders", nil))
+		require.Equal(t, http.StatusCreated, rec.Code)
+		require.JSONEq(t, body, rec.Body.String())
+	}
+
+	values := openapi3filter.NewExampleValues()
+	value, err := values.Render(nil, []interface{}{"{{seq.a}}", "{{seq.b}}", "{{seq.a}}", map[string]interface{}{"n": 1.0}})
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{1.0, 1.0, 1.0, map[string]interface{}{"n": 1.0}}, value)
+
+	_, err = values.Render(nil, "{{unknown}}")
+	require.EqualError(t, err, "Token 'unknown' is not supported")
+	_, err = values.Render(nil, "{{request.id}}")
+	require.EqualError(t, err, "Token 'request.id' needs a request")
+}
@@ -0,0 +1,165 @@
+package openapi3filter
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// ExtHeaderPropagation is the extension of a document or an operation that declares
+// headers a reverse proxy must forward or strip:
+//
+//	x-header-propagation:
+//	  request:
+//	    forward: [X-Request-ID]
+//	    strip: [X-Internal-Token]
+//	  response:
+//	    strip: [Server]
+//
+// Rules of an operation are added to rules of the document.
+const ExtHeaderPropagation = "x-header-propagation"
+
+// HeaderPropagation describes headers that a reverse proxy must forward or strip.
+type HeaderPropagation struct {
+	// Request rules compare the request received by the proxy with the request sent upstream.
+	Request HeaderPropagationRules `json:"request,omitempty"`
+
+	// Response rules compare the response received from upstream with the response sent by the proxy.
+	Response HeaderPropagationRules `json:"response,omitempty"`
+}
+
+// HeaderPropagationRules lists headers that must be forwarded or stripped.
+type HeaderPropagationRules struct {
+	// Forward headers must be passed on with the same values when they're present.
+	Forward []string `json:"forward,omitempty"`
+
+	// Strip headers must not be passed on.
+	Strip []string `json:"strip,omitempty"`
+}
+
+// HeaderPropagationViolation describes a header that wasn't forwarded or stripped.
+type HeaderPropagationViolation struct {
+	// Direction is either "request" or "response".
+	Direction string
+
+	// Rule is either "forward" or "strip".
+	Rule   string
+	Header string
+}
+
+func (violation *HeaderPropagationViolation) String() string {
+	if violation.Rule == "strip" {
+		return fmt.Sprintf("%s header '%s' must be stripped", violation.Direction, violation.Header)
+	}
+	return fmt.Sprintf("%s header '%s' must be forwarded", violation.Direction, violation.Header)
+}
+
+// HeaderPropagationError lists violations of header propagation rules.
+type HeaderPropagationError struct {
+	Route      *Route
+	Violations []*HeaderPropagationViolation
+}
+
+func (err *HeaderPropagationError) Error() string {
+	messages := make([]string, 0, len(err.Violations))
+	for _, violation := range err.Violations {
+		messages = append(messages, violation.String())
+	}
+	return "Headers violate propagation rules: " + strings.Join(messages, ", ")
+}
+
+// GetHeaderPropagation returns header propagation rules of an operation of the document.
+// The function returns nil if neither the document nor the operation has the extension ExtHeaderPropagation.
+func GetHeaderPropagation(swagger *openapi3.Swagger, operation *openapi3.Operation) (*HeaderPropagation, error) {
+	var result *HeaderPropagation
+	for _, props := range []openapi3.ExtensionProps{swagger.ExtensionProps, operation.ExtensionProps} {
+		propagation, err := headerPropagationExtension(props)
+		if err != nil {
+			return nil, err
+		}
+		if propagation == nil {
+			continue
+		}
+		if result == nil {
+			result = &HeaderPropagation{}
+		}
+		result.Request.Forward = append(result.Request.Forward, propagation.Request.Forward...)
+		result.Request.Strip = append(result.Request.Strip, propagation.Request.Strip...)
+		result.Response.Forward = append(result.Response.Forward, propagation.Response.Forward...)
+		result.Response.Strip = append(result.Response.Strip, propagation.Response.Strip...)
+	}
+	return result, nil
+}
+
+func headerPropagationExtension(props openapi3.ExtensionProps) (*HeaderPropagation, error) {
+	switch v := props.Extensions[ExtHeaderPropagation].(type) {
+	case nil:
+		return nil, nil
+	case *HeaderPropagation:
+		return v, nil
+	case json.RawMessage:
+		result := &HeaderPropagation{}
+		if err := json.Unmarshal(v, result); err != nil {
+			return nil, fmt.Errorf("Extension '%s' is invalid: %v", ExtHeaderPropagation, err)
+		}
+		return result, nil
+	default:
+		return nil, fmt.Errorf("Extension '%s' has unsupported type %T", ExtHeaderPropagation, v)
+	}
+}
+
+// ValidateRequestHeaderPropagation compares headers of a request received by a reverse proxy
+// with headers of the request that the proxy sends upstream.
+//
+// The function returns HeaderPropagationError if a header violates a rule.
+func ValidateRequestHeaderPropagation(route *Route, received http.Header, sent http.Header) error {
+	return validateHeaderPropagation(route, "request", received, sent)
+}
+
+// ValidateResponseHeaderPropagation compares headers of a response received by a reverse proxy
+// from upstream with headers of the response that the proxy sends.
+//
+// The function returns HeaderPropagationError if a header violates a rule.
+func ValidateResponseHeaderPropagation(route *Route, received http.Header, sent http.Header) error {
+	return validateHeaderPropagation(route, "response", received, sent)
+}
+
+func validateHeaderPropagation(route *Route, direction string, received http.Header, sent http.Header) error {
+	if route == nil || route.Operation == nil {
+		return errRouteMissingOperation
+	}
+	if route.Swagger == nil {
+		return errRouteMissingSwagger
+	}
+	propagation, err := GetHeaderPropagation(route.Swagger, route.Operation)
+	if err != nil || propagation == nil {
+		return err
+	}
+	rules := propagation.Request
+	if direction == "response" {
+		rules = propagation.Response
+	}
+	var violations []*HeaderPropagationViolation
+	for _, name := range rules.Forward {
+		values := received[http.CanonicalHeaderKey(name)]
+		if len(values) > 0 && !reflect.DeepEqual(values, sent[http.CanonicalHeaderKey(name)]) {
+			violations = append(violations, &HeaderPropagationViolation{Direction: direction, Rule: "forward", Header: name})
+		}
+	}
+	for _, name := range rules.Strip {
+		if len(sent[http.CanonicalHeaderKey(name)]) > 0 {
+			violations = append(violations, &HeaderPropagationViolation{Direction: direction, Rule: "strip", Header: name})
+		}
+	}
+	if len(violations) > 0 {
+		return &HeaderPropagationError{
+			Route:      route,
+			Violations: violations,
+		}
+	}
+	return nil
+}
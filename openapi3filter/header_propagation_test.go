@@ -0,0 +1,65 @@
+package openapi3filter_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/stretchr/testify/require"
+)
+
+const headerPropagationSpec = `
+openapi: 3.0.0
+info:
+  title: Gateway
+  version: "1.0"
+x-header-propagation:
+  request:
+    forward: [X-Request-ID]
+    strip: [X-Internal-Token]
+paths:
+  /orders:
+    get:
+      x-header-propagation:
+        request:
+          forward: [Accept-Language]
+        response:
+          strip: [Server]
+      responses:
+        "200":
+          description: orders
+`
+
+func TestHeaderPropagation(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(headerPropagationSpec))
+	require.NoError(t, err)
+	router := openapi3filter.NewRouter().WithSwagger(swagger)
+	req := httptest.NewRequest(http.MethodGet, "/orders", nil)
+	route, _, err := router.FindRoute(req.Method, req.URL)
+	require.NoError(t, err)
+
+	propagation, err := openapi3filter.GetHeaderPropagation(swagger, route.Operation)
+	require.NoError(t, err)
+	require.Equal(t, []string{"X-Request-ID", "Accept-Language"}, propagation.Request.Forward)
+
+	received := http.Header{}
+	received.Set("X-Request-ID", "1")
+	received.Set("X-Internal-Token", "secret")
+	sent := http.Header{}
+	sent.Set("X-Request-ID", "1")
+	require.NoError(t, openapi3filter.ValidateRequestHeaderPropagation(route, received, sent))
+
+	sent.Del("X-Request-ID")
+	sent.Set("X-Internal-Token", "secret")
+	err = openapi3filter.ValidateRequestHeaderPropagation(route, received, sent)
+	require.IsType(t, &openapi3filter.HeaderPropagationError{}, err)
+	require.Len(t, err.(*openapi3filter.HeaderPropagationError).Violations, 2)
+	require.EqualError(t, err, "Headers violate propagation rules: request header 'X-Request-ID' must be forwarded, request header 'X-Internal-Token' must be stripped")
+
+	upstream := http.Header{}
+	upstream.Set("Server", "nginx")
+	require.NoError(t, openapi3filter.ValidateResponseHeaderPropagation(route, upstream, http.Header{}))
+	require.Error(t, openapi3filter.ValidateResponseHeaderPropagation(route, upstream, upstream))
+}
@@ -0,0 +1,116 @@
+package openapi3filter_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/stretchr/testify/require"
+)
+
+const hooksSpec = `
+openapi: 3.0.0
+info:
+  title: Shapes
+  version: "1.0"
+paths:
+  /shapes:
+    post:
+      parameters:
+        - name: X-Tenant
+          in: header
+          required: true
+          schema:
+            type: string
+      requestBody:
+        content:
+          application/json:
+            schema:
+              oneOf:
+                - type: object
+                  required: [radius]
+                  properties:
+                    radius:
+                      type: number
+                - type: object
+                  required: [side]
+                  properties:
+                    side:
+                      type: number
+      responses:
+        "201":
+          description: created
+`
+
+func TestValidationHooks(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(hooksSpec))
+	require.NoError(t, err)
+	router := openapi3filter.NewRouter().WithSwagger(swagger)
+	validate := func(options *openapi3filter.Options, tenant string, body string) error {
+		req := httptest.NewRequest(http.MethodPost, "/shapes", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		if tenant != "" {
+			req.Header.Set("X-Tenant", tenant)
+		}
+		route, pathParams, err := router.FindRoute(req.Method, req.URL)
+		require.NoError(t, err)
+		return openapi3filter.ValidateRequest(context.Background(), &openapi3filter.RequestValidationInput{
+			Request:    req,
+			PathParams: pathParams,
+			Route:      route,
+			Options:    options,
+		})
+	}
+
+	errBlocked := errors.New("tenant is blocked")
+	options := &openapi3filter.Options{
+		BeforeParameterDecode: func(c context.Context, input *openapi3filter.RequestValidationInput, parameter *openapi3.Parameter) error {
+			switch input.Request.Header.Get(parameter.Name) {
+			case "":
+				input.Request.Header.Set(parameter.Name, "default")
+			case "blocked":
+				return errBlocked
+			}
+			return nil
+		},
+	}
+	require.NoError(t, validate(options, "", `{"radius": 1}`))
+	err = validate(options, "blocked", `{"radius": 1}`)
+	require.IsType(t, &openapi3filter.RequestError{}, err)
+	require.Equal(t, "X-Tenant", err.(*openapi3filter.RequestError).Parameter.Name)
+	require.True(t, errors.Is(err, errBlocked))
+
+	options = &openapi3filter.Options{
+		AfterBodyDecode: func(c context.Context, input *openapi3filter.BodyDecodeInput, value interface{}) (interface{}, error) {
+			require.Equal(t, "application/json", input.MediaType)
+			require.Nil(t, input.ResponseValidationInput)
+			object := value.(map[string]interface{})
+			if diameter, ok := object["diameter"]; ok {
+				return map[string]interface{}{"radius": diameter.(float64) / 2}, nil
+			}
+			return value, nil
+		},
+	}
+	require.NoError(t, validate(options, "a", `{"diameter": 2}`))
+	require.Error(t, validate(options, "a", `{"width": 2}`))
+
+	var failedFields []string
+	options = &openapi3filter.Options{
+		OnValidationError: func(c context.Context, err error) error {
+			var schemaErr *openapi3.SchemaError
+			if errors.As(err, &schemaErr) {
+				failedFields = append(failedFields, schemaErr.SchemaField)
+				return nil
+			}
+			return err
+		},
+	}
+	require.NoError(t, validate(options, "a", `{"radius": 1, "side": 1}`))
+	require.Equal(t, []string{"oneOf"}, failedFields)
+	require.Error(t, validate(options, "", `{"radius": 1}`))
+}
@@ -0,0 +1,197 @@
+package openapi3filter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// ExtIdempotencyKey is the extension of an operation that requires clients to send an idempotency key.
+//
+// The value is either true, which requires the header DefaultIdempotencyKeyHeader,
+// or an object described by IdempotencyKey.
+const ExtIdempotencyKey = "x-idempotency-key"
+
+// DefaultIdempotencyKeyHeader is the header that contains an idempotency key unless the extension names another one.
+// An operation that declares a header parameter with this name exposes its value as an idempotency key too.
+const DefaultIdempotencyKeyHeader = "Idempotency-Key"
+
+// IdempotencyKey describes the idempotency key of an operation.
+type IdempotencyKey struct {
+	// Header defaults to DefaultIdempotencyKeyHeader.
+	Header string `json:"header,omitempty"`
+	// Optional keys may be omitted by clients.
+	Optional bool `json:"optional,omitempty"`
+	// Schema describes the format of a key, such as its pattern or maximum length.
+	Schema *openapi3.Schema `json:"schema,omitempty"`
+
+	// parameter is the declared header parameter that is the key, if any.
+	parameter *openapi3.Parameter
+}
+
+// GetIdempotencyKey returns the idempotency key of an operation.
+// The function returns nil if the operation has neither the extension ExtIdempotencyKey
+// nor a header parameter named DefaultIdempotencyKeyHeader.
+// Parameters of the path item aren't considered, see Route.Parameters.
+func GetIdempotencyKey(operation *openapi3.Operation) (*IdempotencyKey, error) {
+	return getIdempotencyKey(operation, operation.Parameters)
+}
+
+// routeIdempotencyKey returns the idempotency key of the operation of a route,
+// which may be a header parameter of the path item.
+// Routers parse the key once when they add a route, see parseIdempotencyKey.
+// The result must not be modified.
+func routeIdempotencyKey(route *Route) (*IdempotencyKey, error) {
+	if parsed := route.idempotencyKey; parsed != nil {
+		return parsed.key, parsed.err
+	}
+	return getIdempotencyKey(route.Operation, route.Parameters())
+}
+
+// parsedIdempotencyKey is the idempotency key of a route or the error of parsing it.
+type parsedIdempotencyKey struct {
+	key *IdempotencyKey
+	err error
+}
+
+// parseIdempotencyKey parses the idempotency key of the route, so it isn't parsed by every request.
+func (route *Route) parseIdempotencyKey() {
+	key, err := getIdempotencyKey(route.Operation, route.Parameters())
+	route.idempotencyKey = &parsedIdempotencyKey{key: key, err: err}
+}
+
+func getIdempotencyKey(operation *openapi3.Operation, parameters openapi3.Parameters) (*IdempotencyKey, error) {
+	var result *IdempotencyKey
+	switch v := operation.Extensions[ExtIdempotencyKey].(type) {
+	case nil:
+	case bool:
+		if v {
+			result = &IdempotencyKey{}
+		}
+	case *IdempotencyKey:
+		copied := *v
+		result = &copied
+	case json.RawMessage:
+		if !bytes.Equal(v, []byte("false")) {
+			result = &IdempotencyKey{}
+			if !bytes.Equal(v, []byte("true")) {
+				if err := json.Unmarshal(v, result); err != nil {
+					return nil, fmt.Errorf("Extension '%s' is invalid: %v", ExtIdempotencyKey, err)
+				}
+			}
+		}
+	default:
+		return nil, fmt.Errorf("Extension '%s' has unsupported type %T", ExtIdempotencyKey, v)
+	}
+	if result == nil {
+		parameter := parameters.GetByInAndName(openapi3.ParameterInHeader, DefaultIdempotencyKeyHeader)
+		if parameter == nil {
+			return nil, nil
+		}
+		// The value is validated like any other parameter.
+		return &IdempotencyKey{Header: parameter.Name, Optional: !parameter.Required, parameter: parameter}, nil
+	}
+	if result.Header == "" {
+		result.Header = DefaultIdempotencyKeyHeader
+	}
+	return result, nil
+}
+
+// ValidateIdempotencyKey validates the idempotency key of a request and stores it in the input.
+// ValidateRequest calls it if Options.ValidateIdempotencyKeys is set.
+//
+// The function returns RequestError with ErrInvalidRequired cause when a required key is not defined.
+// The function returns RequestError with a openapi3.SchemaError cause when a key is invalid by JSON schema.
+func ValidateIdempotencyKey(c context.Context, input *RequestValidationInput) error {
+	return validateIdempotencyKey(c, input, false)
+}
+
+// validateIdempotencyKey validates the idempotency key of a request.
+// A key that is a header parameter is validated as a parameter unless parametersValidated is true.
+func validateIdempotencyKey(c context.Context, input *RequestValidationInput, parametersValidated bool) error {
+	route := input.Route
+	if route == nil || route.Operation == nil {
+		return errRouteMissingOperation
+	}
+	idempotencyKey, err := routeIdempotencyKey(route)
+	if err != nil || idempotencyKey == nil {
+		return err
+	}
+	header := idempotencyKey.Header
+	if parameter := idempotencyKey.parameter; parameter != nil {
+		if !parametersValidated {
+			if err := ValidateParameter(c, input, parameter); err != nil {
+				return err
+			}
+		}
+		input.IdempotencyKey = input.Request.Header.Get(header)
+		return nil
+	}
+	value := input.Request.Header.Get(header)
+	if value == "" {
+		if !idempotencyKey.Optional {
+			return &RequestError{
+				Input:     input,
+				Parameter: openapi3.NewHeaderParameter(header),
+				Reason:    "idempotency key must have a value",
+				Err:       ErrInvalidRequired,
+			}
+		}
+		return nil
+	}
+	if schema := idempotencyKey.Schema; schema != nil {
+		if err := schema.VisitJSONContext(c, value); err != nil {
+			return &RequestError{
+				Input:     input,
+				Parameter: openapi3.NewHeaderParameter(header),
+				Reason:    "idempotency key is invalid",
+				Err:       err,
+			}
+		}
+	}
+	input.IdempotencyKey = value
+	return nil
+}
+
+type idempotencyKeyContextKey struct{}
+
+// IdempotencyKeyFromContext returns the idempotency key stored by IdempotencyKeyMiddleware.
+func IdempotencyKeyFromContext(c context.Context) string {
+	v, _ := c.Value(idempotencyKeyContextKey{}).(string)
+	return v
+}
+
+// IdempotencyKeyMiddleware returns a handler that rejects requests with a missing or invalid idempotency key.
+// The key of a valid request is available to the next handler with IdempotencyKeyFromContext.
+//
+// Requests that don't match any route of the router are passed to the next handler as they are.
+func IdempotencyKeyMiddleware(router *Router, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		route, pathParams, err := router.FindRoute(req.Method, req.URL)
+		if err != nil {
+			next.ServeHTTP(w, req)
+			return
+		}
+		input := &RequestValidationInput{
+			Request:    req,
+			PathParams: pathParams,
+			Route:      route,
+		}
+		if err := ValidateIdempotencyKey(req.Context(), input); err != nil {
+			status := http.StatusInternalServerError
+			if requestError, ok := err.(*RequestError); ok {
+				status = requestError.HTTPStatus()
+			}
+			http.Error(w, err.Error(), status)
+			return
+		}
+		if input.IdempotencyKey != "" {
+			req = req.WithContext(context.WithValue(req.Context(), idempotencyKeyContextKey{}, input.IdempotencyKey))
+		}
+		next.ServeHTTP(w, req)
+	})
+}
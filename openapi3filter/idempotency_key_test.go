@@ -0,0 +1,158 @@
+package openapi3filter_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/stretchr/testify/require"
+)
+
+const idempotencyKeySpec = `
+openapi: 3.0.0
+info:
+  title: Payments
+  version: "1.0"
+paths:
+  /payments:
+    post:
+      x-idempotency-key:
+        schema:
+          type: string
+          pattern: "^[0-9a-f]{8}$"
+      responses:
+        "201":
+          description: created
+  /refunds:
+    post:
+      parameters:
+        - name: Idempotency-Key
+          in: header
+          schema:
+            type: string
+      responses:
+        "201":
+          description: created
+  /transfers:
+    post:
+      x-idempotency-key: true
+      responses:
+        "201":
+          description: created
+  /orders:
+    parameters:
+      - name: Idempotency-Key
+        in: header
+        required: true
+        schema:
+          type: string
+          maxLength: 8
+    post:
+      responses:
+        "201":
+          description: created
+`
+
+func TestValidateIdempotencyKey(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(idempotencyKeySpec))
+	require.NoError(t, err)
+	router := openapi3filter.NewRouter().WithSwagger(swagger)
+
+	options := &openapi3filter.Options{ValidateIdempotencyKeys: true}
+	validate := func(path string, key string) (string, error) {
+		req := httptest.NewRequest(http.MethodPost, path, nil)
+		if key != "" {
+			req.Header.Set("Idempotency-Key", key)
+		}
+		route, pathParams, err := router.FindRoute(req.Method, req.URL)
+		require.NoError(t, err)
+		input := &openapi3filter.RequestValidationInput{
+			Request:    req,
+			PathParams: pathParams,
+			Route:      route,
+			Options:    options,
+		}
+		err = openapi3filter.ValidateRequest(context.Background(), input)
+		return input.IdempotencyKey, err
+	}
+
+	key, err := validate("/payments", "0123abcd")
+	require.NoError(t, err)
+	require.Equal(t, "0123abcd", key)
+
+	_, err = validate("/payments", "not-a-key")
+	require.Error(t, err)
+	require.IsType(t, &openapi3filter.RequestError{}, err)
+
+	_, err = validate("/payments", "")
+	require.Error(t, err)
+	require.Equal(t, openapi3filter.ErrInvalidRequired, err.(*openapi3filter.RequestError).Err)
+
+	key, err = validate("/refunds", "")
+	require.NoError(t, err)
+	require.Empty(t, key)
+	key, err = validate("/refunds", "anything")
+	require.NoError(t, err)
+	require.Equal(t, "anything", key)
+
+	_, err = validate("/transfers", "")
+	require.Error(t, err)
+	key, err = validate("/transfers", "anything")
+	require.NoError(t, err)
+	require.Equal(t, "anything", key)
+
+	// Header parameters of path items are keys too.
+	key, err = validate("/orders", "12345678")
+	require.NoError(t, err)
+	require.Equal(t, "12345678", key)
+	_, err = validate("/orders", "")
+	require.Error(t, err)
+	_, err = validate("/orders", "123456789")
+	require.Error(t, err)
+
+	options = &openapi3filter.Options{ValidateIdempotencyKeys: true, ExcludeHeaderParameters: true}
+	_, err = validate("/payments", "not-a-key")
+	require.NoError(t, err, "header parameters are excluded")
+	options = &openapi3filter.Options{}
+	key, err = validate("/payments", "not-a-key")
+	require.NoError(t, err, "keys aren't validated by default")
+	require.Empty(t, key)
+}
+
+func TestIdempotencyKeyMiddleware(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(idempotencyKeySpec))
+	require.NoError(t, err)
+	router := openapi3filter.NewRouter().WithSwagger(swagger)
+
+	var handledKey string
+	handler := openapi3filter.IdempotencyKeyMiddleware(router, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		handledKey = openapi3filter.IdempotencyKeyFromContext(req.Context())
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/payments", nil)
+	req.Header.Set("Idempotency-Key", "0123abcd")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.Equal(t, "0123abcd", handledKey)
+
+	req = httptest.NewRequest(http.MethodPost, "/payments", nil)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	req = httptest.NewRequest(http.MethodPost, "/orders", nil)
+	req.Header.Set("Idempotency-Key", "123456789")
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/unknown", nil)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code)
+}
@@ -2,12 +2,28 @@ package openapi3filter
 
 import (
 	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
 )
 
 func parseMediaType(contentType string) string {
 	i := strings.IndexByte(contentType, ';')
-	if i < 0 {
-		return contentType
+	if i >= 0 {
+		contentType = contentType[:i]
+	}
+	return strings.ToLower(strings.TrimSpace(contentType))
+}
+
+// findMediaType returns the declaration of the media type, such as "application/json",
+// or of a range that includes it, such as "application/*" or "*/*".
+func findMediaType(content openapi3.Content, mediaType string) *openapi3.MediaType {
+	if v := content[mediaType]; v != nil {
+		return v
+	}
+	if i := strings.IndexByte(mediaType, '/'); i > 0 {
+		if v := content[mediaType[:i]+"/*"]; v != nil {
+			return v
+		}
 	}
-	return contentType[:i]
+	return content["*/*"]
 }
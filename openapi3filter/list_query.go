@@ -0,0 +1,211 @@
+package openapi3filter
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// ExtListQuery is the extension that marks a parameter of a list operation.
+// Its value is one of ListQueryFilter, ListQuerySort, ListQueryPage, or ListQueryLimit.
+const ExtListQuery = "x-list-query"
+
+// ExtListQueryField is the extension that names the property of listed items that
+// a filter parameter is applied to. By default, the name of the parameter is used.
+const ExtListQueryField = "x-list-query-field"
+
+const (
+	ListQueryFilter = "filter"
+	ListQuerySort   = "sort"
+	ListQueryPage   = "page"
+	ListQueryLimit  = "limit"
+)
+
+// ListQuery is a normalized query of a list operation.
+type ListQuery struct {
+	Filters []*ListFilter
+	Sort    []*ListSortField
+
+	// Page and Limit are zero when they are not defined by the request or the defaults of the parameters.
+	Page  int64
+	Limit int64
+}
+
+// ListFilter is a filter of listed items.
+// Value is decoded using the parameter's schema.
+type ListFilter struct {
+	Field     string
+	Value     interface{}
+	Parameter *openapi3.Parameter
+}
+
+// ListSortField is a field that listed items are sorted by.
+// A field is prefixed with "-" in the request when items are sorted in descending order.
+type ListSortField struct {
+	Field      string
+	Descending bool
+}
+
+// DecodeListQuery returns the query of a list operation described by parameters that have the extension ExtListQuery.
+//
+// The listed items are described by the schema of the items of the array returned by the 200 response.
+// Filters and sort fields must be properties of the listed items when they have declared properties.
+//
+// The function returns RequestError when the request has an invalid value of a parameter.
+func DecodeListQuery(input *RequestValidationInput) (*ListQuery, error) {
+	route := input.Route
+	if route == nil || route.Operation == nil {
+		return nil, errRouteMissingOperation
+	}
+	items := listItemsSchema(route.Operation)
+	result := &ListQuery{}
+	for _, parameter := range routeParameters(route) {
+		kind, err := stringExtension(parameter.ExtensionProps, ExtListQuery)
+		if err != nil {
+			return nil, err
+		}
+		if kind == "" {
+			continue
+		}
+		value, err := decodeAndValidateParameter(input.Request.Context(), input, parameter)
+		if err != nil {
+			return nil, err
+		}
+		if schema := parameterSchema(parameter); value == nil && schema != nil && schema.Value != nil {
+			value = schema.Value.Default
+		}
+		switch kind {
+		case ListQueryFilter:
+			if value == nil {
+				continue
+			}
+			field, err := stringExtension(parameter.ExtensionProps, ExtListQueryField)
+			if err != nil {
+				return nil, err
+			}
+			if field == "" {
+				field = parameter.Name
+			}
+			if !hasListItemsProperty(items, field) {
+				return nil, fmt.Errorf("Filter parameter '%s' refers to unknown property '%s'", parameter.Name, field)
+			}
+			result.Filters = append(result.Filters, &ListFilter{
+				Field:     field,
+				Value:     value,
+				Parameter: parameter,
+			})
+		case ListQuerySort:
+			sortFields, err := toListSortFields(value)
+			if err != nil {
+				return nil, &RequestError{Input: input, Parameter: parameter, Reason: err.Error()}
+			}
+			for _, sortField := range sortFields {
+				if !hasListItemsProperty(items, sortField.Field) {
+					return nil, &RequestError{Input: input, Parameter: parameter, Reason: fmt.Sprintf("can't sort by unknown field %q", sortField.Field)}
+				}
+			}
+			result.Sort = append(result.Sort, sortFields...)
+		case ListQueryPage, ListQueryLimit:
+			if value == nil {
+				continue
+			}
+			n, ok := value.(float64)
+			if !ok || n != float64(int64(n)) {
+				return nil, &RequestError{Input: input, Parameter: parameter, Reason: "must be an integer"}
+			}
+			if kind == ListQueryPage {
+				result.Page = int64(n)
+			} else {
+				result.Limit = int64(n)
+			}
+		default:
+			return nil, fmt.Errorf("Parameter '%s' has unsupported value of extension '%s': %q", parameter.Name, ExtListQuery, kind)
+		}
+	}
+	return result, nil
+}
+
+// listItemsSchema returns the schema of items returned by a list operation.
+func listItemsSchema(operation *openapi3.Operation) *openapi3.Schema {
+	response := operation.Responses.Get(200)
+	if response == nil || response.Value == nil {
+		return nil
+	}
+	mediaType := response.Value.Content.Get("application/json")
+	if mediaType == nil || mediaType.Schema == nil || mediaType.Schema.Value == nil {
+		return nil
+	}
+	schema := mediaType.Schema.Value
+	if schema.Type != "array" || schema.Items == nil {
+		return nil
+	}
+	return schema.Items.Value
+}
+
+func hasListItemsProperty(items *openapi3.Schema, name string) bool {
+	if items == nil || len(items.Properties) == 0 {
+		// Fields of the items are unknown.
+		return true
+	}
+	_, ok := items.Properties[name]
+	return ok
+}
+
+// toListSortFields parses sort fields from a string or an array of strings.
+// Every string may contain comma-separated fields.
+func toListSortFields(value interface{}) ([]*ListSortField, error) {
+	var fields []string
+	switch value := value.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		fields = strings.Split(value, ",")
+	case []interface{}:
+		for _, item := range value {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("sort field must be a string, not %T", item)
+			}
+			fields = append(fields, strings.Split(s, ",")...)
+		}
+	default:
+		return nil, fmt.Errorf("sort fields must be a string or an array, not %T", value)
+	}
+	result := make([]*ListSortField, 0, len(fields))
+	for _, field := range fields {
+		sortField := &ListSortField{}
+		switch {
+		case strings.HasPrefix(field, "-"):
+			sortField.Descending = true
+			field = field[1:]
+		case strings.HasPrefix(field, "+"):
+			field = field[1:]
+		}
+		if field == "" {
+			return nil, fmt.Errorf("sort field must not be empty")
+		}
+		sortField.Field = field
+		result = append(result, sortField)
+	}
+	return result, nil
+}
+
+// stringExtension returns the value of an extension that must be a string.
+func stringExtension(props openapi3.ExtensionProps, name string) (string, error) {
+	switch v := props.Extensions[name].(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case json.RawMessage:
+		var s string
+		if err := json.Unmarshal(v, &s); err != nil {
+			return "", fmt.Errorf("Extension '%s' must be a string: %v", name, err)
+		}
+		return s, nil
+	default:
+		return "", fmt.Errorf("Extension '%s' must be a string, not %T", name, v)
+	}
+}
@@ -0,0 +1,138 @@
+package openapi3filter_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/stretchr/testify/require"
+)
+
+const listQuerySpec = `
+openapi: 3.0.0
+info:
+  title: Pets
+  version: "1.0"
+paths:
+  /pets:
+    get:
+      parameters:
+        - name: status
+          in: query
+          x-list-query: filter
+          schema:
+            type: string
+            enum: [available, sold]
+        - name: min_age
+          in: query
+          x-list-query: filter
+          x-list-query-field: age
+          schema:
+            type: integer
+        - name: name_is
+          in: query
+          x-list-query: filter
+          x-list-query-field: name
+          content:
+            application/json:
+              schema:
+                type: string
+        - name: sort
+          in: query
+          x-list-query: sort
+          schema:
+            type: array
+            items:
+              type: string
+        - name: page
+          in: query
+          x-list-query: page
+          schema:
+            type: integer
+            minimum: 1
+            default: 1
+        - name: per_page
+          in: query
+          x-list-query: limit
+          schema:
+            type: integer
+            maximum: 100
+        - name: trace
+          in: query
+          schema:
+            type: boolean
+      responses:
+        "200":
+          description: pets
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  type: object
+                  properties:
+                    name:
+                      type: string
+                    age:
+                      type: integer
+                    status:
+                      type: string
+`
+
+func TestDecodeListQuery(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(listQuerySpec))
+	require.NoError(t, err)
+	router := openapi3filter.NewRouter().WithSwagger(swagger)
+
+	decode := func(rawQuery string) (*openapi3filter.ListQuery, error) {
+		req, err := http.NewRequest(http.MethodGet, "http://example.com/pets?"+rawQuery, nil)
+		require.NoError(t, err)
+		route, pathParams, err := router.FindRoute(req.Method, req.URL)
+		require.NoError(t, err)
+		return openapi3filter.DecodeListQuery(&openapi3filter.RequestValidationInput{
+			Request:    req,
+			PathParams: pathParams,
+			Route:      route,
+		})
+	}
+
+	query, err := decode("status=sold&min_age=2&sort=-age,name&per_page=20&trace=true")
+	require.NoError(t, err)
+	require.Len(t, query.Filters, 2)
+	require.Equal(t, "status", query.Filters[0].Field)
+	require.Equal(t, "sold", query.Filters[0].Value)
+	require.Equal(t, "age", query.Filters[1].Field)
+	require.Equal(t, float64(2), query.Filters[1].Value)
+	require.Equal(t, []*openapi3filter.ListSortField{
+		{Field: "age", Descending: true},
+		{Field: "name"},
+	}, query.Sort)
+	require.Equal(t, int64(1), query.Page)
+	require.Equal(t, int64(20), query.Limit)
+
+	query, err = decode(`name_is="Rex"`)
+	require.NoError(t, err)
+	require.Len(t, query.Filters, 1)
+	require.Equal(t, "name", query.Filters[0].Field)
+	require.Equal(t, "Rex", query.Filters[0].Value)
+
+	query, err = decode("")
+	require.NoError(t, err)
+	require.Empty(t, query.Filters)
+	require.Empty(t, query.Sort)
+	require.Equal(t, int64(0), query.Limit)
+
+	for _, rawQuery := range []string{
+		"status=lost",
+		"sort=unknown",
+		"sort=-",
+		"page=0",
+		"per_page=1000",
+		"min_age=old",
+	} {
+		_, err = decode(rawQuery)
+		require.Error(t, err, rawQuery)
+		require.IsType(t, &openapi3filter.RequestError{}, err, rawQuery)
+	}
+}
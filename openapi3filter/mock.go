@@ -0,0 +1,250 @@
+package openapi3filter
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// ExtExampleMatch is the extension of a named example that selects the example for requests
+// with values of parameters. Its value is an object from names of parameters,
+// which may be qualified like "path.id", to values:
+//
+//	responses:
+//	  "404":
+//	    content:
+//	      application/json:
+//	        examples:
+//	          NotFound:
+//	            x-example-match:
+//	              id: 404
+//	            value:
+//	              message: user not found
+const ExtExampleMatch = "x-example-match"
+
+// ResponseExample is an example of a response selected for a request.
+type ResponseExample struct {
+	Status      int
+	ContentType string
+
+	// Name is the name of the example, or empty for the example of the media type.
+	Name  string
+	Value interface{}
+}
+
+// SelectResponseExample returns the example of a response to the validated request.
+//
+// Named examples with the extension ExtExampleMatch are selected when every listed parameter has the value.
+// Values are compared with decoded values of parameters, so the value 404 matches an integer parameter "404".
+// If no example matches, the example of the first successful response without the extension is returned.
+// Responses of ranges like "4XX" have the first status of the range like 400.
+// The "default" response isn't selected, because its status is unknown.
+func SelectResponseExample(input *RequestValidationInput) (*ResponseExample, error) {
+	route := input.Route
+	if route == nil || route.Operation == nil {
+		return nil, errRouteMissingOperation
+	}
+	var fallback *ResponseExample
+	for _, key := range responseStatuses(route.Operation.Responses) {
+		status := key.status
+		response := route.Operation.Responses[key.key].Value
+		if response == nil {
+			continue
+		}
+		for _, contentType := range exampleContentTypes(response.Content) {
+			mediaType := response.Content[contentType]
+			if fallback == nil && status < 300 && mediaType.Example != nil {
+				fallback = &ResponseExample{Status: status, ContentType: contentType, Value: mediaType.Example}
+			}
+			names := make([]string, 0, len(mediaType.Examples))
+			for name := range mediaType.Examples {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				example := mediaType.Examples[name].Value
+				if example == nil {
+					continue
+				}
+				values, err := exampleMatch(example)
+				if err != nil {
+					return nil, fmt.Errorf("Example '%s' of response %d is invalid: %v", name, status, err)
+				}
+				selected := &ResponseExample{Status: status, ContentType: contentType, Name: name, Value: example.Value}
+				if values == nil {
+					if fallback == nil && status < 300 {
+						fallback = selected
+					}
+					continue
+				}
+				if input.matchesParameterValues(values) {
+					return selected, nil
+				}
+			}
+		}
+	}
+	if fallback == nil {
+		return nil, fmt.Errorf("Operation %s %s doesn't have an example that matches the request", route.Method, route.Path)
+	}
+	return fallback, nil
+}
+
+// exampleMatch returns values of parameters of the extension ExtExampleMatch, or nil without the extension.
+func exampleMatch(example *openapi3.Example) (map[string]interface{}, error) {
+	switch v := example.Extensions[ExtExampleMatch].(type) {
+	case nil:
+		return nil, nil
+	case map[string]interface{}:
+		return v, nil
+	case json.RawMessage:
+		var values map[string]interface{}
+		if err := json.Unmarshal(v, &values); err != nil || values == nil {
+			return nil, fmt.Errorf("Extension '%s' must be an object", ExtExampleMatch)
+		}
+		return values, nil
+	default:
+		return nil, fmt.Errorf("Extension '%s' must be an object, not %T", ExtExampleMatch, v)
+	}
+}
+
+// matchesParameterValues returns true if decoded parameters of the request have the values.
+func (input *RequestValidationInput) matchesParameterValues(values map[string]interface{}) bool {
+	for key, expected := range values {
+		parameter := input.Route.PathItem.FindLinkParameter(input.Route.Operation, key)
+		if parameter == nil {
+			return false
+		}
+		value, ok := input.ParameterValues[parameter]
+		if !ok {
+			return false
+		}
+		if !reflect.DeepEqual(value, expected) && fmt.Sprint(value) != fmt.Sprint(expected) {
+			return false
+		}
+	}
+	return true
+}
+
+type responseStatus struct {
+	key    string
+	status int
+}
+
+func (status responseStatus) isRange() bool {
+	_, err := strconv.Atoi(status.key)
+	return err != nil
+}
+
+// responseStatuses returns statuses of the responses in ascending order, except for "default".
+// A range like "4XX" has the first status of the range, and follows the exact statuses of the range.
+func responseStatuses(responses openapi3.Responses) []responseStatus {
+	statuses := make([]responseStatus, 0, len(responses))
+	for key := range responses {
+		if status, err := strconv.Atoi(key); err == nil {
+			statuses = append(statuses, responseStatus{key: key, status: status})
+		} else if len(key) == 3 && '1' <= key[0] && key[0] <= '5' && strings.EqualFold(key[1:], "XX") {
+			statuses = append(statuses, responseStatus{key: key, status: int(key[0]-'0') * 100})
+		}
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		a, b := statuses[i], statuses[j]
+		if a.status/100 != b.status/100 {
+			return a.status < b.status
+		}
+		if a.isRange() != b.isRange() {
+			return b.isRange()
+		}
+		return a.status < b.status
+	})
+	return statuses
+}
+
+// exampleContentTypes returns content types of the content with JSON first.
+func exampleContentTypes(content openapi3.Content) []string {
+	contentTypes := make([]string, 0, len(content))
+	for contentType, mediaType := range content {
+		if mediaType != nil {
+			contentTypes = append(contentTypes, contentType)
+		}
+	}
+	sort.Slice(contentTypes, func(i, j int) bool {
+		a, b := contentTypes[i] == "application/json", contentTypes[j] == "application/json"
+		if a != b {
+			return a
+		}
+		return contentTypes[i] < contentTypes[j]
+	})
+	return contentTypes
+}
+
+// MockHandler responds to requests of operations of the router with examples selected by SelectResponseExample.
+// Invalid requests are answered with the status of the RequestError.
+type MockHandler struct {
+	Router  *Router
+	Options *Options
+
+	// Values replaces tokens in examples. If nil, examples are written as they are.
+	Values *ExampleValues
+}
+
+// NewMockHandler returns a handler that responds to requests of operations of the router with examples,
+// where tokens are replaced by a new ExampleValues.
+func NewMockHandler(router *Router, options *Options) *MockHandler {
+	return &MockHandler{
+		Router:  router,
+		Options: options,
+		Values:  NewExampleValues(),
+	}
+}
+
+func (h *MockHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	route, pathParams, err := h.Router.FindRoute(req.Method, req.URL)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	input := &RequestValidationInput{
+		Request:    req,
+		PathParams: pathParams,
+		Route:      route,
+		Options:    h.Options,
+	}
+	if err := ValidateRequest(req.Context(), input); err != nil {
+		status := http.StatusBadRequest
+		if requestErr, ok := err.(*RequestError); ok {
+			status = requestErr.HTTPStatus()
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+	example, err := SelectResponseExample(input)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotImplemented)
+		return
+	}
+	value := example.Value
+	if h.Values != nil {
+		if value, err = h.Values.Render(input, value); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	var body []byte
+	if s, ok := value.(string); ok && !strings.Contains(example.ContentType, "json") {
+		body = []byte(s)
+	} else if body, err = json.Marshal(value); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if example.ContentType != "" {
+		w.Header().Set("Content-Type", example.ContentType)
+	}
+	w.WriteHeader(example.Status)
+	w.Write(body)
+}
@@ -0,0 +1,120 @@
+package openapi3filter_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/stretchr/testify/require"
+)
+
+const mockSpec = `
+openapi: 3.0.0
+info:
+  title: Users
+  version: "1.0"
+paths:
+  /users/{id}:
+    get:
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: integer
+        - name: verbose
+          in: query
+          schema:
+            type: boolean
+      responses:
+        "200":
+          description: user
+          content:
+            application/json:
+              examples:
+                Alice:
+                  value:
+                    name: alice
+                Admin:
+                  x-example-match:
+                    path.id: 1
+                    verbose: true
+                  value:
+                    name: admin
+                    roles: [admin]
+        "404":
+          description: not found
+          content:
+            application/json:
+              examples:
+                NotFound:
+                  x-example-match:
+                    id: 404
+                  value:
+                    message: user not found
+        "4XX":
+          description: client error
+          content:
+            application/json:
+              examples:
+                Invalid:
+                  x-example-match:
+                    id: 0
+                  value:
+                    message: invalid user
+`
+
+func TestMockHandler(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(mockSpec))
+	require.NoError(t, err)
+	handler := openapi3filter.NewMockHandler(openapi3filter.NewRouter().WithSwagger(swagger), nil)
+
+	for _, test := range []struct {
+		url    string
+		status int
+		body   string
+	}{
+		{"/users/2", http.StatusOK, `{"name":"alice"}`},
+		{"/users/1", http.StatusOK, `{"name":"alice"}`},
+		{"/users/1?verbose=true", http.StatusOK, `{"name":"admin","roles":["admin"]}`},
+		{"/users/404", http.StatusNotFound, `{"message":"user not found"}`},
+		{"/users/0", http.StatusBadRequest, `{"message":"invalid user"}`},
+	} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, test.url, nil))
+		require.Equal(t, test.status, rec.Code, test.url)
+		require.Equal(t, "application/json", rec.Header().Get("Content-Type"), test.url)
+		require.JSONEq(t, test.body, rec.Body.String(), test.url)
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/alice", nil))
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestSelectResponseExample(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(mockSpec))
+	require.NoError(t, err)
+	router := openapi3filter.NewRouter().WithSwagger(swagger)
+	req := httptest.NewRequest(http.MethodGet, "/users/404", nil)
+	route, pathParams, err := router.FindRoute(req.Method, req.URL)
+	require.NoError(t, err)
+	input := &openapi3filter.RequestValidationInput{
+		Request:    req,
+		PathParams: pathParams,
+		Route:      route,
+	}
+	require.NoError(t, openapi3filter.ValidateRequest(req.Context(), input))
+	require.Equal(t, 404.0, input.ParameterValues[route.Operation.Parameters[0].Value])
+
+	example, err := openapi3filter.SelectResponseExample(input)
+	require.NoError(t, err)
+	require.Equal(t, &openapi3filter.ResponseExample{
+		Status:      http.StatusNotFound,
+		ContentType: "application/json",
+		Name:        "NotFound",
+		Value:       map[string]interface{}{"message": "user not found"},
+	}, example)
+}
@@ -0,0 +1,199 @@
+package openapi3filter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// MultipartPart describes a part of a multipart/form-data body.
+type MultipartPart struct {
+	// Name is the name of the form field of the part.
+	Name        string
+	FileName    string
+	ContentType string
+	Header      http.Header
+
+	// HeaderValues are decoded values of headers that the encoding of the part declares.
+	HeaderValues map[string]interface{}
+}
+
+// decodeMultipartBody decodes parts of a multipart/form-data body to an object whose properties are values of parts,
+// and validates headers of parts by encodings of the media type.
+// Parts are added to the input.
+//
+// A value of a part is decoded by the content type of the part, or by the schema of its property
+// when the part doesn't have a content type.
+// Parts with the same name are items of an array property.
+func decodeMultipartBody(c context.Context, input *RequestValidationInput, requestBody *openapi3.RequestBody,
+	mediaType *openapi3.MediaType, contentType string, body []byte) (interface{}, error) {
+	_, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, &ParseError{Kind: KindInvalidFormat, Reason: "an invalid content type of a multipart body", Cause: err}
+	}
+	boundary := params["boundary"]
+	if boundary == "" {
+		return nil, &ParseError{Kind: KindInvalidFormat, Reason: "a multipart body without a boundary"}
+	}
+	var properties map[string]*openapi3.SchemaRef
+	if schema := mediaType.Schema; schema != nil && schema.Value != nil {
+		properties = schema.Value.Properties
+	}
+
+	value := make(map[string]interface{})
+	r := multipart.NewReader(bytes.NewReader(body), boundary)
+	for {
+		part, err := r.NextPart()
+		if err == io.EOF {
+			return value, nil
+		}
+		if err != nil {
+			return nil, &ParseError{Kind: KindInvalidFormat, Cause: err}
+		}
+		if err := c.Err(); err != nil {
+			return nil, err
+		}
+		name := part.FormName()
+		if name == "" {
+			continue
+		}
+		data, err := ioutil.ReadAll(part)
+		if err != nil {
+			return nil, &ParseError{Kind: KindInvalidFormat, Path: []interface{}{name}, Cause: err}
+		}
+		metadata := &MultipartPart{
+			Name:        name,
+			FileName:    part.FileName(),
+			ContentType: parseMediaType(part.Header.Get("Content-Type")),
+			Header:      http.Header(part.Header),
+		}
+		input.Parts = append(input.Parts, metadata)
+
+		if encoding := mediaType.Encoding[name]; encoding != nil {
+			if err := validatePartEncoding(c, input, requestBody, metadata, encoding); err != nil {
+				return nil, err
+			}
+		}
+
+		property := properties[name]
+		isArray := property != nil && property.Value != nil && property.Value.Type == "array"
+		if isArray {
+			property = property.Value.Items
+		}
+		item, err := decodePartValue(metadata.ContentType, data, property)
+		if err != nil {
+			return nil, wrapParseError(name, err)
+		}
+		if isArray {
+			items, _ := value[name].([]interface{})
+			value[name] = append(items, item)
+		} else {
+			value[name] = item
+		}
+	}
+}
+
+// decodePartValue returns a value of a part.
+// JSON parts and parts of objects and arrays are unmarshaled, and parts of other schemas are parsed as primitive values.
+func decodePartValue(contentType string, data []byte, schema *openapi3.SchemaRef) (interface{}, error) {
+	if isJSONMediaType(contentType) || (contentType == "" && schema != nil && isContainerSchema(schema)) {
+		var value interface{}
+		if err := json.Unmarshal(data, &value); err != nil {
+			return nil, &ParseError{Kind: KindInvalidFormat, Cause: err}
+		}
+		return value, nil
+	}
+	if schema == nil || schema.Value == nil || isContainerSchema(schema) {
+		return string(data), nil
+	}
+	switch schema.Value.Type {
+	case "integer", "number", "boolean", "string":
+		return parsePrimitive(string(data), schema)
+	}
+	return string(data), nil
+}
+
+// validatePartEncoding checks the content type of a part, and validates headers of the part
+// like header parameters of a request.
+// Missing headers are valid, because headers of encodings can't be required.
+func validatePartEncoding(c context.Context, input *RequestValidationInput, requestBody *openapi3.RequestBody,
+	part *MultipartPart, encoding *openapi3.Encoding) error {
+	if allowed := encoding.ContentType; allowed != "" && part.ContentType != "" && !matchesPartContentType(allowed, part.ContentType) {
+		return &RequestError{
+			Input:       input,
+			RequestBody: requestBody,
+			Status:      http.StatusUnsupportedMediaType,
+			Reason:      fmt.Sprintf("part '%s' has unexpected content type %q", part.Name, part.ContentType),
+			Err:         ErrUnsupportedMediaType,
+		}
+	}
+
+	names := make([]string, 0, len(encoding.Headers))
+	for name := range encoding.Headers {
+		// Content-Type of a part is described by the content type of the encoding.
+		if http.CanonicalHeaderKey(name) != "Content-Type" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	headerInput := &RequestValidationInput{Request: &http.Request{Header: part.Header}}
+	for _, name := range names {
+		headerRef := encoding.Headers[name]
+		if headerRef == nil || headerRef.Value == nil || headerRef.Value.Schema == nil || headerRef.Value.Schema.Value == nil {
+			continue
+		}
+		schema := headerRef.Value.Schema
+		parameter := &openapi3.Parameter{Name: name, In: openapi3.ParameterInHeader, Schema: schema}
+		value, err := decodeParameter(parameter, headerInput)
+		if err != nil {
+			return &RequestError{
+				Input:       input,
+				RequestBody: requestBody,
+				Reason:      fmt.Sprintf("header '%s' of part '%s' is invalid", name, part.Name),
+				Err:         err,
+			}
+		}
+		if value == nil {
+			continue
+		}
+		if err := schema.Value.VisitJSONContext(c, value); err != nil {
+			if err := c.Err(); err != nil {
+				return err
+			}
+			return &RequestError{
+				Input:       input,
+				RequestBody: requestBody,
+				Reason:      fmt.Sprintf("header '%s' of part '%s' doesn't match the schema", name, part.Name),
+				Err:         err,
+			}
+		}
+		if part.HeaderValues == nil {
+			part.HeaderValues = make(map[string]interface{})
+		}
+		part.HeaderValues[name] = value
+	}
+	return nil
+}
+
+// matchesPartContentType returns true if the content type matches one of the comma-separated content types,
+// like "image/png, image/*".
+func matchesPartContentType(allowed string, contentType string) bool {
+	for _, v := range strings.Split(allowed, ",") {
+		v = parseMediaType(v)
+		content := openapi3.Content{v: openapi3.NewMediaType()}
+		if findMediaType(content, contentType) != nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,147 @@
+package openapi3filter_test
+
+import (
+	"bytes"
+	"context"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/stretchr/testify/require"
+)
+
+const multipartSpec = `
+openapi: 3.0.0
+info:
+  title: Uploads
+  version: "1.0"
+paths:
+  /uploads:
+    post:
+      requestBody:
+        required: true
+        content:
+          multipart/form-data:
+            schema:
+              type: object
+              required: [count, file]
+              properties:
+                count:
+                  type: integer
+                tags:
+                  type: array
+                  items:
+                    type: string
+                metadata:
+                  type: object
+                  properties:
+                    title:
+                      type: string
+                file:
+                  type: string
+                  format: binary
+            encoding:
+              file:
+                contentType: image/png, image/*
+                headers:
+                  X-Rate-Limit:
+                    schema:
+                      type: integer
+                      maximum: 10
+              metadata:
+                headers:
+                  Content-Disposition:
+                    schema:
+                      type: string
+                      pattern: ^form-data
+      responses:
+        "200":
+          description: ok
+`
+
+type testPart struct {
+	name   string
+	header textproto.MIMEHeader
+	body   string
+}
+
+func newMultipartRequest(t *testing.T, parts []testPart) *http.Request {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	for _, part := range parts {
+		header := textproto.MIMEHeader{"Content-Disposition": {`form-data; name="` + part.name + `"`}}
+		for k, v := range part.header {
+			header[k] = v
+		}
+		pw, err := w.CreatePart(header)
+		require.NoError(t, err)
+		_, err = pw.Write([]byte(part.body))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	req := httptest.NewRequest(http.MethodPost, "/uploads", &body)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	return req
+}
+
+func TestValidateMultipartBody(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(multipartSpec))
+	require.NoError(t, err)
+	router := openapi3filter.NewRouter().WithSwagger(swagger)
+	validate := func(parts []testPart) (*openapi3filter.RequestValidationInput, error) {
+		req := newMultipartRequest(t, parts)
+		route, pathParams, err := router.FindRoute(req.Method, req.URL)
+		require.NoError(t, err)
+		input := &openapi3filter.RequestValidationInput{Request: req, PathParams: pathParams, Route: route}
+		return input, openapi3filter.ValidateRequest(context.Background(), input)
+	}
+	file := testPart{
+		name:   "file",
+		header: textproto.MIMEHeader{"Content-Type": {"image/png"}, "X-Rate-Limit": {"5"}},
+		body:   "PNG",
+	}
+
+	input, err := validate([]testPart{
+		{name: "count", body: "2"},
+		{name: "tags", body: "a"},
+		{name: "tags", body: "b"},
+		{name: "metadata", body: `{"title":"cat"}`},
+		file,
+	})
+	require.NoError(t, err)
+	require.Len(t, input.Parts, 5)
+	part := input.Parts[4]
+	require.Equal(t, "file", part.Name)
+	require.Equal(t, "image/png", part.ContentType)
+	require.Equal(t, "5", part.Header.Get("X-Rate-Limit"))
+	require.Equal(t, map[string]interface{}{"X-Rate-Limit": 5.0}, part.HeaderValues)
+
+	_, err = validate([]testPart{{name: "count", body: "two"}, file})
+	require.Error(t, err)
+	parseErr, ok := err.(*openapi3filter.RequestError).Err.(*openapi3filter.ParseError)
+	require.True(t, ok)
+	require.Equal(t, []interface{}{"count"}, parseErr.Path)
+
+	_, err = validate([]testPart{{name: "count", body: "2"}, {name: "metadata", body: `{"title":1}`}, file})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "doesn't match the schema")
+
+	_, err = validate([]testPart{
+		{name: "count", body: "2"},
+		{name: "file", header: textproto.MIMEHeader{"Content-Type": {"image/gif"}, "X-Rate-Limit": {"11"}}, body: "GIF"},
+	})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "header 'X-Rate-Limit' of part 'file' doesn't match the schema")
+
+	_, err = validate([]testPart{
+		{name: "count", body: "2"},
+		{name: "file", header: textproto.MIMEHeader{"Content-Type": {"text/plain"}}, body: "text"},
+	})
+	require.Error(t, err)
+	require.Equal(t, http.StatusUnsupportedMediaType, err.(*openapi3filter.RequestError).HTTPStatus())
+	require.Equal(t, openapi3filter.ErrUnsupportedMediaType, err.(*openapi3filter.RequestError).Err)
+}
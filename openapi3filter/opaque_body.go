@@ -0,0 +1,50 @@
+package openapi3filter
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// OpaqueBody is the decoded value of a body of an opaque content type, like Protocol Buffers.
+// Such bodies are not decoded, so only their length is validated by 'minLength' and 'maxLength' of the schema.
+type OpaqueBody []byte
+
+// RegisterOpaque declares bodies of a content type opaque.
+// The presence of a required body and its declared content type are validated as usual,
+// but the body isn't decoded.
+func (decoders *BodyDecoders) RegisterOpaque(contentType string) {
+	decoders.RegisterContext(contentType, opaqueBodyDecoder)
+}
+
+// RegisterOpaqueBodyType declares bodies of a content type opaque, like application/protobuf.
+func RegisterOpaqueBodyType(contentType string) {
+	bodyDecoders.RegisterOpaque(contentType)
+}
+
+func opaqueBodyDecoder(c context.Context, body []byte) (interface{}, error) {
+	return OpaqueBody(body), nil
+}
+
+// validateOpaqueBody validates the length of an opaque body in bytes.
+func validateOpaqueBody(schema *openapi3.Schema, body OpaqueBody) error {
+	n := uint64(len(body))
+	if n < schema.MinLength {
+		return &openapi3.SchemaError{
+			Value:       fmt.Sprintf("<%d bytes>", n),
+			Schema:      schema,
+			SchemaField: "minLength",
+			Reason:      fmt.Sprintf("Minimum body length is %d bytes", schema.MinLength),
+		}
+	}
+	if max := schema.MaxLength; max != nil && n > *max {
+		return &openapi3.SchemaError{
+			Value:       fmt.Sprintf("<%d bytes>", n),
+			Schema:      schema,
+			SchemaField: "maxLength",
+			Reason:      fmt.Sprintf("Maximum body length is %d bytes", *max),
+		}
+	}
+	return nil
+}
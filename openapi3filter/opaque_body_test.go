@@ -0,0 +1,103 @@
+package openapi3filter_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/stretchr/testify/require"
+)
+
+const opaqueBodySpec = `
+openapi: 3.0.0
+info:
+  title: Messages
+  version: "1.0"
+paths:
+  /messages:
+    post:
+      requestBody:
+        required: true
+        content:
+          application/protobuf:
+            schema:
+              type: string
+              format: binary
+              minLength: 2
+              maxLength: 8
+      responses:
+        "200":
+          description: echo
+          content:
+            application/octet-stream:
+              schema:
+                type: string
+                format: binary
+                maxLength: 4
+`
+
+func TestOpaqueBody(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(opaqueBodySpec))
+	require.NoError(t, err)
+	router := openapi3filter.NewRouter().WithSwagger(swagger)
+	decoders := openapi3filter.NewBodyDecoders()
+	decoders.RegisterOpaque("application/protobuf")
+	options := &openapi3filter.Options{BodyDecoders: decoders}
+
+	newInput := func(contentType string, body []byte) *openapi3filter.RequestValidationInput {
+		req := httptest.NewRequest(http.MethodPost, "/messages", bytes.NewReader(body))
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		route, pathParams, err := router.FindRoute(req.Method, req.URL)
+		require.NoError(t, err)
+		return &openapi3filter.RequestValidationInput{
+			Request:    req,
+			PathParams: pathParams,
+			Route:      route,
+			Options:    options,
+		}
+	}
+	validate := func(contentType string, body []byte) error {
+		return openapi3filter.ValidateRequest(context.Background(), newInput(contentType, body))
+	}
+
+	// Bytes that aren't valid UTF-8 are counted as bytes.
+	require.NoError(t, validate("application/protobuf", []byte{0x08, 0x96, 0x01}))
+
+	err = validate("application/protobuf", []byte{0x08})
+	var schemaErr *openapi3.SchemaError
+	require.True(t, errors.As(err, &schemaErr))
+	require.Equal(t, "minLength", schemaErr.SchemaField)
+
+	err = validate("application/protobuf", bytes.Repeat([]byte{0xff}, 9))
+	require.True(t, errors.As(err, &schemaErr))
+	require.Equal(t, "maxLength", schemaErr.SchemaField)
+
+	err = validate("application/protobuf", nil)
+	require.True(t, errors.Is(err, openapi3filter.ErrInvalidRequired))
+
+	err = validate("application/json", []byte("{}"))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "header 'Content-Type' has unexpected value")
+
+	// Package-level opaque content types apply to responses too.
+	openapi3filter.RegisterOpaqueBodyType("application/octet-stream")
+	defer openapi3filter.UnregisterBodyDecoder("application/octet-stream")
+	validateResponse := func(body []byte) error {
+		input := &openapi3filter.ResponseValidationInput{
+			RequestValidationInput: newInput("application/protobuf", []byte{1, 2}),
+			Status:                 http.StatusOK,
+			Header:                 http.Header{"Content-Type": []string{"application/octet-stream"}},
+			Options:                options,
+		}
+		return openapi3filter.ValidateResponse(context.Background(), input.SetBodyBytes(body))
+	}
+	require.NoError(t, validateResponse([]byte{1, 2, 3, 4}))
+	require.Error(t, validateResponse([]byte{1, 2, 3, 4, 5}))
+}
@@ -2,6 +2,8 @@ package openapi3filter
 
 import (
 	"context"
+
+	"github.com/getkin/kin-openapi/openapi3"
 )
 
 var DefaultOptions = &Options{}
@@ -11,4 +13,123 @@ type Options struct {
 	ExcludeResponseBody   bool
 	IncludeResponseStatus bool
 	AuthenticationFunc    func(c context.Context, input *AuthenticationInput) error
+
+	// Switches that skip validation of parameters in the path, query, headers, or cookies of requests.
+	ExcludePathParameters   bool
+	ExcludeQueryParameters  bool
+	ExcludeHeaderParameters bool
+	ExcludeCookieParameters bool
+
+	// ExcludeSecurity skips validation of security requirements, so AuthenticationFunc isn't called.
+	ExcludeSecurity bool
+
+	// ExcludeContentType accepts bodies of requests and responses with a missing or undeclared
+	// header Content-Type, and skips validation of such bodies.
+	ExcludeContentType bool
+
+	// IncludeResponseHeaders enables validation of headers of responses by ValidateResponse.
+	// ValidateHTTPResponse and ValidateRecordedResponse always validate them.
+	IncludeResponseHeaders bool
+
+	// ValidateIdempotencyKeys enables validation of idempotency keys of requests, see ValidateIdempotencyKey.
+	// Keys aren't validated if ExcludeHeaderParameters is set.
+	ValidateIdempotencyKeys bool
+
+	// ValidateConditionalHeaders enables validation of entity tags in the headers
+	// If-Match and If-None-Match of requests and ETag of responses.
+	ValidateConditionalHeaders bool
+
+	// StrictContentEncoding rejects responses with a header Content-Encoding
+	// that the response doesn't declare.
+	StrictContentEncoding bool
+
+	// StrictCacheHeaders rejects responses without headers Cache-Control and Expires that they declare,
+	// and responses with invalid values of these headers. See also DeclaredCaching.
+	StrictCacheHeaders bool
+
+	// RejectUndeclaredQueryParameters rejects requests with query parameters
+	// that neither the operation nor the path item declares.
+	RejectUndeclaredQueryParameters bool
+
+	// RejectUndeclaredHeaders rejects requests with undeclared headers,
+	// except for StandardRequestHeaders, API keys, and idempotency keys.
+	RejectUndeclaredHeaders bool
+
+	// RejectUndeclaredCookies rejects requests with undeclared cookies, except for API keys.
+	RejectUndeclaredCookies bool
+
+	// BodyDecoders decodes bodies of requests and responses.
+	// If nil, decoders registered with RegisterBodyDecoder are used.
+	BodyDecoders *BodyDecoders
+
+	// MaxBodyDepth, MaxBodyArrayLength, and MaxBodyObjectProperties limit nesting depth of arrays and objects,
+	// numbers of items of arrays, and numbers of properties of objects in bodies of requests and responses.
+	// Bodies that exceed a limit are rejected with a ParseError of kind KindLimitExceeded.
+	// Zero means no limit.
+	MaxBodyDepth            int
+	MaxBodyArrayLength      int
+	MaxBodyObjectProperties int
+
+	// BeforeParameterDecode is called before a value of a parameter is decoded.
+	// The hook may modify the request, or return an error that rejects the parameter.
+	BeforeParameterDecode func(c context.Context, input *RequestValidationInput, parameter *openapi3.Parameter) error
+
+	// AfterBodyDecode is called after a body of a request or response is decoded,
+	// and before it's validated. The hook returns the value to validate, or an error that rejects the body.
+	AfterBodyDecode func(c context.Context, input *BodyDecodeInput, value interface{}) (interface{}, error)
+
+	// RegexEngine compiles patterns of schemas validated by ValidateRequest, ValidateResponse, and ValidateHTTPResponse,
+	// in place of the compilers of the schemas, like openapi3.SwaggerLoader.RegexCompiler.
+	// Unlike compilers of schemas, the engine doesn't modify schemas, so validators with different engines
+	// may share schemas, like ones interned by openapi3.SwaggerLoader.InternSchemas.
+	RegexEngine *openapi3.RegexEngine
+
+	// Coverage records statuses and media types of validated responses, if not nil.
+	Coverage *Coverage
+
+	// OnValidationError is called with an error of ValidateRequest or ValidateResponse.
+	// The returned error replaces the error, so returning nil accepts the request or response.
+	OnValidationError func(c context.Context, err error) error
+}
+
+// excludesParameter returns true if the options skip validation of the parameter.
+func (options *Options) excludesParameter(parameter *openapi3.Parameter) bool {
+	switch parameter.In {
+	case openapi3.ParameterInPath:
+		return options.ExcludePathParameters
+	case openapi3.ParameterInQuery:
+		return options.ExcludeQueryParameters
+	case openapi3.ParameterInHeader:
+		return options.ExcludeHeaderParameters
+	case openapi3.ParameterInCookie:
+		return options.ExcludeCookieParameters
+	}
+	return false
+}
+
+// contextWithRegexEngine returns a context that validates schemas with the regex engine of the options.
+func (options *Options) contextWithRegexEngine(c context.Context) context.Context {
+	if engine := options.RegexEngine; engine != nil {
+		return openapi3.ContextWithRegexEngine(c, engine)
+	}
+	return c
+}
+
+// BodyDecodeInput describes a decoded body for the hook AfterBodyDecode.
+type BodyDecodeInput struct {
+	RequestValidationInput *RequestValidationInput
+
+	// ResponseValidationInput is nil for a request body.
+	ResponseValidationInput *ResponseValidationInput
+
+	MediaType string
+	Schema    *openapi3.SchemaRef
+}
+
+// handleValidationError calls the hook OnValidationError with an error of validation.
+func (options *Options) handleValidationError(c context.Context, err error) error {
+	if err == nil || options.OnValidationError == nil || err == c.Err() {
+		return err
+	}
+	return options.OnValidationError(c, err)
 }
@@ -0,0 +1,202 @@
+package openapi3filter_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/stretchr/testify/require"
+)
+
+const validationPhasesSpec = `
+openapi: 3.0.0
+info:
+  title: Phases
+  version: "1.0"
+components:
+  securitySchemes:
+    token:
+      type: http
+      scheme: bearer
+paths:
+  /items/{id}:
+    put:
+      security:
+        - token: []
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: integer
+        - name: limit
+          in: query
+          schema:
+            type: integer
+        - name: X-Version
+          in: header
+          schema:
+            type: integer
+        - name: session
+          in: cookie
+          schema:
+            type: integer
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+      responses:
+        "200":
+          description: item
+          headers:
+            X-Rate-Limit:
+              schema:
+                type: integer
+          content:
+            application/json:
+              schema:
+                type: object
+`
+
+func TestExcludeValidationPhases(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(validationPhasesSpec))
+	require.NoError(t, err)
+	router := openapi3filter.NewRouter().WithSwagger(swagger)
+	errDenied := errors.New("denied")
+	authenticate := func(c context.Context, input *openapi3filter.AuthenticationInput) error {
+		if input.RequestValidationInput.Request.Header.Get("Authorization") == "" {
+			return errDenied
+		}
+		return nil
+	}
+
+	type request struct {
+		path, query, header, cookie, contentType string
+		unauthorized                             bool
+	}
+	validate := func(r request, options openapi3filter.Options) error {
+		if r.path == "" {
+			r.path = "1"
+		}
+		if r.contentType == "" {
+			r.contentType = "application/json"
+		}
+		req := httptest.NewRequest(http.MethodPut, "/items/"+r.path+"?"+r.query, strings.NewReader("{}"))
+		req.Header.Set("Content-Type", r.contentType)
+		if r.header != "" {
+			req.Header.Set("X-Version", r.header)
+		}
+		if r.cookie != "" {
+			req.AddCookie(&http.Cookie{Name: "session", Value: r.cookie})
+		}
+		if !r.unauthorized {
+			req.Header.Set("Authorization", "Bearer x")
+		}
+		route, pathParams, err := router.FindRoute(req.Method, req.URL)
+		require.NoError(t, err)
+		options.AuthenticationFunc = authenticate
+		return openapi3filter.ValidateRequest(context.Background(), &openapi3filter.RequestValidationInput{
+			Request:    req,
+			PathParams: pathParams,
+			Route:      route,
+			Options:    &options,
+		})
+	}
+
+	for _, tc := range []struct {
+		name    string
+		request request
+		options openapi3filter.Options
+	}{
+		{"path", request{path: "x"}, openapi3filter.Options{ExcludePathParameters: true}},
+		{"query", request{query: "limit=x"}, openapi3filter.Options{ExcludeQueryParameters: true}},
+		{"header", request{header: "x"}, openapi3filter.Options{ExcludeHeaderParameters: true}},
+		{"cookie", request{cookie: "x"}, openapi3filter.Options{ExcludeCookieParameters: true}},
+		{"security", request{unauthorized: true}, openapi3filter.Options{ExcludeSecurity: true}},
+		{"content type", request{contentType: "text/xml"}, openapi3filter.Options{ExcludeContentType: true}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Error(t, validate(tc.request, openapi3filter.Options{}))
+			require.NoError(t, validate(tc.request, tc.options))
+		})
+	}
+	require.NoError(t, validate(request{}, openapi3filter.Options{}))
+
+	validateResponse := func(rateLimit string, contentType string, options *openapi3filter.Options) error {
+		req := httptest.NewRequest(http.MethodPut, "/items/1", nil)
+		route, pathParams, err := router.FindRoute(req.Method, req.URL)
+		require.NoError(t, err)
+		input := &openapi3filter.ResponseValidationInput{
+			RequestValidationInput: &openapi3filter.RequestValidationInput{
+				Request:    req,
+				PathParams: pathParams,
+				Route:      route,
+			},
+			Status:  http.StatusOK,
+			Header:  http.Header{"Content-Type": []string{contentType}, "X-Rate-Limit": []string{rateLimit}},
+			Options: options,
+		}
+		return openapi3filter.ValidateResponse(context.Background(), input.SetBodyBytes([]byte("{}")))
+	}
+	require.NoError(t, validateResponse("10", "application/json", &openapi3filter.Options{IncludeResponseHeaders: true}))
+	require.NoError(t, validateResponse("many", "application/json", nil), "headers aren't validated by default")
+	err = validateResponse("many", "application/json", &openapi3filter.Options{IncludeResponseHeaders: true})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "response header 'X-Rate-Limit' is invalid")
+	require.Error(t, validateResponse("10", "text/xml", nil))
+	require.NoError(t, validateResponse("10", "text/xml", &openapi3filter.Options{ExcludeContentType: true}))
+}
+
+func TestRegexEngineOption(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(`
+openapi: 3.0.0
+info:
+  title: Users
+  version: "1.0"
+paths:
+  /users/{name}:
+    get:
+      parameters:
+        - name: name
+          in: path
+          required: true
+          schema:
+            type: string
+            pattern: "^(?!admin).+$"
+      responses:
+        default:
+          description: user
+`))
+	require.NoError(t, err)
+	router := openapi3filter.NewRouter().WithSwagger(swagger)
+
+	// The engine supports only the negative lookahead of a prefix.
+	engine := openapi3.NewRegexEngine(func(pattern string) (openapi3.Regexp, error) {
+		return prefixRegexp(strings.TrimSuffix(strings.TrimPrefix(pattern, "^(?!"), ").+$")), nil
+	})
+	validate := func(name string, options *openapi3filter.Options) error {
+		req := httptest.NewRequest(http.MethodGet, "/users/"+name, nil)
+		route, pathParams, err := router.FindRoute(req.Method, req.URL)
+		require.NoError(t, err)
+		return openapi3filter.ValidateRequest(context.Background(), &openapi3filter.RequestValidationInput{
+			Request:    req,
+			PathParams: pathParams,
+			Route:      route,
+			Options:    options,
+		})
+	}
+	require.Error(t, validate("alice", nil))
+	require.NoError(t, validate("alice", &openapi3filter.Options{RegexEngine: engine}))
+	require.Error(t, validate("administrator", &openapi3filter.Options{RegexEngine: engine}))
+}
+
+type prefixRegexp string
+
+func (prefix prefixRegexp) MatchString(s string) bool { return !strings.HasPrefix(s, string(prefix)) }
+func (prefix prefixRegexp) String() string            { return "^(?!" + string(prefix) + ").+$" }
@@ -1,20 +1,37 @@
 package openapi3filter
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
-	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/getkin/kin-openapi/openapi3"
 )
 
 const errMsgInvalidSerializationF = "%s parameter %q has an invalid serialization method: style=%q, explode=%v"
 
+// SerializationMethodError describes a parameter whose serialization method is not allowed
+// by the specification for the location of the parameter, like style "deepObject" of a cookie.
+// Values that don't conform to an allowed serialization method cause ParseError instead.
+type SerializationMethodError struct {
+	Parameter *openapi3.Parameter
+	Method    *openapi3.SerializationMethod
+}
+
+func (err *SerializationMethodError) Error() string {
+	return fmt.Sprintf(errMsgInvalidSerializationF, err.Parameter.In, err.Parameter.Name, err.Method.Style, err.Method.Explode)
+}
+
 // ParseErrorKind describes a kind of ParseError.
-// The type simplifies comparison of errors.
+// The type simplifies comparison of errors, including with errors.Is:
+//
+//	if errors.Is(err, openapi3filter.KindInvalidInt) { ... }
 type ParseErrorKind int
 
 const (
@@ -31,9 +48,48 @@ const (
 	KindInvalidNumber
 	// KindInvalidBool describes an error that happens when a value is an invalid boolean.
 	KindInvalidBool
+	// KindUnexpectedProperty describes an error that happens when an object has a property
+	// that its schema doesn't allow.
+	KindUnexpectedProperty
+	// KindEmptyValue describes an error that happens when a parameter has an empty value,
+	// but its schema is neither a nullable schema nor a string, and the parameter doesn't allow empty values.
+	KindEmptyValue
+	// KindInvalidEnum describes an error that happens when a value is not one of the values of the enum of its schema.
+	KindInvalidEnum
+	// KindLimitExceeded describes an error that happens when a body exceeds a limit of nesting depth,
+	// array length, or object properties of Options.
+	KindLimitExceeded
 )
 
+var parseErrorKindNames = map[ParseErrorKind]string{
+	KindOther:              "other",
+	KindUnsupportedFormat:  "unsupported format",
+	KindInvalidFormat:      "invalid format",
+	KindInvalidInt:         "invalid integer",
+	KindInvalidNumber:      "invalid number",
+	KindInvalidBool:        "invalid boolean",
+	KindUnexpectedProperty: "unexpected property",
+	KindEmptyValue:         "empty value",
+	KindInvalidEnum:        "invalid enum value",
+	KindLimitExceeded:      "limit exceeded",
+}
+
+func (kind ParseErrorKind) String() string {
+	if name, ok := parseErrorKindNames[kind]; ok {
+		return name
+	}
+	return fmt.Sprintf("kind %d", int(kind))
+}
+
+// Error makes a kind an error, so it can be a target of errors.Is.
+func (kind ParseErrorKind) Error() string {
+	return kind.String()
+}
+
 // ParseError describes errors which happens while parse operation's parameters, requestBody, or response.
+//
+// Path is the complete path of the invalid value, such as ["items", 3, "name"].
+// When a ParseError is a cause of another ParseError, the path of the cause is relative to the parent.
 type ParseError struct {
 	Kind   ParseErrorKind
 	Path   []interface{}
@@ -45,18 +101,108 @@ type ParseError struct {
 func (e *ParseError) Error() string {
 	var msg []string
 	if e.Path != nil {
-		msg = append(msg, fmt.Sprintf("path %v", e.Path))
-	}
-	if e.Value != nil {
-		msg = append(msg, fmt.Sprintf("value %v", e.Value))
+		path := make([]interface{}, len(e.Path))
+		for i, v := range e.Path {
+			if name, ok := v.(string); ok {
+				v = openapi3.ErrorMessages.FieldName(name)
+			}
+			path[i] = v
+		}
+		msg = append(msg, fmt.Sprintf("path %v", path))
 	}
-	if e.Reason != "" {
-		msg = append(msg, e.Reason)
+	return strings.Join(append(msg, e.messages()...), ": ")
+}
+
+// messages returns parts of the message of the error, except for the path
+// that already includes paths of causes.
+func (e *ParseError) messages() []string {
+	var msg []string
+	if message, ok := e.format(openapi3.ErrorMessages); ok {
+		msg = append(msg, message)
+	} else {
+		if e.Value != nil {
+			msg = append(msg, fmt.Sprintf("value %v", e.Value))
+		}
+		if e.Reason != "" {
+			msg = append(msg, e.Reason)
+		}
 	}
-	if e.Cause != nil {
+	if cause, ok := e.Cause.(*ParseError); ok {
+		msg = append(msg, cause.messages()...)
+	} else if e.Cause != nil {
 		msg = append(msg, e.Cause.Error())
 	}
-	return strings.Join(msg, ": ")
+	return msg
+}
+
+// Message returns the message of the catalog for the kind of the innermost ParseError,
+// or the reason of the error. Unlike Error, the message doesn't include the path.
+//
+// Templates are keyed by names of kinds like "invalid integer", and have data with fields Kind, Value, Reason, and Path.
+func (e *ParseError) Message(catalog *openapi3.MessageCatalog) string {
+	if cause, ok := e.Cause.(*ParseError); ok {
+		return cause.Message(catalog)
+	}
+	if message, ok := e.format(catalog); ok {
+		return message
+	}
+	if e.Reason == "" && e.Cause != nil {
+		return e.Cause.Error()
+	}
+	return e.Reason
+}
+
+func (e *ParseError) format(catalog *openapi3.MessageCatalog) (string, bool) {
+	return catalog.Format(e.Kind.String(), map[string]interface{}{
+		"Kind":   e.Kind.String(),
+		"Value":  e.Value,
+		"Reason": e.Reason,
+		"Path":   e.Path,
+	})
+}
+
+// Unwrap returns the cause of the error.
+func (e *ParseError) Unwrap() error {
+	return e.Cause
+}
+
+// Is returns true if the target is the kind of the error.
+// Errors that only add a path to a ParseError cause don't have a kind, so the kind of the cause is matched.
+func (e *ParseError) Is(target error) bool {
+	kind, ok := target.(ParseErrorKind)
+	if !ok {
+		return false
+	}
+	if _, wrapper := e.Cause.(*ParseError); wrapper && e.Kind == KindOther {
+		return false
+	}
+	return e.Kind == kind
+}
+
+// RootCause returns the innermost error of the chain of causes.
+// If the innermost error is a ParseError, the error is returned.
+func (e *ParseError) RootCause() error {
+	var err error = e
+	for {
+		cause := errors.Unwrap(err)
+		if cause == nil {
+			return err
+		}
+		err = cause
+	}
+}
+
+// wrapParseError returns a ParseError of a value with the path element,
+// such as a property name or an array index, that has the error.
+func wrapParseError(pathElement interface{}, err error) error {
+	v, ok := err.(*ParseError)
+	if !ok {
+		return err
+	}
+	path := make([]interface{}, 0, 1+len(v.Path))
+	path = append(path, pathElement)
+	path = append(path, v.Path...)
+	return &ParseError{Path: path, Cause: v}
 }
 
 // decodeParameter returns a value of an operation's parameter from HTTP request.
@@ -81,16 +227,86 @@ func decodeParameter(param *openapi3.Parameter, input *RequestValidationInput) (
 		return nil, fmt.Errorf("unsupported parameter's 'in': %s", param.In)
 	}
 
+	if param.Schema == nil && len(param.Content) > 0 {
+		return decodeContentParameter(param, input)
+	}
+
+	if len(param.Schema.Value.AllOf) > 0 {
+		// Values are decoded with the effective schema, but validated with the original one.
+		if merged, err := param.Schema.Value.MergeAllOf(); err == nil {
+			decodedParam := *param
+			decodedParam.Schema = merged.NewRef()
+			param = &decodedParam
+		}
+	}
+
+	// Absent arrays and objects are returned as untyped nil values.
 	switch param.Schema.Value.Type {
 	case "array":
-		return decoder.DecodeArray(param)
+		value, err := decoder.DecodeArray(param)
+		if value == nil {
+			return nil, err
+		}
+		return value, err
 	case "object":
-		return decoder.DecodeObject(param)
+		value, err := decoder.DecodeObject(param)
+		if value == nil {
+			return nil, err
+		}
+		return value, err
 	default:
 		return decoder.DecodePrimitive(param)
 	}
 }
 
+// decodeContentParameter decodes a value of a parameter that has content instead of a schema.
+// Values of JSON media types are decoded as JSON, and values of other media types are strings.
+func decodeContentParameter(param *openapi3.Parameter, input *RequestValidationInput) (interface{}, error) {
+	var raw string
+	switch param.In {
+	case openapi3.ParameterInPath:
+		raw = input.PathParams[param.Name]
+	case openapi3.ParameterInQuery:
+		if values := input.GetQueryParams()[param.Name]; len(values) > 0 {
+			raw = values[0]
+		}
+	case openapi3.ParameterInHeader:
+		raw = input.Request.Header.Get(http.CanonicalHeaderKey(param.Name))
+	case openapi3.ParameterInCookie:
+		if cookie, err := input.Request.Cookie(param.Name); err == nil {
+			raw = cookie.Value
+		}
+	}
+	if raw == "" {
+		// HTTP request does not contain a value of the parameter.
+		return nil, nil
+	}
+	for mediaType := range param.Content {
+		if !isJSONMediaType(parseMediaType(mediaType)) {
+			continue
+		}
+		var value interface{}
+		if err := json.Unmarshal([]byte(raw), &value); err != nil {
+			return nil, &ParseError{Kind: KindInvalidFormat, Value: raw, Reason: fmt.Sprintf("an invalid value of media type %q", mediaType), Cause: err}
+		}
+		return value, nil
+	}
+	return raw, nil
+}
+
+// parameterSchema returns the schema of a parameter, or the schema of the content of the parameter.
+func parameterSchema(param *openapi3.Parameter) *openapi3.SchemaRef {
+	if param.Schema != nil {
+		return param.Schema
+	}
+	for _, mediaType := range param.Content {
+		if mediaType != nil {
+			return mediaType.Schema
+		}
+	}
+	return nil
+}
+
 // pathParamDecoder decodes values of path parameters.
 type pathParamDecoder struct {
 	input *RequestValidationInput
@@ -110,7 +326,7 @@ func (d *pathParamDecoder) DecodePrimitive(param *openapi3.Parameter) (interface
 	case "matrix":
 		prefix = ";" + param.Name + "="
 	default:
-		return nil, fmt.Errorf(errMsgInvalidSerializationF, param.In, param.Name, sm.Style, sm.Explode)
+		return nil, &SerializationMethodError{Parameter: param, Method: sm}
 	}
 
 	if d.input.PathParams == nil {
@@ -151,7 +367,7 @@ func (d *pathParamDecoder) DecodeArray(param *openapi3.Parameter) ([]interface{}
 		prefix = ";" + param.Name + "="
 		delim = ";" + param.Name + "="
 	default:
-		return nil, fmt.Errorf(errMsgInvalidSerializationF, param.In, param.Name, sm.Style, sm.Explode)
+		return nil, &SerializationMethodError{Parameter: param, Method: sm}
 	}
 
 	if d.input.PathParams == nil {
@@ -200,7 +416,7 @@ func (d *pathParamDecoder) DecodeObject(param *openapi3.Parameter) (map[string]i
 		propsDelim = ";"
 		valueDelim = "="
 	default:
-		return nil, fmt.Errorf(errMsgInvalidSerializationF, param.In, param.Name, sm.Style, sm.Explode)
+		return nil, &SerializationMethodError{Parameter: param, Method: sm}
 	}
 
 	if d.input.PathParams == nil {
@@ -262,7 +478,7 @@ func (d *queryParamDecoder) DecodePrimitive(param *openapi3.Parameter) (interfac
 		return nil, err
 	}
 	if sm.Style != "form" {
-		return nil, fmt.Errorf(errMsgInvalidSerializationF, param.In, param.Name, sm.Style, sm.Explode)
+		return nil, &SerializationMethodError{Parameter: param, Method: sm}
 	}
 
 	values := d.input.GetQueryParams()[param.Name]
@@ -270,7 +486,7 @@ func (d *queryParamDecoder) DecodePrimitive(param *openapi3.Parameter) (interfac
 		// HTTP request does not contain a value of the target query parameter.
 		return nil, nil
 	}
-	return parsePrimitive(values[0], param.Schema)
+	return parsePrimitiveParameter(values[0], param)
 }
 
 func (d *queryParamDecoder) DecodeArray(param *openapi3.Parameter) ([]interface{}, error) {
@@ -279,7 +495,7 @@ func (d *queryParamDecoder) DecodeArray(param *openapi3.Parameter) ([]interface{
 		return nil, err
 	}
 	if sm.Style == "deepObject" {
-		return nil, fmt.Errorf(errMsgInvalidSerializationF, param.In, param.Name, sm.Style, sm.Explode)
+		return nil, &SerializationMethodError{Parameter: param, Method: sm}
 	}
 
 	values := d.input.GetQueryParams()[param.Name]
@@ -316,8 +532,12 @@ func (d *queryParamDecoder) DecodeObject(param *openapi3.Parameter) (map[string]
 				return nil, nil
 			}
 			if sm.Explode {
-				props := make(map[string]string)
+				others := otherParameterNames(d.input, param)
+				props := make(map[string]string, len(params))
 				for key, values := range params {
+					if containsString(others, key) {
+						continue
+					}
 					props[key] = values[0]
 				}
 				return props, nil
@@ -330,24 +550,26 @@ func (d *queryParamDecoder) DecodeObject(param *openapi3.Parameter) (map[string]
 			return propsFromString(values[0], ",", ",")
 		}
 	case "deepObject":
-		propsFn = func(params map[string][]string) (map[string]string, error) {
-			props := make(map[string]string)
-			for key, values := range params {
-				groups := regexp.MustCompile(fmt.Sprintf("%s\\[(.+?)\\]", param.Name)).FindAllStringSubmatch(key, -1)
-				if len(groups) == 0 {
-					// A query parameter's name does not match the required format, so skip it.
-					continue
-				}
-				props[groups[0][1]] = values[0]
+		tree := make(map[string]interface{})
+		// The path is reused by keys, because trees don't keep paths.
+		path := make([]string, 0, 4)
+		for key, values := range d.input.GetQueryParams() {
+			path = appendDeepObjectPath(path[:0], param.Name, key)
+			if len(path) == 0 {
+				// A query parameter's name does not match the required format, so skip it.
+				continue
 			}
-			if len(props) == 0 {
-				// HTTP request does not contain query parameters encoded by rules of style "deepObject".
-				return nil, nil
+			if err := addDeepObjectValue(tree, path, values); err != nil {
+				return nil, err
 			}
-			return props, nil
 		}
+		if len(tree) == 0 {
+			// HTTP request does not contain query parameters encoded by rules of style "deepObject".
+			return nil, nil
+		}
+		return makeDeepObject(tree, param.Schema)
 	default:
-		return nil, fmt.Errorf(errMsgInvalidSerializationF, param.In, param.Name, sm.Style, sm.Explode)
+		return nil, &SerializationMethodError{Parameter: param, Method: sm}
 	}
 
 	props, err := propsFn(d.input.GetQueryParams())
@@ -360,6 +582,55 @@ func (d *queryParamDecoder) DecodeObject(param *openapi3.Parameter) (map[string]
 	return makeObject(props, param.Schema)
 }
 
+// appendDeepObjectPath appends names of properties of a query parameter of style "deepObject" to the path,
+// like "items", "0", and "id" for "param[items][0][id]".
+// Nothing is appended if the key isn't a property of the parameter.
+func appendDeepObjectPath(path []string, name, key string) []string {
+	if len(key) <= len(name)+1 || key[:len(name)] != name || key[len(name)] != '[' {
+		return path
+	}
+	rest := key[len(name):]
+	end := strings.IndexByte(rest, ']')
+	if end < 2 {
+		return path
+	}
+	path = append(path, rest[1:end])
+	// Names of nested properties follow in brackets.
+	for rest = rest[end+1:]; strings.HasPrefix(rest, "["); rest = rest[end+1:] {
+		if end = strings.IndexByte(rest, ']'); end < 0 {
+			break
+		}
+		if end > 1 {
+			path = append(path, rest[1:end])
+		}
+	}
+	return path
+}
+
+// otherParameterNames returns names of other parameters of the route in the same location,
+// which can't be properties of an exploded object.
+func otherParameterNames(input *RequestValidationInput, param *openapi3.Parameter) []string {
+	if input.Route == nil || input.Route.Operation == nil {
+		return nil
+	}
+	var names []string
+	for _, parameterRef := range input.Route.Parameters() {
+		if other := parameterRef.Value; other.In == param.In && other.Name != param.Name {
+			names = append(names, other.Name)
+		}
+	}
+	return names
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
 // headerParamDecoder decodes values of header parameters.
 type headerParamDecoder struct {
 	input *RequestValidationInput
@@ -371,11 +642,15 @@ func (d *headerParamDecoder) DecodePrimitive(param *openapi3.Parameter) (interfa
 		return nil, err
 	}
 	if sm.Style != "simple" {
-		return nil, fmt.Errorf(errMsgInvalidSerializationF, param.In, param.Name, sm.Style, sm.Explode)
+		return nil, &SerializationMethodError{Parameter: param, Method: sm}
 	}
 
-	raw := d.input.Request.Header.Get(http.CanonicalHeaderKey(param.Name))
-	return parsePrimitive(raw, param.Schema)
+	values := d.input.Request.Header[http.CanonicalHeaderKey(param.Name)]
+	if len(values) == 0 {
+		// HTTP request does not contain the header.
+		return nil, nil
+	}
+	return parsePrimitiveParameter(values[0], param)
 }
 
 func (d *headerParamDecoder) DecodeArray(param *openapi3.Parameter) ([]interface{}, error) {
@@ -384,7 +659,7 @@ func (d *headerParamDecoder) DecodeArray(param *openapi3.Parameter) ([]interface
 		return nil, err
 	}
 	if sm.Style != "simple" {
-		return nil, fmt.Errorf(errMsgInvalidSerializationF, param.In, param.Name, sm.Style, sm.Explode)
+		return nil, &SerializationMethodError{Parameter: param, Method: sm}
 	}
 
 	raw := d.input.Request.Header.Get(http.CanonicalHeaderKey(param.Name))
@@ -401,7 +676,7 @@ func (d *headerParamDecoder) DecodeObject(param *openapi3.Parameter) (map[string
 		return nil, err
 	}
 	if sm.Style != "simple" {
-		return nil, fmt.Errorf(errMsgInvalidSerializationF, param.In, param.Name, sm.Style, sm.Explode)
+		return nil, &SerializationMethodError{Parameter: param, Method: sm}
 	}
 	valueDelim := ","
 	if sm.Explode {
@@ -431,7 +706,7 @@ func (d *cookieParamDecoder) DecodePrimitive(param *openapi3.Parameter) (interfa
 		return nil, err
 	}
 	if sm.Style != "form" {
-		return nil, fmt.Errorf(errMsgInvalidSerializationF, param.In, param.Name, sm.Style, sm.Explode)
+		return nil, &SerializationMethodError{Parameter: param, Method: sm}
 	}
 
 	cookie, err := d.input.Request.Cookie(param.Name)
@@ -442,7 +717,7 @@ func (d *cookieParamDecoder) DecodePrimitive(param *openapi3.Parameter) (interfa
 	if err != nil {
 		return nil, fmt.Errorf("decode param %q: %s", param.Name, err)
 	}
-	return parsePrimitive(cookie.Value, param.Schema)
+	return parsePrimitiveParameter(cookie.Value, param)
 }
 
 func (d *cookieParamDecoder) DecodeArray(param *openapi3.Parameter) ([]interface{}, error) {
@@ -450,8 +725,23 @@ func (d *cookieParamDecoder) DecodeArray(param *openapi3.Parameter) ([]interface
 	if err != nil {
 		return nil, err
 	}
-	if sm.Style != "form" || sm.Explode {
-		return nil, fmt.Errorf(errMsgInvalidSerializationF, param.In, param.Name, sm.Style, sm.Explode)
+	if sm.Style != "form" {
+		return nil, &SerializationMethodError{Parameter: param, Method: sm}
+	}
+
+	if sm.Explode {
+		// Every item is a separate cookie with the name of the parameter.
+		var values []string
+		for _, cookie := range d.input.Request.Cookies() {
+			if cookie.Name == param.Name {
+				values = append(values, cookie.Value)
+			}
+		}
+		if len(values) == 0 {
+			// HTTP request does not contain a corresponding cookie.
+			return nil, nil
+		}
+		return parseArray(values, param.Schema)
 	}
 
 	cookie, err := d.input.Request.Cookie(param.Name)
@@ -470,8 +760,31 @@ func (d *cookieParamDecoder) DecodeObject(param *openapi3.Parameter) (map[string
 	if err != nil {
 		return nil, err
 	}
-	if sm.Style != "form" || sm.Explode {
-		return nil, fmt.Errorf(errMsgInvalidSerializationF, param.In, param.Name, sm.Style, sm.Explode)
+	if sm.Style != "form" {
+		return nil, &SerializationMethodError{Parameter: param, Method: sm}
+	}
+
+	if sm.Explode {
+		// Every property is a separate cookie.
+		// Other cookies are properties only if the schema allows additional properties.
+		schema := param.Schema.Value
+		additional := schema.AdditionalProperties != nil || (schema.AdditionalPropertiesAllowed != nil && *schema.AdditionalPropertiesAllowed)
+		others := otherParameterNames(d.input, param)
+		props := make(map[string]string)
+		for _, cookie := range d.input.Request.Cookies() {
+			if _, ok := props[cookie.Name]; ok || containsString(others, cookie.Name) {
+				continue
+			}
+			if _, ok := schema.Properties[cookie.Name]; !ok && !additional {
+				continue
+			}
+			props[cookie.Name] = cookie.Value
+		}
+		if len(props) == 0 {
+			// HTTP request does not contain cookies.
+			return nil, nil
+		}
+		return makeObject(props, param.Schema)
 	}
 
 	cookie, err := d.input.Request.Cookie(param.Name)
@@ -489,12 +802,130 @@ func (d *cookieParamDecoder) DecodeObject(param *openapi3.Parameter) (map[string
 	return makeObject(props, param.Schema)
 }
 
+// addDeepObjectValue adds values of a query parameter like "filter[tags][0]" to a tree of properties,
+// where leaves are values and other nodes are maps.
+func addDeepObjectValue(tree map[string]interface{}, path []string, values []string) error {
+	node := tree
+	for i, name := range path[:len(path)-1] {
+		child, ok := node[name]
+		if !ok {
+			child = make(map[string]interface{})
+			node[name] = child
+		}
+		childNode, ok := child.(map[string]interface{})
+		if !ok {
+			return &ParseError{Kind: KindInvalidFormat, Path: toPath(path[:i+1]), Reason: "a property has both a value and properties"}
+		}
+		node = childNode
+	}
+	name := path[len(path)-1]
+	if _, ok := node[name].(map[string]interface{}); ok {
+		return &ParseError{Kind: KindInvalidFormat, Path: toPath(path), Reason: "a property has both a value and properties"}
+	}
+	node[name] = values
+	return nil
+}
+
+func toPath(names []string) []interface{} {
+	path := make([]interface{}, 0, len(names))
+	for _, name := range names {
+		path = append(path, name)
+	}
+	return path
+}
+
+// makeDeepObject returns an object from a tree of properties of style "deepObject".
+// Properties of primitive types are parsed like properties of other styles,
+// and properties of arrays and objects are parsed with their nested properties.
+func makeDeepObject(tree map[string]interface{}, schema *openapi3.SchemaRef) (map[string]interface{}, error) {
+	props := make(map[string]string)
+	for name, node := range tree {
+		if propSchema := schema.Value.Properties[name]; propSchema != nil && isContainerSchema(propSchema) {
+			continue
+		}
+		values, ok := node.([]string)
+		if !ok {
+			return nil, wrapParseError(name, &ParseError{Kind: KindInvalidFormat, Reason: "a primitive value has properties"})
+		}
+		props[name] = values[0]
+	}
+	obj, err := makeObject(props, schema)
+	if err != nil {
+		return nil, err
+	}
+	for name, propSchema := range schema.Value.Properties {
+		node, ok := tree[name]
+		if !ok || !isContainerSchema(propSchema) {
+			continue
+		}
+		value, err := decodeDeepObjectValue(node, propSchema)
+		if err != nil {
+			return nil, wrapParseError(name, err)
+		}
+		obj[name] = value
+	}
+	return obj, nil
+}
+
+// decodeDeepObjectValue returns a value of a node of a tree of properties of style "deepObject".
+// Items of arrays are repeated values like "tags[]=a&tags[]=b", or indexed properties like "tags[0]=a&tags[1]=b".
+func decodeDeepObjectValue(node interface{}, schema *openapi3.SchemaRef) (interface{}, error) {
+	values, isLeaf := node.([]string)
+	switch schema.Value.Type {
+	case "object":
+		if isLeaf {
+			return nil, &ParseError{Kind: KindInvalidFormat, Value: values[0], Reason: "an object must have properties"}
+		}
+		return makeDeepObject(node.(map[string]interface{}), schema)
+	case "array":
+		items := schema.Value.Items
+		if isLeaf {
+			if items != nil && isContainerSchema(items) {
+				return nil, &ParseError{Kind: KindInvalidFormat, Reason: "items of an array must have indexes"}
+			}
+			return parseArray(values, schema)
+		}
+		indexed := node.(map[string]interface{})
+		indexes := make([]int, 0, len(indexed))
+		for key := range indexed {
+			index, err := strconv.Atoi(key)
+			if err != nil || index < 0 {
+				return nil, wrapParseError(key, &ParseError{Kind: KindInvalidFormat, Reason: "an index of an item must be a non-negative integer"})
+			}
+			indexes = append(indexes, index)
+		}
+		sort.Ints(indexes)
+		result := make([]interface{}, 0, len(indexes))
+		for i, index := range indexes {
+			if items == nil {
+				return nil, &ParseError{Kind: KindInvalidFormat, Reason: "an array must have a schema of items"}
+			}
+			item, err := decodeDeepObjectValue(indexed[strconv.Itoa(index)], items)
+			if err != nil {
+				return nil, wrapParseError(i, err)
+			}
+			result = append(result, item)
+		}
+		return result, nil
+	default:
+		if !isLeaf {
+			return nil, &ParseError{Kind: KindInvalidFormat, Reason: "a primitive value has properties"}
+		}
+		return parsePrimitive(values[0], schema)
+	}
+}
+
+// isContainerSchema returns true if the schema is a schema of arrays or objects.
+func isContainerSchema(schema *openapi3.SchemaRef) bool {
+	return schema.Value != nil && (schema.Value.Type == "array" || schema.Value.Type == "object")
+}
+
 // propsFromString returns a properties map that is created by splitting a source string by propDelim and valueDelim.
 // The source string must have a valid format: pairs <propName><valueDelim><propValue> separated by <propDelim>.
 // The function returns an error when the source string has an invalid format.
 func propsFromString(src, propDelim, valueDelim string) (map[string]string, error) {
-	props := make(map[string]string)
 	pairs := strings.Split(src, propDelim)
+	props := make(map[string]string, len(pairs))
 
 	// When propDelim and valueDelim is equal the source string follow the next rule:
 	// every even item of pairs is a properies's name, and the subsequent odd item is a property's value.
@@ -532,34 +963,84 @@ func propsFromString(src, propDelim, valueDelim string) (map[string]string, erro
 
 // makeObject returns an object that contains properties from props.
 // A value of every property is parsed as a primitive value.
+//
+// Properties that the schema doesn't declare are parsed by the schema 'additionalProperties',
+// kept as strings if 'additionalProperties' is true, and rejected if it is false.
+// They are ignored if the schema doesn't have 'additionalProperties'.
 // The function returns an error when an error happened while parse object's properties.
 func makeObject(props map[string]string, schema *openapi3.SchemaRef) (map[string]interface{}, error) {
-	obj := make(map[string]interface{})
+	obj := make(map[string]interface{}, len(schema.Value.Properties))
 	for propName, propSchema := range schema.Value.Properties {
+		if isContainerSchema(propSchema) {
+			if raw := props[propName]; raw != "" {
+				return nil, wrapParseError(propName, &ParseError{
+					Kind:   KindUnsupportedFormat,
+					Value:  raw,
+					Reason: fmt.Sprintf("properties of type %q are supported only by style \"deepObject\"", propSchema.Value.Type),
+				})
+			}
+			obj[propName] = nil
+			continue
+		}
 		value, err := parsePrimitive(props[propName], propSchema)
 		if err != nil {
-			if v, ok := err.(*ParseError); ok {
-				return nil, &ParseError{Path: []interface{}{propName}, Cause: v}
-			}
-			return nil, err
+			return nil, wrapParseError(propName, err)
 		}
 		obj[propName] = value
 	}
+
+	additional := schema.Value.AdditionalProperties
+	allowed := schema.Value.AdditionalPropertiesAllowed
+	if additional == nil && allowed == nil {
+		return obj, nil
+	}
+	names := make([]string, 0, len(props))
+	for propName := range props {
+		if _, ok := schema.Value.Properties[propName]; !ok {
+			names = append(names, propName)
+		}
+	}
+	sort.Strings(names)
+	for _, propName := range names {
+		raw := props[propName]
+		switch {
+		case additional != nil && additional.Value != nil && (additional.Value.Type == "object" || additional.Value.Type == "array"):
+			return nil, wrapParseError(propName, &ParseError{
+				Kind:   KindUnsupportedFormat,
+				Value:  raw,
+				Reason: fmt.Sprintf("additional properties of type %q are not supported", additional.Value.Type),
+			})
+		case additional != nil && additional.Value != nil && additional.Value.Type != "":
+			value, err := parsePrimitive(raw, additional)
+			if err != nil {
+				return nil, wrapParseError(propName, err)
+			}
+			obj[propName] = value
+		case additional != nil || *allowed:
+			obj[propName] = raw
+		default:
+			return nil, wrapParseError(propName, &ParseError{
+				Kind:   KindUnexpectedProperty,
+				Value:  raw,
+				Reason: "property is not allowed by the schema",
+			})
+		}
+	}
 	return obj, nil
 }
 
 // parseArray returns an array that contains items from a raw array.
-// Every item is parsed as a primitive value.
+// Every item is parsed as a primitive value, so items of arrays and objects are not supported.
 // The function returns an error when an error happened while parse array's items.
 func parseArray(raw []string, schemaRef *openapi3.SchemaRef) ([]interface{}, error) {
+	if items := schemaRef.Value.Items; items == nil || isContainerSchema(items) {
+		return nil, &ParseError{Kind: KindUnsupportedFormat, Reason: "items of an array must have a primitive schema"}
+	}
 	var value []interface{}
 	for i, v := range raw {
 		item, err := parsePrimitive(v, schemaRef.Value.Items)
 		if err != nil {
-			if v, ok := err.(*ParseError); ok {
-				return nil, &ParseError{Path: []interface{}{i}, Cause: v}
-			}
-			return nil, err
+			return nil, wrapParseError(i, err)
 		}
 		value = append(value, item)
 	}
@@ -573,88 +1054,250 @@ func parsePrimitive(raw string, schema *openapi3.SchemaRef) (interface{}, error)
 	if raw == "" {
 		return nil, nil
 	}
+	var value interface{}
 	switch schema.Value.Type {
 	case "integer":
 		v, err := strconv.ParseFloat(raw, 64)
 		if err != nil {
 			return nil, &ParseError{Kind: KindInvalidInt, Value: raw, Reason: "an invalid interger", Cause: err}
 		}
-		return v, nil
+		value = v
 	case "number":
 		v, err := strconv.ParseFloat(raw, 64)
 		if err != nil {
 			return nil, &ParseError{Kind: KindInvalidNumber, Value: raw, Reason: "an invalid number", Cause: err}
 		}
-		return v, nil
+		value = v
 	case "boolean":
 		v, err := strconv.ParseBool(raw)
 		if err != nil {
 			return nil, &ParseError{Kind: KindInvalidBool, Value: raw, Reason: "an invalid number", Cause: err}
 		}
-		return v, nil
+		value = v
 	case "string":
-		return raw, nil
+		value = raw
 	default:
 		panic(fmt.Sprintf("schema has non primitive type %q", schema.Value.Type))
 	}
+	if enum := schema.Value.Enum; len(enum) > 0 && !enumContains(enum, value) {
+		return nil, &ParseError{Kind: KindInvalidEnum, Value: raw, Reason: "not one of the values of the enum"}
+	}
+	return value, nil
+}
+
+// parsePrimitiveParameter returns a value of a primitive parameter that the request contains.
+// An empty value is null if the schema is nullable, and absent if the parameter allows empty values
+// or its schema is a string. Other empty values are invalid.
+func parsePrimitiveParameter(raw string, param *openapi3.Parameter) (interface{}, error) {
+	if raw == "" {
+		schema := param.Schema.Value
+		if !schema.Nullable && !param.AllowEmptyValue && schema.Type != "string" {
+			return nil, &ParseError{Kind: KindEmptyValue, Reason: "an empty value of a schema that is not nullable"}
+		}
+		return nil, nil
+	}
+	return parsePrimitive(raw, param.Schema)
+}
+
+// enumContains returns true if the enum contains the parsed value.
+// Numbers of the enum may have any numeric type.
+func enumContains(enum []interface{}, value interface{}) bool {
+	for _, item := range enum {
+		if item == value {
+			return true
+		}
+		if number, ok := value.(float64); ok {
+			switch item := item.(type) {
+			case int:
+				if float64(item) == number {
+					return true
+				}
+			case int64:
+				if float64(item) == number {
+					return true
+				}
+			case float32:
+				if float64(item) == number {
+					return true
+				}
+			}
+		}
+	}
+	return false
 }
 
 // BodyDecoder is an interface to decode a body of a request or response.
 // An implementation must return a value that is a primitive, []interface{}, or map[string]interface{}.
 type BodyDecoder func(data []byte) (interface{}, error)
 
+// BodyDecoderContext is a BodyDecoder that receives the context of validation,
+// so it can stop decoding when the context is done.
+type BodyDecoderContext func(c context.Context, data []byte) (interface{}, error)
+
+// BodyDecoders is a registry of body decoders that is safe for concurrent use.
+//
+// A registry created with NewBodyDecoders can be set in Options,
+// so applications don't share decoders with each other.
+// Content types that are absent in the registry are decoded by the decoders
+// registered with RegisterBodyDecoder, unless they are unregistered from the registry.
+// The zero value is an empty registry without defaults.
+type BodyDecoders struct {
+	mu sync.RWMutex
+	// decoders maps content types to decoders, or to nil if they are unregistered.
+	decoders map[string]BodyDecoderContext
+	parent   *BodyDecoders
+}
+
+// NewBodyDecoders returns an empty registry that defaults to the package-level decoders.
+func NewBodyDecoders() *BodyDecoders {
+	return &BodyDecoders{
+		decoders: make(map[string]BodyDecoderContext),
+		parent:   bodyDecoders,
+	}
+}
+
 // bodyDecoders contains decoders for supported content types of a body.
-// By default, there is content type "application/json" is supported only.
-var bodyDecoders = map[string]BodyDecoder{
-	"plain/text": func(body []byte) (interface{}, error) {
-		return string(body), nil
-	},
-	"application/json": func(body []byte) (interface{}, error) {
-		var value interface{}
-		if err := json.Unmarshal(body, &value); err != nil {
-			return nil, err
-		}
-		return value, nil
+// By default, content types "plain/text", "application/json", "text/csv", and "application/x-ndjson" are supported.
+var bodyDecoders = &BodyDecoders{
+	decoders: map[string]BodyDecoderContext{
+		"plain/text": func(c context.Context, body []byte) (interface{}, error) {
+			return string(body), nil
+		},
+		"application/json": func(c context.Context, body []byte) (interface{}, error) {
+			var value interface{}
+			if err := json.Unmarshal(body, &value); err != nil {
+				return nil, err
+			}
+			return value, nil
+		},
+		"text/csv":             csvBodyDecoder,
+		"application/x-ndjson": ndjsonBodyDecoder,
 	},
 }
 
-// RegisterBodyDecoder registers a request body's decoder for a content type.
+// Register registers a body decoder for a content type.
 //
-// If a decoder for the specified content type already exists, the function replaces
-// it with the specified decoder.
-func RegisterBodyDecoder(contentType string, decoder BodyDecoder) {
+// If a decoder for the specified content type already exists in the registry,
+// the method replaces it with the specified decoder.
+func (decoders *BodyDecoders) Register(contentType string, decoder BodyDecoder) {
+	if decoder == nil {
+		panic("decoder is not defined")
+	}
+	decoders.RegisterContext(contentType, func(c context.Context, data []byte) (interface{}, error) {
+		return decoder(data)
+	})
+}
+
+// RegisterContext registers a body decoder that receives the context of validation.
+func (decoders *BodyDecoders) RegisterContext(contentType string, decoder BodyDecoderContext) {
 	if contentType == "" {
 		panic("contentType is empty")
 	}
 	if decoder == nil {
 		panic("decoder is not defined")
 	}
-	bodyDecoders[contentType] = decoder
+	decoders.mu.Lock()
+	if decoders.decoders == nil {
+		decoders.decoders = make(map[string]BodyDecoderContext)
+	}
+	decoders.decoders[contentType] = decoder
+	decoders.mu.Unlock()
+}
+
+// Unregister dissociates a body decoder from a content type in the registry.
+// The content type isn't decoded by the defaults of the registry either.
+func (decoders *BodyDecoders) Unregister(contentType string) {
+	if contentType == "" {
+		panic("contentType is empty")
+	}
+	decoders.mu.Lock()
+	if decoders.parent == nil {
+		delete(decoders.decoders, contentType)
+	} else {
+		if decoders.decoders == nil {
+			decoders.decoders = make(map[string]BodyDecoderContext)
+		}
+		decoders.decoders[contentType] = nil
+	}
+	decoders.mu.Unlock()
+}
+
+// Get returns the decoder for a content type.
+// A nil registry returns the package-level decoders.
+func (decoders *BodyDecoders) Get(contentType string) (BodyDecoderContext, bool) {
+	if decoders == nil {
+		decoders = bodyDecoders
+	}
+	for ; decoders != nil; decoders = decoders.parent {
+		decoders.mu.RLock()
+		decoder, ok := decoders.decoders[contentType]
+		decoders.mu.RUnlock()
+		if ok {
+			return decoder, decoder != nil
+		}
+	}
+	return nil, false
+}
+
+// RegisterBodyDecoder registers a request body's decoder for a content type.
+//
+// If a decoder for the specified content type already exists, the function replaces
+// it with the specified decoder.
+func RegisterBodyDecoder(contentType string, decoder BodyDecoder) {
+	bodyDecoders.Register(contentType, decoder)
+}
+
+// RegisterBodyDecoderContext registers a body decoder that receives the context of validation.
+func RegisterBodyDecoderContext(contentType string, decoder BodyDecoderContext) {
+	bodyDecoders.RegisterContext(contentType, decoder)
 }
 
 // UnregisterBodyDecoder dissociates a body decoder from a content type.
 //
 // Decoding this content type will result in an error.
 func UnregisterBodyDecoder(contentType string) {
-	if contentType == "" {
-		panic("contentType is empty")
-	}
-	delete(bodyDecoders, contentType)
+	bodyDecoders.Unregister(contentType)
 }
 
 // decodeBody returns a decoded body.
 // The function returns ParseError when a body is invalid.
 func decodeBody(body []byte, contentType string) (interface{}, error) {
-	decoder, ok := bodyDecoders[contentType]
+	return decodeBodyWith(context.Background(), nil, body, contentType)
+}
+
+// decodeBodyWith returns a body decoded by a decoder of the options, and checks limits of the options.
+// The function returns the error of the context when the context is done.
+func decodeBodyWith(c context.Context, options *Options, body []byte, contentType string) (interface{}, error) {
+	if options == nil {
+		options = DefaultOptions
+	}
+	decoder, ok := options.BodyDecoders.Get(contentType)
 	if !ok {
 		return nil, &ParseError{
 			Kind:   KindUnsupportedFormat,
 			Reason: fmt.Sprintf("an unsupported content type %q", contentType),
 		}
 	}
-	value, err := decoder(body)
+	if err := c.Err(); err != nil {
+		return nil, err
+	}
+	if isJSONMediaType(contentType) {
+		// JSON is checked before decoding, so deeply nested bodies aren't decoded at all.
+		if err := options.checkJSONBodyLimits(body); err != nil {
+			return nil, err
+		}
+	}
+	value, err := decoder(c, body)
 	if err != nil {
+		if err := c.Err(); err != nil {
+			return nil, err
+		}
 		return nil, &ParseError{Kind: KindInvalidFormat, Cause: err}
 	}
+	if !isJSONMediaType(contentType) {
+		if err := options.checkBodyValueLimits(value, 0); err != nil {
+			return nil, err
+		}
+	}
 	return value, nil
 }
@@ -1,9 +1,13 @@
 package openapi3filter
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"reflect"
+	"strconv"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/getkin/kin-openapi/openapi3"
@@ -37,6 +41,16 @@ func TestDecodeParameter(t *testing.T) {
 		stringSchema  = &openapi3.SchemaRef{Value: &openapi3.Schema{Type: "string"}}
 		arraySchema   = arrayOf(stringSchema)
 		objectSchema  = objectOf("id", stringSchema, "name", stringSchema)
+
+		mapOf = func(additional *openapi3.SchemaRef) *openapi3.SchemaRef {
+			return &openapi3.SchemaRef{Value: &openapi3.Schema{Type: "object", AdditionalProperties: additional}}
+		}
+		freeFormSchema = &openapi3.SchemaRef{Value: &openapi3.Schema{Type: "object", AdditionalPropertiesAllowed: boolPtr(true)}}
+		closedSchema   = &openapi3.SchemaRef{Value: &openapi3.Schema{
+			Type:                        "object",
+			Properties:                  objectSchema.Value.Properties,
+			AdditionalPropertiesAllowed: boolPtr(false),
+		}}
 	)
 
 	type testCase struct {
@@ -408,6 +422,42 @@ func TestDecodeParameter(t *testing.T) {
 					query: "param=foo",
 					err:   &ParseError{Kind: KindInvalidBool, Value: "foo"},
 				},
+				{
+					name:  "integer empty",
+					param: &openapi3.Parameter{Name: "param", In: "query", Schema: integerSchema},
+					query: "param=",
+					err:   &ParseError{Kind: KindEmptyValue},
+				},
+				{
+					name:  "integer empty nullable",
+					param: &openapi3.Parameter{Name: "param", In: "query", Schema: &openapi3.SchemaRef{Value: &openapi3.Schema{Type: "integer", Nullable: true}}},
+					query: "param=",
+					want:  nil,
+				},
+				{
+					name:  "integer empty allowed",
+					param: &openapi3.Parameter{Name: "param", In: "query", AllowEmptyValue: true, Schema: integerSchema},
+					query: "param=",
+					want:  nil,
+				},
+				{
+					name:  "string empty",
+					param: &openapi3.Parameter{Name: "param", In: "query", Schema: stringSchema},
+					query: "param=",
+					want:  nil,
+				},
+				{
+					name:  "enum",
+					param: &openapi3.Parameter{Name: "param", In: "query", Schema: openapi3.NewIntegerSchema().WithEnum(1, 2.0).NewRef()},
+					query: "param=1",
+					want:  float64(1),
+				},
+				{
+					name:  "enum invalid",
+					param: &openapi3.Parameter{Name: "param", In: "query", Schema: openapi3.NewStringSchema().WithEnum("foo", "bar").NewRef()},
+					query: "param=baz",
+					err:   &ParseError{Kind: KindInvalidEnum, Value: "baz"},
+				},
 			},
 		},
 		{
@@ -520,6 +570,94 @@ func TestDecodeParameter(t *testing.T) {
 					query: "foo=bar",
 					err:   &ParseError{Path: []interface{}{"foo"}, Cause: &ParseError{Kind: KindInvalidBool, Value: "bar"}},
 				},
+				{
+					name:  "deepObject additional properties",
+					param: &openapi3.Parameter{Name: "param", In: "query", Style: "deepObject", Explode: explode, Schema: mapOf(integerSchema)},
+					query: "param[a]=1&param[b]=2",
+					want:  map[string]interface{}{"a": 1.0, "b": 2.0},
+				},
+				{
+					name:  "form explode free-form",
+					param: &openapi3.Parameter{Name: "param", In: "query", Style: "form", Explode: explode, Schema: freeFormSchema},
+					query: "a=foo&b=bar",
+					want:  map[string]interface{}{"a": "foo", "b": "bar"},
+				},
+				{
+					name:  "form empty additional properties schema",
+					param: &openapi3.Parameter{Name: "param", In: "query", Style: "form", Explode: noExplode, Schema: mapOf(&openapi3.SchemaRef{Value: &openapi3.Schema{}})},
+					query: "param=a,foo",
+					want:  map[string]interface{}{"a": "foo"},
+				},
+				{
+					name:  "deepObject closed object",
+					param: &openapi3.Parameter{Name: "param", In: "query", Style: "deepObject", Explode: explode, Schema: closedSchema},
+					query: "param[id]=foo&param[name]=bar",
+					want:  map[string]interface{}{"id": "foo", "name": "bar"},
+				},
+				{
+					name:  "unexpected prop",
+					param: &openapi3.Parameter{Name: "param", In: "query", Style: "deepObject", Explode: explode, Schema: closedSchema},
+					query: "param[id]=foo&param[extra]=bar",
+					err:   &ParseError{Path: []interface{}{"extra"}, Cause: &ParseError{Kind: KindUnexpectedProperty, Value: "bar"}},
+				},
+				{
+					name:  "invalid additional prop",
+					param: &openapi3.Parameter{Name: "param", In: "query", Style: "deepObject", Explode: explode, Schema: mapOf(integerSchema)},
+					query: "param[a]=foo",
+					err:   &ParseError{Path: []interface{}{"a"}, Cause: &ParseError{Kind: KindInvalidInt, Value: "foo"}},
+				},
+				{
+					name: "deepObject allOf",
+					param: &openapi3.Parameter{Name: "param", In: "query", Style: "deepObject", Explode: explode, Schema: openapi3.NewAllOfSchema(
+						objectSchema.Value, objectOf("count", integerSchema).Value).NewRef()},
+					query: "param[id]=foo&param[name]=bar&param[count]=2",
+					want:  map[string]interface{}{"id": "foo", "name": "bar", "count": 2.0},
+				},
+				{
+					name:  "deepObject nested array",
+					param: &openapi3.Parameter{Name: "param", In: "query", Style: "deepObject", Explode: explode, Schema: objectOf("tags", arrayOf(integerSchema))},
+					query: "param[tags][]=1&param[tags][]=2",
+					want:  map[string]interface{}{"tags": []interface{}{1.0, 2.0}},
+				},
+				{
+					name:  "deepObject array of objects",
+					param: &openapi3.Parameter{Name: "param", In: "query", Style: "deepObject", Explode: explode, Schema: objectOf("items", arrayOf(objectSchema))},
+					query: "param[items][1][id]=b&param[items][1][name]=y&param[items][0][id]=a&param[items][0][name]=x",
+					want: map[string]interface{}{"items": []interface{}{
+						map[string]interface{}{"id": "a", "name": "x"},
+						map[string]interface{}{"id": "b", "name": "y"},
+					}},
+				},
+				{
+					name:  "deepObject invalid item",
+					param: &openapi3.Parameter{Name: "param", In: "query", Style: "deepObject", Explode: explode, Schema: objectOf("items", arrayOf(objectOf("id", integerSchema)))},
+					query: "param[items][0][id]=1&param[items][1][id]=foo",
+					err: &ParseError{Path: []interface{}{"items", 1, "id"}, Cause: &ParseError{Path: []interface{}{1, "id"},
+						Cause: &ParseError{Path: []interface{}{"id"}, Cause: &ParseError{Kind: KindInvalidInt, Value: "foo"}}}},
+				},
+				{
+					name:  "deepObject invalid index",
+					param: &openapi3.Parameter{Name: "param", In: "query", Style: "deepObject", Explode: explode, Schema: objectOf("items", arrayOf(objectSchema))},
+					query: "param[items][first][id]=a",
+					err:   &ParseError{Path: []interface{}{"items", "first"}, Cause: &ParseError{Path: []interface{}{"first"}, Cause: &ParseError{Kind: KindInvalidFormat}}},
+				},
+			},
+		},
+		{
+			name: "query content",
+			testCases: []testCase{
+				{
+					name:  "json",
+					param: &openapi3.Parameter{Name: "param", In: "query", Content: openapi3.NewContentWithJSONSchemaRef(arrayOf(objectSchema))},
+					query: `param=[{"id":"a"}]`,
+					want:  []interface{}{map[string]interface{}{"id": "a"}},
+				},
+				{
+					name:  "invalid json",
+					param: &openapi3.Parameter{Name: "param", In: "query", Content: openapi3.NewContentWithJSONSchemaRef(objectSchema)},
+					query: `param={`,
+					err:   &ParseError{Kind: KindInvalidFormat, Value: "{"},
+				},
 			},
 		},
 		{
@@ -561,6 +699,12 @@ func TestDecodeParameter(t *testing.T) {
 					header: "X-Param:foo",
 					err:    &ParseError{Kind: KindInvalidInt, Value: "foo"},
 				},
+				{
+					name:   "integer empty",
+					param:  &openapi3.Parameter{Name: "X-Param", In: "header", Schema: integerSchema},
+					header: "X-Param:",
+					err:    &ParseError{Kind: KindEmptyValue},
+				},
 				{
 					name:   "number",
 					param:  &openapi3.Parameter{Name: "X-Param", In: "header", Schema: numberSchema},
@@ -761,6 +905,18 @@ func TestDecodeParameter(t *testing.T) {
 					cookie: "X-Param:true,foo",
 					err:    &ParseError{Path: []interface{}{1}, Cause: &ParseError{Kind: KindInvalidBool, Value: "foo"}},
 				},
+				{
+					name:   "form explode",
+					param:  &openapi3.Parameter{Name: "X-Param", In: "cookie", Style: "form", Explode: explode, Schema: arraySchema},
+					cookie: "X-Param:foo;other:baz;X-Param:bar",
+					want:   []interface{}{"foo", "bar"},
+				},
+				{
+					name:   "invalid integer items of form explode",
+					param:  &openapi3.Parameter{Name: "X-Param", In: "cookie", Style: "form", Explode: explode, Schema: arrayOf(integerSchema)},
+					cookie: "X-Param:1;X-Param:foo",
+					err:    &ParseError{Path: []interface{}{1}, Cause: &ParseError{Kind: KindInvalidInt, Value: "foo"}},
+				},
 			},
 		},
 		{
@@ -790,6 +946,36 @@ func TestDecodeParameter(t *testing.T) {
 					cookie: "X-Param:foo,bar",
 					err:    &ParseError{Path: []interface{}{"foo"}, Cause: &ParseError{Kind: KindInvalidBool, Value: "bar"}},
 				},
+				{
+					name:   "form explode",
+					param:  &openapi3.Parameter{Name: "X-Param", In: "cookie", Style: "form", Explode: explode, Schema: objectSchema},
+					cookie: "id:foo;other:baz;name:bar",
+					want:   map[string]interface{}{"id": "foo", "name": "bar"},
+				},
+				{
+					name:   "form explode without properties",
+					param:  &openapi3.Parameter{Name: "X-Param", In: "cookie", Style: "form", Explode: explode, Schema: objectSchema},
+					cookie: "other:baz",
+					want:   nil,
+				},
+				{
+					name:   "form explode additional properties",
+					param:  &openapi3.Parameter{Name: "X-Param", In: "cookie", Style: "form", Explode: explode, Schema: mapOf(integerSchema)},
+					cookie: "a:1;b:2",
+					want:   map[string]interface{}{"a": 1.0, "b": 2.0},
+				},
+				{
+					name:   "invalid integer prop of form explode",
+					param:  &openapi3.Parameter{Name: "X-Param", In: "cookie", Style: "form", Explode: explode, Schema: objectOf("foo", integerSchema)},
+					cookie: "foo:bar",
+					err:    &ParseError{Path: []interface{}{"foo"}, Cause: &ParseError{Kind: KindInvalidInt, Value: "bar"}},
+				},
+				{
+					name:   "malformed form",
+					param:  &openapi3.Parameter{Name: "X-Param", In: "cookie", Style: "form", Explode: noExplode, Schema: objectSchema},
+					cookie: "X-Param:id,foo,name",
+					err:    &ParseError{Kind: KindInvalidFormat, Value: "id,foo,name"},
+				},
 			},
 		},
 	}
@@ -816,8 +1002,10 @@ func TestDecodeParameter(t *testing.T) {
 					}
 
 					if tc.cookie != "" {
-						v := strings.Split(tc.cookie, ":")
-						req.AddCookie(&http.Cookie{Name: v[0], Value: v[1]})
+						for _, cookie := range strings.Split(tc.cookie, ";") {
+							v := strings.Split(cookie, ":")
+							req.AddCookie(&http.Cookie{Name: v[0], Value: v[1]})
+						}
 					}
 
 					var path string
@@ -862,6 +1050,26 @@ func TestDecodeParameter(t *testing.T) {
 	}
 }
 
+func TestDecodeParameterUnsupportedSerialization(t *testing.T) {
+	schema := &openapi3.SchemaRef{Value: openapi3.NewObjectSchema().WithProperty("id", openapi3.NewStringSchema())}
+	for _, param := range []*openapi3.Parameter{
+		{Name: "param", In: "cookie", Style: "deepObject", Schema: schema},
+		{Name: "param", In: "header", Style: "form", Schema: schema},
+		{Name: "param", In: "query", Style: "matrix", Schema: schema},
+	} {
+		req, err := http.NewRequest(http.MethodGet, "http://test.org/test?param[id]=foo", nil)
+		require.NoError(t, err)
+		req.Header.Set("param", "id,foo")
+		req.AddCookie(&http.Cookie{Name: "param", Value: "id,foo"})
+		_, err = decodeParameter(param, &RequestValidationInput{Request: req})
+		var serializationErr *SerializationMethodError
+		require.True(t, errors.As(err, &serializationErr), "%s parameter with style %q", param.In, param.Style)
+		require.Equal(t, param, serializationErr.Parameter)
+		var parseErr *ParseError
+		require.False(t, errors.As(err, &parseErr))
+	}
+}
+
 func TestDecodeBody(t *testing.T) {
 	testCases := []struct {
 		name    string
@@ -906,7 +1114,7 @@ func TestDecodeBody(t *testing.T) {
 
 func TestRegisterAndUnregisterBodyDecoder(t *testing.T) {
 	var (
-		contentType = "text/csv"
+		contentType = "text/tab-separated-values"
 		decoder     = func(body []byte) (interface{}, error) {
 			var vv []interface{}
 			for _, v := range strings.Split(string(body), ",") {
@@ -932,6 +1140,87 @@ func TestRegisterAndUnregisterBodyDecoder(t *testing.T) {
 	require.Truef(t, matchParseError(err, wantErr), "got error:\n%v\nwant error:\n%v", err, wantErr)
 }
 
+func TestBodyDecoders(t *testing.T) {
+	decoders := NewBodyDecoders()
+	decoders.Register("text/tab-separated-values", func(body []byte) (interface{}, error) {
+		return strings.Split(string(body), ","), nil
+	})
+
+	got, err := decodeBodyWith(context.Background(), &Options{BodyDecoders: decoders}, []byte("foo,bar"), "text/tab-separated-values")
+	require.NoError(t, err)
+	require.Equal(t, []string{"foo", "bar"}, got)
+	_, err = decodeBody([]byte("foo,bar"), "text/tab-separated-values")
+	require.Error(t, err, "package-level decoders must not be affected")
+
+	got, err = decodeBodyWith(context.Background(), &Options{BodyDecoders: decoders}, []byte(`{"a":1}`), "application/json")
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"a": 1.0}, got)
+
+	decoders.Unregister("text/tab-separated-values")
+	_, err = decodeBodyWith(context.Background(), &Options{BodyDecoders: decoders}, []byte("foo,bar"), "text/tab-separated-values")
+	require.Error(t, err)
+
+	decoders.Unregister("application/json")
+	_, err = decodeBodyWith(context.Background(), &Options{BodyDecoders: decoders}, []byte(`{"a":1}`), "application/json")
+	require.Error(t, err, "unregistered defaults must not be used")
+	_, err = decodeBody([]byte(`{"a":1}`), "application/json")
+	require.NoError(t, err, "package-level decoders must not be affected")
+	decoders.Register("application/json", func(body []byte) (interface{}, error) {
+		return string(body), nil
+	})
+	got, err = decodeBodyWith(context.Background(), &Options{BodyDecoders: decoders}, []byte(`{"a":1}`), "application/json")
+	require.NoError(t, err)
+	require.Equal(t, `{"a":1}`, got)
+
+	var empty BodyDecoders
+	empty.Register("text/plain", func(body []byte) (interface{}, error) {
+		return string(body), nil
+	})
+	_, ok := empty.Get("text/plain")
+	require.True(t, ok)
+	_, ok = empty.Get("application/json")
+	require.False(t, ok, "the zero value has no defaults")
+	empty.Unregister("text/plain")
+	_, ok = empty.Get("text/plain")
+	require.False(t, ok)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			decoders.Register("text/plain", func(body []byte) (interface{}, error) {
+				return string(body), nil
+			})
+		}()
+		go func() {
+			defer wg.Done()
+			decodeBodyWith(context.Background(), &Options{BodyDecoders: decoders}, []byte("{}"), "application/json")
+		}()
+	}
+	wg.Wait()
+}
+
+func TestParseErrorChain(t *testing.T) {
+	cause := &ParseError{Kind: KindInvalidInt, Value: "foo", Reason: "an invalid integer", Cause: strconv.ErrSyntax}
+	err := wrapParseError("items", wrapParseError(3, wrapParseError("name", cause)))
+
+	var parseErr *ParseError
+	require.True(t, errors.As(err, &parseErr))
+	require.Equal(t, []interface{}{"items", 3, "name"}, parseErr.Path)
+	require.Equal(t, "path [items 3 name]: value foo: an invalid integer: invalid syntax", err.Error())
+	require.Equal(t, strconv.ErrSyntax, parseErr.RootCause())
+	require.True(t, errors.Is(err, KindInvalidInt))
+	require.False(t, errors.Is(err, KindInvalidBool))
+	require.False(t, errors.Is(err, KindOther), "wrappers don't have a kind")
+	require.True(t, errors.Is(wrapParseError("items", &ParseError{Reason: "other"}), KindOther))
+
+	requestErr := &RequestError{Reason: "failed to decode", Err: err}
+	require.True(t, errors.Is(requestErr, KindInvalidInt))
+	require.True(t, errors.Is(requestErr, strconv.ErrSyntax))
+	require.True(t, errors.As(requestErr, &parseErr))
+}
+
 func matchParseError(got, want error) bool {
 	wErr, ok := want.(*ParseError)
 	if !ok {
@@ -955,3 +1244,125 @@ func matchParseError(got, want error) bool {
 	}
 	return true
 }
+
+func TestAppendDeepObjectPath(t *testing.T) {
+	testCases := []struct {
+		key  string
+		want []string
+	}{
+		{key: "filter[name]", want: []string{"name"}},
+		{key: "filter[items][0][id]", want: []string{"items", "0", "id"}},
+		{key: "filter[tags][]", want: []string{"tags"}},
+		{key: "filter[a]b[c]", want: []string{"a"}},
+		{key: "filter[]"},
+		{key: "filter"},
+		{key: "filter[name"},
+		{key: "xfilter[name]"},
+		{key: "filters[name]"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.key, func(t *testing.T) {
+			require.Equal(t, tc.want, appendDeepObjectPath(nil, "filter", tc.key))
+		})
+	}
+	require.Equal(t, []string{"b"}, appendDeepObjectPath(nil, "a.c", "a.c[b]"))
+	require.Nil(t, appendDeepObjectPath(nil, "a.c", "abc[b]"))
+}
+
+func BenchmarkDecodeParameter(b *testing.B) {
+	explode, noExplode := true, false
+	object := &openapi3.SchemaRef{Value: openapi3.NewObjectSchema().
+		WithProperty("id", openapi3.NewIntegerSchema()).
+		WithProperty("name", openapi3.NewStringSchema()).
+		WithProperty("active", openapi3.NewBoolSchema())}
+	nested := &openapi3.SchemaRef{Value: openapi3.NewObjectSchema().
+		WithProperty("id", openapi3.NewIntegerSchema()).
+		WithPropertyRef("owner", object).
+		WithProperty("tags", openapi3.NewArraySchema().WithItems(openapi3.NewStringSchema()))}
+	array := &openapi3.SchemaRef{Value: openapi3.NewArraySchema().WithItems(openapi3.NewIntegerSchema())}
+
+	benchmarks := []struct {
+		name   string
+		path   string
+		target string
+		header string
+		cookie string
+		param  *openapi3.Parameter
+	}{
+		{
+			name:   "path/primitive",
+			path:   "/test/{id}",
+			target: "/test/42",
+			param:  &openapi3.Parameter{Name: "id", In: "path", Required: true, Schema: openapi3.NewIntegerSchema().NewRef()},
+		},
+		{
+			name:   "path/object",
+			path:   "/test/{obj}",
+			target: "/test/id,1,name,foo,active,true",
+			param:  &openapi3.Parameter{Name: "obj", In: "path", Required: true, Schema: object},
+		},
+		{
+			name:   "query/array",
+			target: "/test?ids=1,2,3,4",
+			param:  &openapi3.Parameter{Name: "ids", In: "query", Explode: &noExplode, Schema: array},
+		},
+		{
+			name:   "query/form",
+			target: "/test?id=1&name=foo&active=true&limit=10",
+			param:  &openapi3.Parameter{Name: "obj", In: "query", Explode: &explode, Schema: object},
+		},
+		{
+			name:   "query/deepObject",
+			target: "/test?obj[id]=1&obj[owner][id]=2&obj[owner][name]=foo&obj[tags][]=a&obj[tags][]=b&limit=10",
+			param:  &openapi3.Parameter{Name: "obj", In: "query", Style: "deepObject", Explode: &explode, Schema: nested},
+		},
+		{
+			name:   "header/object",
+			target: "/test",
+			header: "id=1,name=foo,active=true",
+			param:  &openapi3.Parameter{Name: "X-Object", In: "header", Explode: &explode, Schema: object},
+		},
+		{
+			name:   "cookie/object",
+			target: "/test",
+			cookie: "id,1,name,foo,active,true",
+			param:  &openapi3.Parameter{Name: "obj", In: "cookie", Explode: &noExplode, Schema: object},
+		},
+	}
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			path := bm.path
+			if path == "" {
+				path = "/test"
+			}
+			spec := &openapi3.Swagger{}
+			op := &openapi3.Operation{OperationID: "test", Parameters: []*openapi3.ParameterRef{{Value: bm.param}}}
+			spec.AddOperation(path, http.MethodGet, op)
+			router, err := NewCompiledRouter(spec)
+			require.NoError(b, err)
+
+			req, err := http.NewRequest(http.MethodGet, "http://example.com"+bm.target, nil)
+			require.NoError(b, err)
+			if bm.header != "" {
+				req.Header.Set(bm.param.Name, bm.header)
+			}
+			if bm.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: bm.param.Name, Value: bm.cookie})
+			}
+			route, pathParams, err := router.FindRoute(req.Method, req.URL)
+			require.NoError(b, err)
+			input := &RequestValidationInput{Request: req, PathParams: pathParams, Route: route}
+			value, err := decodeParameter(bm.param, input)
+			require.NoError(b, err)
+			require.NotNil(b, value)
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := decodeParameter(bm.param, input); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,245 @@
+package openapi3filter
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+const (
+	ResponseDiffInStatus = "status"
+	ResponseDiffInHeader = "header"
+	ResponseDiffInBody   = "body"
+)
+
+// ResponseDiff describes how a response differs from the document.
+type ResponseDiff struct {
+	// In is either "status", "header", or "body".
+	In string
+
+	// Name is the name of a header, or the JSON pointer of a value in the body like "/items/0/id".
+	// The JSON pointer is empty if the whole body differs.
+	Name string
+
+	Reason string
+	Err    error
+}
+
+func (diff *ResponseDiff) String() string {
+	reason := diff.Reason
+	if diff.Err != nil {
+		reason += ": " + diff.Err.Error()
+	}
+	switch {
+	case diff.In == ResponseDiffInHeader:
+		return fmt.Sprintf("header '%s': %s", diff.Name, reason)
+	case diff.In == ResponseDiffInBody && diff.Name != "":
+		return fmt.Sprintf("body at '%s': %s", diff.Name, reason)
+	default:
+		return fmt.Sprintf("%s: %s", diff.In, reason)
+	}
+}
+
+// ResponseConformanceError lists differences between a response and the document.
+type ResponseConformanceError struct {
+	Route  *Route
+	Status int
+	Diffs  []*ResponseDiff
+}
+
+func (err *ResponseConformanceError) Error() string {
+	messages := make([]string, 0, len(err.Diffs))
+	for _, diff := range err.Diffs {
+		messages = append(messages, diff.String())
+	}
+	return fmt.Sprintf("Response of %s %s with status %d doesn't conform to the document: %s",
+		err.Route.Method, err.Route.Path, err.Status, strings.Join(messages, ", "))
+}
+
+// ValidateHTTPResponse validates status, headers, and body of a response of the route,
+// which is useful in tests of handlers:
+//
+//	resp, err := http.Get(server.URL + "/users/1")
+//	require.NoError(t, err)
+//	require.NoError(t, openapi3filter.ValidateHTTPResponse(c, route, nil, resp, nil))
+//
+// If the request is nil, the request of the response is used.
+// The body of the response is restored, so it can be read after validation.
+//
+// The function returns ResponseConformanceError with every header that differs,
+// but only the first difference of the body.
+func ValidateHTTPResponse(c context.Context, route *Route, req *http.Request, resp *http.Response, options *Options) error {
+	if route == nil || route.Operation == nil {
+		return errRouteMissingOperation
+	}
+	if req == nil {
+		req = resp.Request
+	}
+	if req == nil {
+		req = &http.Request{
+			Method: route.Method,
+			URL:    &url.URL{Path: route.Path},
+			Header: make(http.Header),
+		}
+	}
+	var data []byte
+	if resp.Body != nil {
+		var err error
+		data, err = ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return err
+		}
+		resp.Body = ioutil.NopCloser(bytes.NewReader(data))
+	}
+	if options == nil {
+		options = DefaultOptions
+	}
+	input := &ResponseValidationInput{
+		RequestValidationInput: &RequestValidationInput{
+			Request: req,
+			Route:   route,
+			Options: options,
+		},
+		Status:  resp.StatusCode,
+		Header:  resp.Header,
+		Options: options,
+	}
+	diffs, err := responseDiffs(options.contextWithRegexEngine(c), input.SetBodyBytes(data), options)
+	if err != nil {
+		return err
+	}
+	if coverage := options.Coverage; coverage != nil {
+		coverage.recordResponse(input, len(diffs) == 0)
+	}
+	if len(diffs) > 0 {
+		return &ResponseConformanceError{
+			Route:  route,
+			Status: resp.StatusCode,
+			Diffs:  diffs,
+		}
+	}
+	return nil
+}
+
+// ValidateRecordedResponse validates a response recorded by a handler like ValidateHTTPResponse.
+func ValidateRecordedResponse(c context.Context, route *Route, req *http.Request, recorder *httptest.ResponseRecorder, options *Options) error {
+	return ValidateHTTPResponse(c, route, req, recorder.Result(), options)
+}
+
+func responseDiffs(c context.Context, input *ResponseValidationInput, options *Options) ([]*ResponseDiff, error) {
+	if input.RequestValidationInput.Request.Method == http.MethodHead {
+		return nil, nil
+	}
+	responses := input.RequestValidationInput.Route.Operation.Responses
+	if len(responses) == 0 {
+		return nil, nil
+	}
+	status := input.Status
+	responseRef, _ := responses.ResolveStatus(status)
+	if responseRef == nil || responseRef.Value == nil {
+		// Contract tests are interested in statuses that the document doesn't declare.
+		return []*ResponseDiff{{
+			In:     ResponseDiffInStatus,
+			Reason: fmt.Sprintf("status %d is not declared", status),
+		}}, nil
+	}
+	response := responseRef.Value
+
+	var diffs []*ResponseDiff
+	addHeaderDiff := func(name string, err error) error {
+		if err == nil {
+			return nil
+		}
+		responseErr, ok := err.(*ResponseError)
+		if !ok {
+			return err
+		}
+		diffs = append(diffs, &ResponseDiff{
+			In:     ResponseDiffInHeader,
+			Name:   name,
+			Reason: responseErr.Reason,
+			Err:    responseErr.Err,
+		})
+		return nil
+	}
+	for _, name := range responseHeaderNames(response) {
+		if err := addHeaderDiff(name, validateResponseHeader(c, input, name, response.Headers[name])); err != nil {
+			return nil, err
+		}
+	}
+	if options.StrictContentEncoding {
+		if err := addHeaderDiff("Content-Encoding", validateContentEncoding(c, input, response)); err != nil {
+			return nil, err
+		}
+	}
+	if options.ValidateConditionalHeaders {
+		if err := addHeaderDiff(headerETag, validateETagHeader(input)); err != nil {
+			return nil, err
+		}
+	}
+	if options.StrictCacheHeaders {
+		if err := addHeaderDiff(headerCacheControl, validateCacheControlHeader(input, response)); err != nil {
+			return nil, err
+		}
+		if err := addHeaderDiff(headerExpires, validateExpiresHeader(input, response)); err != nil {
+			return nil, err
+		}
+	}
+	if options.ExcludeResponseBody || len(response.Content) == 0 {
+		return diffs, nil
+	}
+	contentType := input.Header.Get("Content-Type")
+	if mediaType := parseMediaType(contentType); response.Content[mediaType] == nil {
+		if !options.ExcludeContentType {
+			diffs = append(diffs, &ResponseDiff{
+				In:     ResponseDiffInHeader,
+				Name:   "Content-Type",
+				Reason: fmt.Sprintf("content type %q is not declared", contentType),
+			})
+		}
+		return diffs, nil
+	}
+
+	// Headers have been validated above.
+	bodyOptions := *options
+	bodyOptions.IncludeResponseHeaders = false
+	bodyOptions.StrictContentEncoding = false
+	bodyOptions.ValidateConditionalHeaders = false
+	bodyOptions.StrictCacheHeaders = false
+	if err := validateResponse(c, input, &bodyOptions); err != nil {
+		responseErr, ok := err.(*ResponseError)
+		if !ok {
+			return nil, err
+		}
+		diff := &ResponseDiff{
+			In:     ResponseDiffInBody,
+			Reason: responseErr.Reason,
+			Err:    responseErr.Err,
+		}
+		var schemaErr *openapi3.SchemaError
+		if errors.As(responseErr.Err, &schemaErr) {
+			diff.Name = jsonPointer(schemaErr.JSONPointer())
+		}
+		diffs = append(diffs, diff)
+	}
+	return diffs, nil
+}
+
+// jsonPointer returns the JSON pointer of the path.
+func jsonPointer(path []string) string {
+	var buf strings.Builder
+	for _, key := range path {
+		buf.WriteByte('/')
+		buf.WriteString(strings.NewReplacer("~", "~0", "/", "~1").Replace(key))
+	}
+	return buf.String()
+}
@@ -0,0 +1,111 @@
+package openapi3filter_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/stretchr/testify/require"
+)
+
+const responseConformanceSpec = `
+openapi: 3.0.0
+info:
+  title: Users
+  version: "1.0"
+paths:
+  /users:
+    get:
+      responses:
+        "200":
+          description: users
+          headers:
+            X-Total-Count:
+              schema:
+                type: integer
+            X-Rate-Limit:
+              schema:
+                type: integer
+                maximum: 100
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  items:
+                    type: array
+                    items:
+                      type: object
+                      properties:
+                        id:
+                          type: integer
+`
+
+func TestValidateHTTPResponse(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(responseConformanceSpec))
+	require.NoError(t, err)
+	router := openapi3filter.NewRouter().WithSwagger(swagger)
+	req := httptest.NewRequest(http.MethodGet, "/users", nil)
+	route, _, err := router.FindRoute(req.Method, req.URL)
+	require.NoError(t, err)
+
+	record := func(status int, header map[string]string, body interface{}) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		rec.Header().Set("Content-Type", "application/json")
+		for name, value := range header {
+			rec.Header().Set(name, value)
+		}
+		rec.WriteHeader(status)
+		require.NoError(t, json.NewEncoder(rec).Encode(body))
+		return rec
+	}
+	validate := func(rec *httptest.ResponseRecorder) []*openapi3filter.ResponseDiff {
+		err := openapi3filter.ValidateRecordedResponse(context.Background(), route, req, rec, nil)
+		if err == nil {
+			return nil
+		}
+		var conformanceErr *openapi3filter.ResponseConformanceError
+		require.True(t, errors.As(err, &conformanceErr), "%v", err)
+		return conformanceErr.Diffs
+	}
+
+	valid := map[string]interface{}{"items": []interface{}{map[string]interface{}{"id": 1}}}
+	require.Empty(t, validate(record(200, map[string]string{"X-Total-Count": "1"}, valid)))
+
+	diffs := validate(record(404, nil, nil))
+	require.Len(t, diffs, 1)
+	require.Equal(t, openapi3filter.ResponseDiffInStatus, diffs[0].In)
+
+	// Every header that differs and the first difference of the body are reported.
+	invalid := map[string]interface{}{"items": []interface{}{map[string]interface{}{"id": "a"}}}
+	diffs = validate(record(200, map[string]string{"X-Total-Count": "many", "X-Rate-Limit": "1000"}, invalid))
+	require.Len(t, diffs, 3)
+	require.Equal(t, openapi3filter.ResponseDiffInHeader, diffs[0].In)
+	require.Equal(t, "X-Rate-Limit", diffs[0].Name)
+	require.Equal(t, "X-Total-Count", diffs[1].Name)
+	require.Equal(t, openapi3filter.ResponseDiffInBody, diffs[2].In)
+	require.Equal(t, "/items/0/id", diffs[2].Name)
+
+	diffs = validate(record(200, map[string]string{"Content-Type": "text/plain"}, valid))
+	require.Len(t, diffs, 1)
+	require.Equal(t, "Content-Type", diffs[0].Name)
+
+	// The body of a real response can be read after validation.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(valid)
+	}))
+	defer server.Close()
+	resp, err := http.Get(server.URL + "/users")
+	require.NoError(t, err)
+	require.NoError(t, openapi3filter.ValidateHTTPResponse(context.Background(), route, nil, resp, nil))
+	data, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"items":[{"id":1}]}`, string(data))
+}
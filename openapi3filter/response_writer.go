@@ -0,0 +1,81 @@
+package openapi3filter
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// ResponseWriter writes responses of an operation after validating them against the document,
+// so handlers catch violations of the contract where they happen.
+//
+// Example:
+//
+//	w := openapi3filter.NewResponseWriter(rw, input)
+//	if err := w.WriteJSON(http.StatusOK, user); err != nil {
+//		log.Printf("response of %s violates the contract: %v", input.Route.Path, err)
+//	}
+type ResponseWriter struct {
+	http.ResponseWriter
+
+	// Input is the validated request that the responses belong to.
+	Input *RequestValidationInput
+
+	// Options of response validation. If nil, options of the input are used.
+	Options *Options
+
+	// Disabled writes responses without validation, for example in production.
+	Disabled bool
+}
+
+// NewResponseWriter returns a writer that validates responses to the request.
+func NewResponseWriter(w http.ResponseWriter, input *RequestValidationInput) *ResponseWriter {
+	return &ResponseWriter{
+		ResponseWriter: w,
+		Input:          input,
+	}
+}
+
+// WriteJSON encodes the value as JSON and writes it with the status.
+//
+// The function returns ResponseError and writes nothing if the response is invalid.
+func (w *ResponseWriter) WriteJSON(status int, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("Failed to encode response body: %v", err)
+	}
+	return w.WriteBody(status, "application/json", data)
+}
+
+// WriteBody writes the body with the status.
+// The header Content-Type is set to the content type unless it is already set.
+//
+// The function returns ResponseError and writes nothing if the response is invalid.
+func (w *ResponseWriter) WriteBody(status int, contentType string, body []byte) error {
+	header := w.Header()
+	setContentType := header.Get("Content-Type") == "" && contentType != ""
+	if setContentType {
+		header.Set("Content-Type", contentType)
+	}
+	if !w.Disabled {
+		options := w.Options
+		if options == nil {
+			options = w.Input.Options
+		}
+		input := &ResponseValidationInput{
+			RequestValidationInput: w.Input,
+			Status:                 status,
+			Header:                 header,
+			Options:                options,
+		}
+		if err := ValidateResponse(w.Input.Request.Context(), input.SetBodyBytes(body)); err != nil {
+			if setContentType {
+				header.Del("Content-Type")
+			}
+			return err
+		}
+	}
+	w.WriteHeader(status)
+	_, err := w.Write(body)
+	return err
+}
@@ -0,0 +1,78 @@
+package openapi3filter_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/stretchr/testify/require"
+)
+
+const responseWriterSpec = `
+openapi: 3.0.0
+info:
+  title: Users
+  version: "1.0"
+paths:
+  /users/{id}:
+    get:
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        "200":
+          description: user
+          content:
+            application/json:
+              schema:
+                type: object
+                required: [name]
+                properties:
+                  name:
+                    type: string
+`
+
+func TestResponseWriter(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(responseWriterSpec))
+	require.NoError(t, err)
+	router := openapi3filter.NewRouter().WithSwagger(swagger)
+	req := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+	route, pathParams, err := router.FindRoute(req.Method, req.URL)
+	require.NoError(t, err)
+	input := &openapi3filter.RequestValidationInput{
+		Request:    req,
+		PathParams: pathParams,
+		Route:      route,
+	}
+
+	rec := httptest.NewRecorder()
+	w := openapi3filter.NewResponseWriter(rec, input)
+	require.NoError(t, w.WriteJSON(http.StatusOK, map[string]interface{}{"name": "alice"}))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	require.JSONEq(t, `{"name":"alice"}`, rec.Body.String())
+
+	// Invalid responses aren't written.
+	rec = httptest.NewRecorder()
+	w = openapi3filter.NewResponseWriter(rec, input)
+	err = w.WriteJSON(http.StatusOK, map[string]interface{}{"name": 1})
+	require.IsType(t, &openapi3filter.ResponseError{}, err)
+	require.Empty(t, rec.Body.String())
+	require.Empty(t, rec.Header().Get("Content-Type"))
+
+	rec = httptest.NewRecorder()
+	w = openapi3filter.NewResponseWriter(rec, input)
+	w.Options = &openapi3filter.Options{IncludeResponseStatus: true}
+	require.Error(t, w.WriteJSON(http.StatusNotFound, nil))
+
+	rec = httptest.NewRecorder()
+	w = openapi3filter.NewResponseWriter(rec, input)
+	w.Disabled = true
+	require.NoError(t, w.WriteJSON(http.StatusOK, map[string]interface{}{"name": 1}))
+	require.JSONEq(t, `{"name":1}`, rec.Body.String())
+}
@@ -0,0 +1,192 @@
+// Package routeadapter builds inputs of package openapi3filter for requests routed by third-party routers.
+//
+// The package doesn't depend on any router. It needs the route's path template and
+// a function that returns values of path parameters, which all popular routers provide:
+//
+//	// github.com/go-chi/chi
+//	input, err := adapter.RequestValidationInput(req, chi.RouteContext(req.Context()).RoutePattern(), func(name string) string {
+//	  return chi.URLParam(req, name)
+//	})
+//
+//	// github.com/gorilla/mux
+//	template, _ := mux.CurrentRoute(req).GetPathTemplate()
+//	vars := mux.Vars(req)
+//	input, err := adapter.RequestValidationInput(req, template, func(name string) string {
+//	  return vars[name]
+//	})
+//
+//	// github.com/gin-gonic/gin
+//	input, err := adapter.RequestValidationInput(c.Request, c.FullPath(), c.Param)
+//
+//	// github.com/labstack/echo
+//	input, err := adapter.RequestValidationInput(c.Request(), c.Path(), c.Param)
+package routeadapter
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+)
+
+// Adapter maps path templates of third-party routers to paths of an OpenAPI specification.
+// It's safe for concurrent use.
+type Adapter struct {
+	swagger   *openapi3.Swagger
+	paths     map[string]*adapterPath
+	basePaths []string
+}
+
+type adapterPath struct {
+	path          string
+	pathItem      *openapi3.PathItem
+	variableNames []string
+}
+
+// New returns an adapter for paths of the Swagger.
+//
+// The function returns an error if the Swagger has paths that differ only in names of variables.
+func New(swagger *openapi3.Swagger) (*Adapter, error) {
+	adapter := &Adapter{
+		swagger: swagger,
+		paths:   make(map[string]*adapterPath, len(swagger.Paths)),
+	}
+	paths := make([]string, 0, len(swagger.Paths))
+	for path := range swagger.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		key, variableNames := parseTemplate(path)
+		if existing := adapter.paths[key]; existing != nil {
+			return nil, fmt.Errorf("Paths '%s' and '%s' are ambiguous", existing.path, path)
+		}
+		adapter.paths[key] = &adapterPath{
+			path:          path,
+			pathItem:      swagger.Paths[path],
+			variableNames: variableNames,
+		}
+	}
+	for _, server := range swagger.Servers {
+		u, err := url.Parse(server.URL)
+		if err != nil {
+			continue
+		}
+		if basePath := strings.TrimRight(u.Path, "/"); basePath != "" {
+			adapter.basePaths = append(adapter.basePaths, basePath)
+		}
+	}
+	return adapter, nil
+}
+
+// RequestValidationInput returns an input for the request routed to the path template.
+//
+// The template uses either braces ("/users/{id}") or colons ("/users/:id") for variables.
+// Variables of the template map to variables of the OpenAPI path by their position,
+// so their names don't need to be the same. The template may include the base path of a server.
+//
+// The function returns openapi3filter.RouteError if the template doesn't match an operation.
+func (adapter *Adapter) RequestValidationInput(req *http.Request, template string, param func(name string) string) (*openapi3filter.RequestValidationInput, error) {
+	route, pathParams, err := adapter.FindRoute(req.Method, template, param)
+	if err != nil {
+		return nil, err
+	}
+	return &openapi3filter.RequestValidationInput{
+		Request:    req,
+		PathParams: pathParams,
+		Route:      route,
+	}, nil
+}
+
+// FindRoute returns the route of the method and path template and values of path parameters.
+func (adapter *Adapter) FindRoute(method string, template string, param func(name string) string) (*openapi3filter.Route, map[string]string, error) {
+	key, names := parseTemplate(template)
+	path := adapter.paths[key]
+	for _, basePath := range adapter.basePaths {
+		if path != nil {
+			break
+		}
+		if strings.HasPrefix(key, basePath+"/") {
+			path = adapter.paths[key[len(basePath):]]
+		}
+	}
+	if path == nil {
+		return nil, nil, &openapi3filter.RouteError{
+			Route: openapi3filter.Route{
+				Swagger: adapter.swagger,
+			},
+			Reason: "Path was not found",
+		}
+	}
+	method = strings.ToUpper(method)
+	operation := path.pathItem.GetOperation(method)
+	if operation == nil {
+		return nil, nil, &openapi3filter.RouteError{
+			Route: openapi3filter.Route{
+				Swagger: adapter.swagger,
+			},
+			Reason: "Path doesn't support the HTTP method",
+		}
+	}
+	pathParams := make(map[string]string, len(names))
+	for i, name := range names {
+		pathParams[path.variableNames[i]] = param(name)
+	}
+	return &openapi3filter.Route{
+		Swagger:   adapter.swagger,
+		Path:      path.path,
+		PathItem:  path.pathItem,
+		Method:    method,
+		Operation: operation,
+	}, pathParams, nil
+}
+
+// parseTemplate returns the template with variables replaced by "{}" and names of the variables.
+//
+// Supported variables are "{name}", "{name:pattern}", ":name", "*name", and "*".
+func parseTemplate(template string) (string, []string) {
+	segments := strings.Split(strings.TrimRight(template, "/"), "/")
+	var names []string
+	for i, segment := range segments {
+		switch {
+		case strings.HasPrefix(segment, ":"):
+			names = append(names, segment[1:])
+			segments[i] = "{}"
+		case strings.HasPrefix(segment, "*"):
+			name := segment[1:]
+			if name == "" {
+				name = "*"
+			}
+			names = append(names, name)
+			segments[i] = "{}"
+		case strings.Contains(segment, "{"):
+			var buf strings.Builder
+			for {
+				start := strings.IndexByte(segment, '{')
+				if start < 0 {
+					break
+				}
+				end := strings.IndexByte(segment[start:], '}')
+				if end < 0 {
+					break
+				}
+				end += start
+				name := segment[start+1 : end]
+				if j := strings.IndexByte(name, ':'); j >= 0 {
+					name = name[:j]
+				}
+				names = append(names, strings.TrimSpace(name))
+				buf.WriteString(segment[:start])
+				buf.WriteString("{}")
+				segment = segment[end+1:]
+			}
+			buf.WriteString(segment)
+			segments[i] = buf.String()
+		}
+	}
+	return strings.Join(segments, "/"), names
+}
@@ -0,0 +1,92 @@
+package routeadapter_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/openapi3filter/routeadapter"
+	"github.com/stretchr/testify/require"
+)
+
+const spec = `
+openapi: 3.0.0
+info:
+  title: Users
+  version: "1.0"
+servers:
+  - url: https://example.com/api
+paths:
+  /users/{id}:
+    get:
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: integer
+      responses:
+        "200":
+          description: user
+  /users/{id}/avatar.{format}:
+    get:
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: integer
+        - name: format
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        "200":
+          description: avatar
+`
+
+func TestRequestValidationInput(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(spec))
+	require.NoError(t, err)
+	adapter, err := routeadapter.New(swagger)
+	require.NoError(t, err)
+
+	params := map[string]string{"userID": "42", "id": "42", "format": "png"}
+	param := func(name string) string {
+		return params[name]
+	}
+	for _, template := range []string{
+		"/users/{id}",
+		"/users/{userID:[0-9]+}",
+		"/users/:userID",
+		"/api/users/:id",
+	} {
+		req := httptest.NewRequest("GET", "/api/users/42", nil)
+		input, err := adapter.RequestValidationInput(req, template, param)
+		require.NoError(t, err, template)
+		require.Equal(t, "/users/{id}", input.Route.Path, template)
+		require.Equal(t, map[string]string{"id": "42"}, input.PathParams, template)
+		err = openapi3filter.ValidateRequest(context.Background(), input)
+		require.NoError(t, err, template)
+	}
+
+	route, pathParams, err := adapter.FindRoute("GET", "/users/{id}/avatar.{format}", param)
+	require.NoError(t, err)
+	require.Equal(t, "/users/{id}/avatar.{format}", route.Path)
+	require.Equal(t, map[string]string{"id": "42", "format": "png"}, pathParams)
+
+	_, _, err = adapter.FindRoute("DELETE", "/users/:id", param)
+	require.Error(t, err)
+	_, _, err = adapter.FindRoute("GET", "/groups/:id", param)
+	require.Error(t, err)
+
+	params["id"] = "x"
+	req := httptest.NewRequest("GET", "/api/users/x", nil)
+	input, err := adapter.RequestValidationInput(req, "/users/:id", param)
+	require.NoError(t, err)
+	err = openapi3filter.ValidateRequest(context.Background(), input)
+	require.Error(t, err)
+}
@@ -22,6 +22,25 @@ type Route struct {
 
 	// For developers who want use the router for handling too
 	Handler http.Handler
+
+	// idempotencyKey is parsed when the route is added to a router.
+	idempotencyKey *parsedIdempotencyKey
+}
+
+// Parameters returns parameters of the operation, including parameters of the path item
+// that the operation doesn't override.
+func (route *Route) Parameters() openapi3.Parameters {
+	return route.PathItem.OperationParameters(route.Operation)
+}
+
+// routeParameters returns values of parameters of the route.
+func routeParameters(route *Route) []*openapi3.Parameter {
+	parameterRefs := route.Parameters()
+	result := make([]*openapi3.Parameter, 0, len(parameterRefs))
+	for _, parameterRef := range parameterRefs {
+		result = append(result, parameterRef.Value)
+	}
+	return result
 }
 
 // Routers maps a HTTP request to a Router.
@@ -57,6 +76,12 @@ func (routers Routers) FindRoute(method string, url *url.URL) (*Router, *Route,
 type Router struct {
 	swagger  *openapi3.Swagger
 	pathNode *pathpattern.Node
+
+	// compiled is set by NewCompiledRouter
+	compiled *compiledRoutes
+
+	serverOverrides  []*serverOverride
+	overriddenRoutes map[*Route]bool
 }
 
 // NewRouter creates a new router.
@@ -96,6 +121,9 @@ func (router *Router) AddSwaggerFromFile(path string) error {
 
 // AddSwagger adds all operations in the OpenAPI specification.
 func (router *Router) AddSwagger(swagger *openapi3.Swagger) error {
+	if router.compiled != nil {
+		return errCompiledRouterImmutable
+	}
 	if err := swagger.Validate(context.TODO()); err != nil {
 		return fmt.Errorf("Validating Swagger failed: %v", err)
 	}
@@ -104,13 +132,18 @@ func (router *Router) AddSwagger(swagger *openapi3.Swagger) error {
 	for path, pathItem := range swagger.Paths {
 		for method, operation := range pathItem.Operations() {
 			method = strings.ToUpper(method)
-			if err := root.Add(method+" "+path, &Route{
+			route := &Route{
 				Swagger:   swagger,
 				Path:      path,
 				PathItem:  pathItem,
 				Method:    method,
 				Operation: operation,
-			}, nil); err != nil {
+			}
+			route.parseIdempotencyKey()
+			if err := root.Add(method+" "+path, route, nil); err != nil {
+				return err
+			}
+			if err := router.addServerOverride(route); err != nil {
 				return err
 			}
 		}
@@ -120,6 +153,9 @@ func (router *Router) AddSwagger(swagger *openapi3.Swagger) error {
 
 // AddRoute adds a route in the router.
 func (router *Router) AddRoute(route *Route) error {
+	if router.compiled != nil {
+		return errCompiledRouterImmutable
+	}
 	method := route.Method
 	if method == "" {
 		return errors.New("Route is missing method")
@@ -141,33 +177,143 @@ func (router *Router) node() *pathpattern.Node {
 	return root
 }
 
+// RouteMatch is a route that matches a request.
+type RouteMatch struct {
+	Route *Route
+
+	// Server is the server that the request URL matches.
+	// It's nil when the route has no servers.
+	Server *openapi3.Server
+
+	// PathParams contains values of the variables of the path.
+	PathParams map[string]string
+
+	// ServerVariables contains values of the variables of the server.
+	ServerVariables map[string]string
+}
+
+// FindRoute returns the route that matches the request and values of both path and server variables.
 func (router *Router) FindRoute(method string, url *url.URL) (*Route, map[string]string, error) {
+	match, err := router.MatchRoute(method, url)
+	if err != nil {
+		return nil, nil, err
+	}
+	pathParams := match.PathParams
+	if len(match.ServerVariables) > 0 {
+		pathParams = make(map[string]string, len(match.ServerVariables)+len(match.PathParams))
+		for k, v := range match.ServerVariables {
+			pathParams[k] = v
+		}
+		for k, v := range match.PathParams {
+			pathParams[k] = v
+		}
+	}
+	return match.Route, pathParams, nil
+}
+
+// MatchRoute returns the route that matches the request.
+//
+// Servers of operations and path items take precedence over servers of the Swagger.
+// Values of server variables must be in the variable's enum if it's defined.
+func (router *Router) MatchRoute(method string, url *url.URL) (*RouteMatch, error) {
 	swagger := router.swagger
+	var rawURL string
+	if len(router.serverOverrides) > 0 || len(swagger.Servers) > 0 {
+		rawURL = url.String()
+		if i := strings.IndexByte(rawURL, '?'); i >= 0 {
+			rawURL = rawURL[:i]
+		}
+	}
+
+	// Routes that override servers
+	for _, override := range router.serverOverrides {
+		if override.route.Method != method {
+			continue
+		}
+		for _, server := range override.servers {
+			serverVariables, remainingPath, ok := router.matchServer(server, rawURL)
+			if !ok {
+				continue
+			}
+			node, paramValues := override.node.Match(remainingPath)
+			if node == nil {
+				continue
+			}
+			return &RouteMatch{
+				Route:           override.route,
+				Server:          server,
+				PathParams:      pathParamsOf(node, paramValues),
+				ServerVariables: serverVariables,
+			}, nil
+		}
+	}
 
-	// Get server
 	servers := swagger.Servers
-	var server *openapi3.Server
-	var remainingPath string
-	var pathParams map[string]string
 	if len(servers) == 0 {
-		remainingPath = url.Path
-	} else {
-		var paramValues []string
-		server, paramValues, remainingPath = servers.MatchURL(url)
-		if server == nil {
-			return nil, nil, &RouteError{
+		route, pathParams, err := router.findPath(nil, method, url.Path)
+		if err != nil {
+			return nil, err
+		}
+		if router.overridesServers(route) {
+			return nil, errDoesNotMatchAnyServer(swagger)
+		}
+		return &RouteMatch{
+			Route:      route,
+			PathParams: pathParams,
+		}, nil
+	}
+	var firstErr error
+	for _, server := range servers {
+		serverVariables, remainingPath, ok := router.matchServer(server, rawURL)
+		if !ok {
+			continue
+		}
+		route, pathParams, err := router.findPath(server, method, remainingPath)
+		if err == nil && router.overridesServers(route) {
+			err = &RouteError{
 				Route: Route{
 					Swagger: swagger,
+					Server:  server,
 				},
-				Reason: "Does not match any server",
+				Reason: "Path was not found",
 			}
 		}
-		pathParams = make(map[string]string, 8)
-		paramNames, _ := server.ParameterNames()
-		for i, value := range paramValues {
-			name := paramNames[i]
-			pathParams[name] = value
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		if pathParams == nil {
+			pathParams = make(map[string]string)
 		}
+		return &RouteMatch{
+			Route:           route,
+			Server:          server,
+			PathParams:      pathParams,
+			ServerVariables: serverVariables,
+		}, nil
+	}
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return nil, errDoesNotMatchAnyServer(swagger)
+}
+
+func errDoesNotMatchAnyServer(swagger *openapi3.Swagger) error {
+	return &RouteError{
+		Route: Route{
+			Swagger: swagger,
+		},
+		Reason: "Does not match any server",
+	}
+}
+
+// findPath returns the route of the path and values of the path's variables.
+func (router *Router) findPath(server *openapi3.Server, method string, remainingPath string) (*Route, map[string]string, error) {
+	swagger := router.swagger
+	if router.compiled != nil {
+		return router.compiled.findRoute(swagger, server, method, remainingPath)
 	}
 
 	// Get PathItem
@@ -199,9 +345,11 @@ func (router *Router) FindRoute(method string, url *url.URL) (*Route, map[string
 			Reason: "Path doesn't support the HTTP method",
 		}
 	}
-	if pathParams == nil {
-		pathParams = make(map[string]string, len(paramValues))
-	}
+	return route, pathParamsOf(node, paramValues), nil
+}
+
+func pathParamsOf(node *pathpattern.Node, paramValues []string) map[string]string {
+	pathParams := make(map[string]string, len(paramValues))
 	paramKeys := node.VariableNames
 	for i, value := range paramValues {
 		key := paramKeys[i]
@@ -210,5 +358,88 @@ func (router *Router) FindRoute(method string, url *url.URL) (*Route, map[string
 		}
 		pathParams[key] = value
 	}
-	return route, pathParams, nil
+	return pathParams
+}
+
+// matchServer returns values of the server's variables and the remaining path if the URL matches the server.
+func (router *Router) matchServer(server *openapi3.Server, rawURL string) (map[string]string, string, bool) {
+	paramValues, remainingPath, ok := server.MatchRawURL(rawURL)
+	if !ok {
+		return nil, "", false
+	}
+	var paramNames []string
+	if router.compiled != nil {
+		paramNames = router.compiled.serverParameterNames[server]
+	}
+	if paramNames == nil {
+		paramNames, _ = server.ParameterNames()
+	}
+	serverVariables := make(map[string]string, len(paramValues))
+	for i, value := range paramValues {
+		name := paramNames[i]
+		if variable := server.Variables[name]; variable != nil && len(variable.Enum) > 0 {
+			if !serverVariableEnumContains(variable.Enum, value) {
+				return nil, "", false
+			}
+		}
+		serverVariables[name] = value
+	}
+	return serverVariables, remainingPath, true
+}
+
+func serverVariableEnumContains(enum []interface{}, value string) bool {
+	for _, item := range enum {
+		if fmt.Sprint(item) == value {
+			return true
+		}
+	}
+	return false
+}
+
+// serverOverride is a route whose operation or path item has its own servers.
+type serverOverride struct {
+	route   *Route
+	servers openapi3.Servers
+	node    *pathpattern.Node
+}
+
+// addServerOverride remembers the route if it doesn't use servers of the Swagger.
+func (router *Router) addServerOverride(route *Route) error {
+	var servers openapi3.Servers
+	if v := route.Operation.Servers; v != nil && len(*v) > 0 {
+		servers = *v
+	} else if v := route.PathItem.Servers; len(v) > 0 {
+		servers = v
+	} else {
+		return nil
+	}
+	node := &pathpattern.Node{}
+	if err := node.Add(route.Path, route, nil); err != nil {
+		return err
+	}
+	router.serverOverrides = append(router.serverOverrides, &serverOverride{
+		route:   route,
+		servers: servers,
+		node:    node,
+	})
+	if router.overriddenRoutes == nil {
+		router.overriddenRoutes = make(map[*Route]bool)
+	}
+	router.overriddenRoutes[route] = true
+	if router.compiled != nil {
+		for _, server := range servers {
+			if _, ok := router.compiled.serverParameterNames[server]; !ok {
+				names, err := server.ParameterNames()
+				if err != nil {
+					return fmt.Errorf("Server '%s' is invalid: %v", server.URL, err)
+				}
+				router.compiled.serverParameterNames[server] = names
+			}
+		}
+	}
+	return nil
+}
+
+func (router *Router) overridesServers(route *Route) bool {
+	return router.overriddenRoutes[route]
 }
@@ -2,11 +2,13 @@ package openapi3filter_test
 
 import (
 	"net/http"
+	"net/url"
 	"sort"
 	"testing"
 
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/stretchr/testify/require"
 )
 
 func TestRouter(t *testing.T) {
@@ -118,3 +120,84 @@ func TestRouter(t *testing.T) {
 		"d1": "domain1",
 	})
 }
+
+func TestRouterServers(t *testing.T) {
+	listGET := &openapi3.Operation{}
+	listPOST := &openapi3.Operation{
+		Servers: &openapi3.Servers{
+			{URL: "https://upload.example.com/v1"},
+		},
+	}
+	legacyGET := &openapi3.Operation{}
+	swagger := &openapi3.Swagger{
+		Servers: openapi3.Servers{
+			{
+				URL: "https://{region}.example.com/{basePath}",
+				Variables: map[string]*openapi3.ServerVariable{
+					"region":   {Enum: []interface{}{"eu", "us"}, Default: "eu"},
+					"basePath": {Default: "v1"},
+				},
+			},
+		},
+		Paths: openapi3.Paths{
+			"/items/{id}": &openapi3.PathItem{
+				Get:  listGET,
+				Post: listPOST,
+			},
+			"/legacy": &openapi3.PathItem{
+				Servers: openapi3.Servers{
+					{URL: "http://legacy.example.com"},
+				},
+				Get: legacyGET,
+			},
+		},
+	}
+	routers := map[string]func() *openapi3filter.Router{
+		"NewRouter": func() *openapi3filter.Router {
+			return openapi3filter.NewRouter().WithSwagger(swagger)
+		},
+		"NewCompiledRouter": func() *openapi3filter.Router {
+			router, err := openapi3filter.NewCompiledRouter(swagger)
+			require.NoError(t, err)
+			return router
+		},
+	}
+	for name, newRouter := range routers {
+		t.Run(name, func(t *testing.T) {
+			router := newRouter()
+			match := func(method string, uri string) (*openapi3filter.RouteMatch, error) {
+				u, err := url.Parse(uri)
+				require.NoError(t, err)
+				return router.MatchRoute(method, u)
+			}
+
+			m, err := match("GET", "https://us.example.com/v2/items/7")
+			require.NoError(t, err)
+			require.True(t, m.Route.Operation == listGET)
+			require.True(t, m.Server == swagger.Servers[0])
+			require.Equal(t, map[string]string{"id": "7"}, m.PathParams)
+			require.Equal(t, map[string]string{"region": "us", "basePath": "v2"}, m.ServerVariables)
+
+			_, err = match("GET", "https://asia.example.com/v2/items/7")
+			require.Error(t, err)
+
+			m, err = match("POST", "https://upload.example.com/v1/items/7")
+			require.NoError(t, err)
+			require.True(t, m.Route.Operation == listPOST)
+			require.Equal(t, map[string]string{"id": "7"}, m.PathParams)
+			_, err = match("POST", "https://us.example.com/v1/items/7")
+			require.Error(t, err)
+
+			m, err = match("GET", "http://legacy.example.com/legacy")
+			require.NoError(t, err)
+			require.True(t, m.Route.Operation == legacyGET)
+			_, err = match("GET", "https://eu.example.com/v1/legacy")
+			require.Error(t, err)
+
+			route, pathParams, err := router.FindRoute("GET", &url.URL{Scheme: "https", Host: "eu.example.com", Path: "/v1/items/7"})
+			require.NoError(t, err)
+			require.True(t, route.Operation == listGET)
+			require.Equal(t, map[string]string{"id": "7", "region": "eu", "basePath": "v1"}, pathParams)
+		})
+	}
+}
@@ -0,0 +1,220 @@
+package openapi3filter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Scenario declares a request to an operation and expectations about the response.
+//
+// Example:
+//
+//	scenario := openapi3filter.NewScenario("create a user", "createUser").
+//		WithJSONBody(map[string]interface{}{"name": "alice"}).
+//		ExpectStatus(201).
+//		ExpectExample("created")
+type Scenario struct {
+	Name string
+	Step *WorkflowStep
+
+	expectedStatus  int
+	expectedSchema  *openapi3.Schema
+	expectedExample *string
+	err             error
+}
+
+// NewScenario returns a scenario that calls the operation with the ID.
+func NewScenario(name string, operationID string) *Scenario {
+	return &Scenario{
+		Name: name,
+		Step: &WorkflowStep{
+			OperationID: operationID,
+			Parameters:  make(map[string]string),
+		},
+	}
+}
+
+// WithParameter sets a value of a parameter, which may be qualified like "path.id".
+func (scenario *Scenario) WithParameter(key string, value string) *Scenario {
+	scenario.Step.Parameters[key] = value
+	return scenario
+}
+
+// WithBody sets the request body.
+func (scenario *Scenario) WithBody(contentType string, body []byte) *Scenario {
+	scenario.Step.ContentType = contentType
+	scenario.Step.Body = body
+	return scenario
+}
+
+// WithJSONBody sets the request body to the value encoded as JSON.
+func (scenario *Scenario) WithJSONBody(value interface{}) *Scenario {
+	body, err := json.Marshal(value)
+	if err != nil {
+		scenario.err = fmt.Errorf("Failed to encode request body: %v", err)
+	}
+	return scenario.WithBody("application/json", body)
+}
+
+// WithoutRequestValidation sends the request even if it doesn't conform to the document.
+func (scenario *Scenario) WithoutRequestValidation() *Scenario {
+	scenario.Step.SkipRequestValidation = true
+	return scenario
+}
+
+// ExpectStatus expects the response to have the status.
+func (scenario *Scenario) ExpectStatus(status int) *Scenario {
+	scenario.expectedStatus = status
+	return scenario
+}
+
+// ExpectSchema expects the response body to be JSON that matches the schema,
+// in addition to the schema in the document.
+func (scenario *Scenario) ExpectSchema(schema *openapi3.Schema) *Scenario {
+	scenario.expectedSchema = schema
+	return scenario
+}
+
+// ExpectExample expects the response body to be equal to the example of the response in the document.
+// An empty name refers to the field 'example' of the media type.
+func (scenario *Scenario) ExpectExample(name string) *Scenario {
+	scenario.expectedExample = &name
+	return scenario
+}
+
+// ScenarioReport is a report of executed scenarios.
+type ScenarioReport struct {
+	Results []*ScenarioResult
+}
+
+// Passed returns true if every scenario passed.
+func (report *ScenarioReport) Passed() bool {
+	return len(report.Failed()) == 0
+}
+
+// Failed returns results of scenarios that failed.
+func (report *ScenarioReport) Failed() []*ScenarioResult {
+	var results []*ScenarioResult
+	for _, result := range report.Results {
+		if !result.Passed() {
+			results = append(results, result)
+		}
+	}
+	return results
+}
+
+func (report *ScenarioReport) String() string {
+	var buf strings.Builder
+	for _, result := range report.Results {
+		if result.Passed() {
+			fmt.Fprintf(&buf, "PASS %s\n", result.Scenario.Name)
+			continue
+		}
+		fmt.Fprintf(&buf, "FAIL %s\n", result.Scenario.Name)
+		for _, failure := range result.Failures {
+			fmt.Fprintf(&buf, "  %v\n", failure)
+		}
+	}
+	return buf.String()
+}
+
+// ScenarioResult is the outcome of a scenario.
+type ScenarioResult struct {
+	Scenario *Scenario
+
+	// Result is nil if the request wasn't sent.
+	Result *WorkflowStepResult
+
+	// Failures are unmet expectations, including validation errors.
+	Failures []error
+}
+
+// Passed returns true if the scenario met all expectations.
+func (result *ScenarioResult) Passed() bool {
+	return len(result.Failures) == 0
+}
+
+// RunScenarios executes scenarios in order, validating every request and response.
+func (workflow *Workflow) RunScenarios(c context.Context, scenarios ...*Scenario) *ScenarioReport {
+	report := &ScenarioReport{}
+	for _, scenario := range scenarios {
+		report.Results = append(report.Results, workflow.runScenario(c, scenario))
+	}
+	return report
+}
+
+func (workflow *Workflow) runScenario(c context.Context, scenario *Scenario) *ScenarioResult {
+	result := &ScenarioResult{
+		Scenario: scenario,
+	}
+	if scenario.err != nil {
+		result.Failures = append(result.Failures, scenario.err)
+		return result
+	}
+	results, err := workflow.Run(c, []*WorkflowStep{scenario.Step})
+	if err != nil {
+		if workflowErr, ok := err.(*WorkflowError); ok {
+			err = workflowErr.Err
+		}
+		result.Failures = append(result.Failures, err)
+	}
+	if len(results) == 0 {
+		return result
+	}
+	stepResult := results[0]
+	result.Result = stepResult
+	if status := scenario.expectedStatus; status != 0 && status != stepResult.Status {
+		result.Failures = append(result.Failures, fmt.Errorf("Expected status %d, but got %d", status, stepResult.Status))
+	}
+	if scenario.expectedSchema == nil && scenario.expectedExample == nil {
+		return result
+	}
+	var body interface{}
+	if err := json.Unmarshal(stepResult.Body, &body); err != nil {
+		result.Failures = append(result.Failures, fmt.Errorf("Response body is not JSON: %v", err))
+		return result
+	}
+	if schema := scenario.expectedSchema; schema != nil {
+		if err := schema.VisitJSON(body); err != nil {
+			result.Failures = append(result.Failures, err)
+		}
+	}
+	if name := scenario.expectedExample; name != nil {
+		example, err := responseExample(stepResult, *name)
+		if err != nil {
+			result.Failures = append(result.Failures, err)
+		} else if !reflect.DeepEqual(example, body) {
+			result.Failures = append(result.Failures, fmt.Errorf("Response body doesn't equal to example '%s'", *name))
+		}
+	}
+	return result
+}
+
+// responseExample returns the example of the response in the document.
+func responseExample(result *WorkflowStepResult, name string) (interface{}, error) {
+	responses := result.Route.Operation.Responses
+	responseRef, _ := responses.ResolveStatus(result.Status)
+	if responseRef == nil || responseRef.Value == nil {
+		return nil, fmt.Errorf("Response with status %d is not declared", result.Status)
+	}
+	mediaType := responseRef.Value.Content.Get(parseMediaType(result.Header.Get("Content-Type")))
+	if mediaType == nil {
+		return nil, fmt.Errorf("Response with status %d doesn't declare content type %q", result.Status, result.Header.Get("Content-Type"))
+	}
+	if name == "" {
+		if mediaType.Example == nil {
+			return nil, fmt.Errorf("Response with status %d doesn't have an example", result.Status)
+		}
+		return mediaType.Example, nil
+	}
+	exampleRef := mediaType.Examples[name]
+	if exampleRef == nil || exampleRef.Value == nil {
+		return nil, fmt.Errorf("Response with status %d doesn't have example '%s'", result.Status, name)
+	}
+	return exampleRef.Value.Value, nil
+}
@@ -0,0 +1,102 @@
+package openapi3filter_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/stretchr/testify/require"
+)
+
+const scenarioSpec = `
+openapi: 3.0.0
+info:
+  title: Pets
+  version: "1.0"
+paths:
+  /pets/{id}:
+    get:
+      operationId: getPet
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: integer
+      responses:
+        "200":
+          description: pet
+          content:
+            application/json:
+              schema:
+                type: object
+                required: [name]
+                properties:
+                  name:
+                    type: string
+              examples:
+                rex:
+                  value:
+                    name: Rex
+        "404":
+          description: not found
+`
+
+func TestScenarios(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(scenarioSpec))
+	require.NoError(t, err)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/pets/1":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"name": "Rex"}`))
+		case "/pets/2":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"name": 2}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	workflow := &openapi3filter.Workflow{
+		Swagger: swagger,
+		Server:  server.URL,
+	}
+	report := workflow.RunScenarios(context.Background(),
+		openapi3filter.NewScenario("existing pet", "getPet").
+			WithParameter("id", "1").
+			ExpectStatus(http.StatusOK).
+			ExpectExample("rex"),
+		openapi3filter.NewScenario("missing pet", "getPet").
+			WithParameter("id", "3").
+			ExpectStatus(http.StatusNotFound),
+		openapi3filter.NewScenario("invalid response", "getPet").
+			WithParameter("id", "2").
+			ExpectStatus(http.StatusOK),
+		openapi3filter.NewScenario("unexpected status", "getPet").
+			WithParameter("id", "3").
+			ExpectStatus(http.StatusOK),
+		openapi3filter.NewScenario("invalid request", "getPet").
+			WithParameter("id", "x"),
+		openapi3filter.NewScenario("invalid request sent anyway", "getPet").
+			WithParameter("id", "x").
+			WithoutRequestValidation().
+			ExpectStatus(http.StatusNotFound),
+	)
+	require.False(t, report.Passed())
+	failed := report.Failed()
+	require.Len(t, failed, 3)
+	require.Equal(t, "invalid response", failed[0].Scenario.Name)
+	require.IsType(t, &openapi3filter.ResponseError{}, failed[0].Failures[0])
+	require.Equal(t, "unexpected status", failed[1].Scenario.Name)
+	require.EqualError(t, failed[1].Failures[0], "Expected status 200, but got 404")
+	require.Equal(t, "invalid request", failed[2].Scenario.Name)
+	require.Nil(t, failed[2].Result)
+	require.IsType(t, &openapi3filter.RequestError{}, failed[2].Failures[0])
+	require.Contains(t, report.String(), "PASS existing pet\n")
+	require.Contains(t, report.String(), "FAIL unexpected status\n  Expected status 200, but got 404\n")
+}
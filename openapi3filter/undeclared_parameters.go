@@ -0,0 +1,167 @@
+package openapi3filter
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// StandardRequestHeaders are headers that Options.RejectUndeclaredHeaders accepts
+// even when an operation doesn't declare them.
+var StandardRequestHeaders = []string{
+	"Accept",
+	"Accept-Charset",
+	"Accept-Encoding",
+	"Accept-Language",
+	"Authorization",
+	"Cache-Control",
+	"Connection",
+	"Content-Encoding",
+	"Content-Length",
+	"Content-Type",
+	"Cookie",
+	"Date",
+	"Expect",
+	"Forwarded",
+	"Host",
+	"If-Match",
+	"If-Modified-Since",
+	"If-None-Match",
+	"If-Range",
+	"If-Unmodified-Since",
+	"Origin",
+	"Pragma",
+	"Proxy-Authorization",
+	"Range",
+	"Referer",
+	"Te",
+	"Trailer",
+	"Transfer-Encoding",
+	"Upgrade",
+	"User-Agent",
+	"Via",
+	"X-Forwarded-For",
+	"X-Forwarded-Host",
+	"X-Forwarded-Proto",
+}
+
+// validateUndeclaredParameters rejects query parameters, headers, and cookies
+// that neither the operation nor the path item declares.
+func validateUndeclaredParameters(input *RequestValidationInput, options *Options) error {
+	route := input.Route
+	declared := map[string]map[string]bool{
+		openapi3.ParameterInQuery:  make(map[string]bool),
+		openapi3.ParameterInHeader: make(map[string]bool),
+		openapi3.ParameterInCookie: make(map[string]bool),
+	}
+	var prefixes []string
+	// Locations where any name may be a property of an exploded object
+	freeForm := make(map[string]bool)
+	for _, parameter := range routeParameters(route) {
+		names := declared[parameter.In]
+		if names == nil {
+			continue
+		}
+		names[parameterKey(parameter.In, parameter.Name)] = true
+		if parameter.In == openapi3.ParameterInHeader || parameter.Schema == nil || parameter.Schema.Value == nil {
+			continue
+		}
+		sm, err := parameter.SerializationMethod()
+		if err != nil {
+			return &RequestError{Input: input, Parameter: parameter, Err: err}
+		}
+		switch {
+		case sm.Style == openapi3.SerializationDeepObject && parameter.In == openapi3.ParameterInQuery:
+			prefixes = append(prefixes, parameter.Name+"[")
+		case sm.Style == openapi3.SerializationForm && sm.Explode && parameter.Schema != nil && parameter.Schema.Value.Type == "object":
+			// Properties of an exploded object are separate query parameters or cookies.
+			schema := parameter.Schema.Value
+			if schema.AdditionalProperties != nil || (schema.AdditionalPropertiesAllowed != nil && *schema.AdditionalPropertiesAllowed) {
+				freeForm[parameter.In] = true
+			}
+			for name := range schema.Properties {
+				names[name] = true
+			}
+		}
+	}
+	for _, name := range StandardRequestHeaders {
+		declared[openapi3.ParameterInHeader][parameterKey(openapi3.ParameterInHeader, name)] = true
+	}
+	if idempotencyKey, err := routeIdempotencyKey(route); err != nil {
+		return &RequestError{Input: input, Err: err}
+	} else if idempotencyKey != nil {
+		declared[openapi3.ParameterInHeader][parameterKey(openapi3.ParameterInHeader, idempotencyKey.Header)] = true
+	}
+	if swagger := route.Swagger; swagger != nil {
+		for _, schemeRef := range swagger.Components.SecuritySchemes {
+			if scheme := schemeRef.Value; scheme != nil && scheme.Type == "apiKey" && declared[scheme.In] != nil {
+				declared[scheme.In][parameterKey(scheme.In, scheme.Name)] = true
+			}
+		}
+	}
+
+	req := input.Request
+	if options.RejectUndeclaredQueryParameters && !freeForm[openapi3.ParameterInQuery] {
+		if name := firstUndeclared(queryNames(req), declared[openapi3.ParameterInQuery], prefixes); name != "" {
+			return &RequestError{Input: input, Reason: fmt.Sprintf("query parameter '%s' is not declared", name)}
+		}
+	}
+	if options.RejectUndeclaredHeaders {
+		names := make([]string, 0, len(req.Header))
+		for name := range req.Header {
+			names = append(names, name)
+		}
+		if name := firstUndeclared(names, declared[openapi3.ParameterInHeader], nil); name != "" {
+			return &RequestError{Input: input, Reason: fmt.Sprintf("header '%s' is not declared", name)}
+		}
+	}
+	if options.RejectUndeclaredCookies && !freeForm[openapi3.ParameterInCookie] {
+		var names []string
+		for _, cookie := range req.Cookies() {
+			names = append(names, cookie.Name)
+		}
+		if name := firstUndeclared(names, declared[openapi3.ParameterInCookie], nil); name != "" {
+			return &RequestError{Input: input, Reason: fmt.Sprintf("cookie '%s' is not declared", name)}
+		}
+	}
+	return nil
+}
+
+// parameterKey returns the name of a parameter, which is canonical for headers
+// because names of headers are case-insensitive.
+func parameterKey(in string, name string) string {
+	if in == openapi3.ParameterInHeader {
+		return http.CanonicalHeaderKey(name)
+	}
+	return name
+}
+
+func queryNames(req *http.Request) []string {
+	query := req.URL.Query()
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	return names
+}
+
+// firstUndeclared returns the first name in lexical order that isn't declared, or an empty string.
+func firstUndeclared(names []string, declared map[string]bool, prefixes []string) string {
+	sort.Strings(names)
+names:
+	for _, name := range names {
+		if declared[name] {
+			continue
+		}
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(name, prefix) {
+				continue names
+			}
+		}
+		return name
+	}
+	return ""
+}
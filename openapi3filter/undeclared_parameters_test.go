@@ -0,0 +1,138 @@
+package openapi3filter_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/stretchr/testify/require"
+)
+
+const undeclaredParametersSpec = `
+openapi: 3.0.0
+info:
+  title: Search
+  version: "1.0"
+components:
+  securitySchemes:
+    apiKey:
+      type: apiKey
+      in: query
+      name: api_key
+    session:
+      type: apiKey
+      in: cookie
+      name: session
+paths:
+  /items:
+    parameters:
+      - name: X-Tenant
+        in: header
+        schema:
+          type: string
+    get:
+      parameters:
+        - name: q
+          in: query
+          schema:
+            type: string
+        - name: filter
+          in: query
+          style: deepObject
+          schema:
+            type: object
+            properties:
+              color:
+                type: string
+        - name: page
+          in: query
+          schema:
+            type: object
+            properties:
+              offset:
+                type: integer
+                nullable: true
+              limit:
+                type: integer
+                nullable: true
+        - name: theme
+          in: cookie
+          schema:
+            type: string
+      responses:
+        "200":
+          description: items
+  /search:
+    get:
+      parameters:
+        - name: q
+          in: query
+          schema:
+            type: string
+        - name: counts
+          in: query
+          schema:
+            type: object
+            additionalProperties:
+              type: integer
+      responses:
+        "200":
+          description: results
+`
+
+func TestRejectUndeclaredParameters(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(undeclaredParametersSpec))
+	require.NoError(t, err)
+	router := openapi3filter.NewRouter().WithSwagger(swagger)
+	options := &openapi3filter.Options{
+		RejectUndeclaredQueryParameters: true,
+		RejectUndeclaredHeaders:         true,
+		RejectUndeclaredCookies:         true,
+	}
+
+	validate := func(req *http.Request) error {
+		route, pathParams, err := router.FindRoute(req.Method, req.URL)
+		require.NoError(t, err)
+		return openapi3filter.ValidateRequest(context.Background(), &openapi3filter.RequestValidationInput{
+			Request:    req,
+			PathParams: pathParams,
+			Route:      route,
+			Options:    options,
+		})
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/items?q=x&filter[color]=red&offset=10&limit=5&api_key=k", nil)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("x-tenant", "acme")
+	req.AddCookie(&http.Cookie{Name: "theme", Value: "dark"})
+	req.AddCookie(&http.Cookie{Name: "session", Value: "s"})
+	require.NoError(t, validate(req))
+
+	req = httptest.NewRequest(http.MethodGet, "/items?q=x&debug=1", nil)
+	err = validate(req)
+	require.IsType(t, &openapi3filter.RequestError{}, err)
+	require.EqualError(t, err, "query parameter 'debug' is not declared")
+
+	req = httptest.NewRequest(http.MethodGet, "/items", nil)
+	req.Header.Set("X-Debug", "1")
+	require.EqualError(t, validate(req), "header 'X-Debug' is not declared")
+
+	req = httptest.NewRequest(http.MethodGet, "/items", nil)
+	req.AddCookie(&http.Cookie{Name: "tracking", Value: "1"})
+	require.EqualError(t, validate(req), "cookie 'tracking' is not declared")
+
+	// Properties of an exploded free-form object may have any name.
+	req = httptest.NewRequest(http.MethodGet, "/search?q=pen&red=1&blue=2", nil)
+	require.NoError(t, validate(req))
+	req = httptest.NewRequest(http.MethodGet, "/search?q=pen&red=x", nil)
+	require.Error(t, validate(req))
+
+	// Extras are ignored by default.
+	options.RejectUndeclaredHeaders = false
+	req = httptest.NewRequest(http.MethodGet, "/items", nil)
+	req.Header.Set("X-Debug", "1")
+	require.NoError(t, validate(req))
+}
@@ -8,6 +8,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"sort"
+	"strings"
 
 	"github.com/getkin/kin-openapi/openapi3"
 )
@@ -15,11 +16,25 @@ import (
 // ErrInvalidRequired is an error that happens when a required value of a parameter or request's body is not defined.
 var ErrInvalidRequired = fmt.Errorf("must have a value")
 
+// ErrUnsupportedMediaType is an error that happens when the request's body doesn't have a media type
+// that the request body declares. Request errors with this cause have status 415 (Unsupported Media Type).
+var ErrUnsupportedMediaType = errors.New("media type is not supported")
+
+// ValidateRequest validates a request.
+//
+// The function returns the error of the context if the context is done before validation completes.
 func ValidateRequest(c context.Context, input *RequestValidationInput) error {
 	options := input.Options
 	if options == nil {
 		options = DefaultOptions
 	}
+	return options.handleValidationError(c, validateRequest(options.contextWithRegexEngine(c), input, options))
+}
+
+func validateRequest(c context.Context, input *RequestValidationInput, options *Options) error {
+	if err := c.Err(); err != nil {
+		return err
+	}
 	route := input.Route
 	if route == nil {
 		return errors.New("invalid route")
@@ -28,25 +43,33 @@ func ValidateRequest(c context.Context, input *RequestValidationInput) error {
 	if operation == nil {
 		return errRouteMissingOperation
 	}
-	operationParameters := operation.Parameters
-	pathItemParameters := route.PathItem.Parameters
+	// Parameters of the operation and the path item
+	for _, parameter := range routeParameters(route) {
+		if options.excludesParameter(parameter) {
+			continue
+		}
+		if err := ValidateParameter(c, input, parameter); err != nil {
+			return err
+		}
+	}
 
-	// For each parameter of the PathItem
-	for _, parameterRef := range pathItemParameters {
-		parameter := parameterRef.Value
-		if operationParameters != nil {
-			if override := operationParameters.GetByInAndName(parameter.In, parameter.Name); override != nil {
-				continue
-			}
-			if err := ValidateParameter(c, input, parameter); err != nil {
-				return err
-			}
+	// Undeclared parameters
+	if options.RejectUndeclaredQueryParameters || options.RejectUndeclaredHeaders || options.RejectUndeclaredCookies {
+		if err := validateUndeclaredParameters(input, options); err != nil {
+			return err
+		}
+	}
+
+	// Idempotency key
+	if options.ValidateIdempotencyKeys && !options.ExcludeHeaderParameters {
+		if err := validateIdempotencyKey(c, input, true); err != nil {
+			return err
 		}
 	}
 
-	// For each parameter of the Operation
-	for _, parameter := range operationParameters {
-		if err := ValidateParameter(c, input, parameter.Value); err != nil {
+	// Conditional requests
+	if options.ValidateConditionalHeaders {
+		if err := validateConditionalRequestHeaders(input); err != nil {
 			return err
 		}
 	}
@@ -61,7 +84,7 @@ func ValidateRequest(c context.Context, input *RequestValidationInput) error {
 
 	// Security
 	security := operation.Security
-	if security != nil {
+	if security != nil && !options.ExcludeSecurity {
 		if err := ValidateSecurityRequirements(c, input, *security); err != nil {
 			return err
 		}
@@ -72,29 +95,73 @@ func ValidateRequest(c context.Context, input *RequestValidationInput) error {
 // ValidateParameter validates a parameter's value by JSON schema.
 // The function returns RequestError with a ParseError cause when unable to parse a value.
 // The function returns RequestError with ErrInvalidRequired cause when a value of a required parameter is not defined.
+// An empty value of a parameter is a ParseError of kind KindEmptyValue, unless the schema is nullable or a string,
+// or the parameter allows empty values.
 // The function returns RequestError with a openapi3.SchemaError cause when a value is invalid by JSON schema.
 func ValidateParameter(c context.Context, input *RequestValidationInput, parameter *openapi3.Parameter) error {
+	_, err := decodeAndValidateParameter(c, input, parameter)
+	return err
+}
+
+// decodeAndValidateParameter returns the decoded value of a parameter if the value is valid.
+func decodeAndValidateParameter(c context.Context, input *RequestValidationInput, parameter *openapi3.Parameter) (interface{}, error) {
+	options := input.Options
+	if options == nil {
+		options = DefaultOptions
+	}
+	if hook := options.BeforeParameterDecode; hook != nil {
+		if err := hook(c, input, parameter); err != nil {
+			return nil, &RequestError{Input: input, Parameter: parameter, Err: err}
+		}
+	}
 	value, err := decodeParameter(parameter, input)
 	if err != nil {
-		return &RequestError{Input: input, Parameter: parameter, Err: err}
+		return nil, &RequestError{Input: input, Parameter: parameter, Err: err}
 	}
 
 	// Validate a parameter's value.
 	if value == nil {
-		if parameter.Required {
-			return &RequestError{Input: input, Parameter: parameter, Reason: "must have a value", Err: ErrInvalidRequired}
+		// An empty value of a nullable parameter is null, which is a value of a required parameter.
+		if parameter.Required && !(isNullable(parameter) && hasParameterValue(input, parameter)) {
+			return nil, &RequestError{Input: input, Parameter: parameter, Reason: "must have a value", Err: ErrInvalidRequired}
 		}
-		return nil
+		return nil, nil
 	}
-	schema := parameter.Schema.Value
-	if schema == nil {
+	schemaRef := parameterSchema(parameter)
+	if schemaRef == nil || schemaRef.Value == nil {
 		// A parameter's schema is not defined so skip validation of a parameter's value.
-		return nil
+		input.setParameterValue(parameter, value)
+		return value, nil
 	}
-	if err = schema.VisitJSON(value); err != nil {
-		return &RequestError{Input: input, Parameter: parameter, Err: err}
+	if err = schemaRef.Value.VisitJSONContext(c, value); err != nil {
+		if err := c.Err(); err != nil {
+			return nil, err
+		}
+		return nil, &RequestError{Input: input, Parameter: parameter, Err: err}
 	}
-	return nil
+	input.setParameterValue(parameter, value)
+	return value, nil
+}
+
+func isNullable(parameter *openapi3.Parameter) bool {
+	schema := parameterSchema(parameter)
+	return schema != nil && schema.Value != nil && schema.Value.Nullable
+}
+
+// hasParameterValue returns true if the request contains the parameter, even if its value is empty.
+func hasParameterValue(input *RequestValidationInput, parameter *openapi3.Parameter) bool {
+	switch parameter.In {
+	case openapi3.ParameterInQuery:
+		_, ok := input.GetQueryParams()[parameter.Name]
+		return ok
+	case openapi3.ParameterInHeader:
+		_, ok := input.Request.Header[http.CanonicalHeaderKey(parameter.Name)]
+		return ok
+	case openapi3.ParameterInCookie:
+		_, err := input.Request.Cookie(parameter.Name)
+		return err == nil
+	}
+	return false
 }
 
 // ValidateRequestBody validates data of a request's body.
@@ -107,7 +174,7 @@ func ValidateRequestBody(c context.Context, input *RequestValidationInput, reque
 		data []byte
 	)
 
-	if req.Body != http.NoBody {
+	if req.Body != nil && req.Body != http.NoBody {
 		defer req.Body.Close()
 		var err error
 		if data, err = ioutil.ReadAll(req.Body); err != nil {
@@ -135,22 +202,33 @@ func ValidateRequestBody(c context.Context, input *RequestValidationInput, reque
 		return nil
 	}
 
+	options := input.Options
+	if options == nil {
+		options = DefaultOptions
+	}
 	inputMIME := req.Header.Get("Content-Type")
-	mediaType := parseMediaType(inputMIME)
+	mediaType := requestMediaType(inputMIME, content)
+	contentType := findMediaType(content, mediaType)
+	if options.ExcludeContentType && contentType == nil {
+		// The body can't be validated without a schema of its content type.
+		return nil
+	}
 	if mediaType == "" {
 		return &RequestError{
 			Input:       input,
 			RequestBody: requestBody,
+			Status:      http.StatusUnsupportedMediaType,
 			Reason:      "content type of request body is missed",
+			Err:         ErrUnsupportedMediaType,
 		}
 	}
-
-	contentType := requestBody.Content[mediaType]
 	if contentType == nil {
 		return &RequestError{
 			Input:       input,
 			RequestBody: requestBody,
+			Status:      http.StatusUnsupportedMediaType,
 			Reason:      fmt.Sprintf("header 'Content-Type' has unexpected value: %q", inputMIME),
+			Err:         ErrUnsupportedMediaType,
 		}
 	}
 
@@ -160,18 +238,30 @@ func ValidateRequestBody(c context.Context, input *RequestValidationInput, reque
 		return nil
 	}
 
-	value, err := decodeBody(data, mediaType)
+	value, err := decodeRequestBody(c, input, requestBody, contentType, inputMIME, mediaType, data)
 	if err != nil {
-		return &RequestError{
-			Input:       input,
-			RequestBody: requestBody,
-			Reason:      "failed to decode request body",
-			Err:         err,
+		return err
+	}
+	if hook := options.AfterBodyDecode; hook != nil {
+		bodyInput := &BodyDecodeInput{
+			RequestValidationInput: input,
+			MediaType:              mediaType,
+			Schema:                 schemaRef,
+		}
+		if value, err = hook(c, bodyInput, value); err != nil {
+			return &RequestError{
+				Input:       input,
+				RequestBody: requestBody,
+				Err:         err,
+			}
 		}
 	}
 
 	// Validate JSON with the schema
-	if err := schemaRef.Value.VisitJSON(value); err != nil {
+	if err := visitBodyValue(c, mediaType, schemaRef.Value, value); err != nil {
+		if err := c.Err(); err != nil {
+			return err
+		}
 		return &RequestError{
 			Input:       input,
 			RequestBody: requestBody,
@@ -182,6 +272,55 @@ func ValidateRequestBody(c context.Context, input *RequestValidationInput, reque
 	return nil
 }
 
+// requestMediaType returns the media type of a request body with the header Content-Type.
+// Without the header, the body has the only declared media type.
+func requestMediaType(inputMIME string, content openapi3.Content) string {
+	mediaType := parseMediaType(inputMIME)
+	if mediaType == "" && len(content) == 1 {
+		for declared := range content {
+			if !strings.Contains(declared, "*") {
+				mediaType = parseMediaType(declared)
+			}
+		}
+	}
+	return mediaType
+}
+
+// decodeRequestBody decodes data of a request body with the declared content type, which may be nil.
+// Multipart bodies are decoded part by part, and records of CSV bodies are coerced to the schema.
+func decodeRequestBody(c context.Context, input *RequestValidationInput, requestBody *openapi3.RequestBody,
+	contentType *openapi3.MediaType, inputMIME string, mediaType string, data []byte) (interface{}, error) {
+	options := input.Options
+	if options == nil {
+		options = DefaultOptions
+	}
+	var value interface{}
+	var err error
+	if _, ok := options.BodyDecoders.Get(mediaType); !ok && mediaType == "multipart/form-data" && contentType != nil {
+		value, err = decodeMultipartBody(c, input, requestBody, contentType, inputMIME, data)
+	} else {
+		value, err = decodeBodyWith(c, options, data, mediaType)
+	}
+	if err == nil && contentType != nil && contentType.Schema != nil {
+		value, err = coerceBodyRecords(mediaType, value, contentType.Schema.Value)
+	}
+	if err != nil {
+		if err := c.Err(); err != nil {
+			return nil, err
+		}
+		if reqErr, ok := err.(*RequestError); ok {
+			return nil, reqErr
+		}
+		return nil, &RequestError{
+			Input:       input,
+			RequestBody: requestBody,
+			Reason:      "failed to decode request body",
+			Err:         err,
+		}
+	}
+	return value, nil
+}
+
 // ValidateSecurityRequirements validates a multiple OpenAPI 3 security requirements.
 // Returns nil if one of them inputed.
 // Otherwise returns an error describing the security failures.
@@ -220,14 +359,21 @@ func ValidateSecurityRequirements(c context.Context, input *RequestValidationInp
 		}()
 	}
 
-	// Wait for all
+	// Wait for all, unless the context is done.
+	// The channel is buffered, so goroutines that are still running don't block.
 	for i := 0; i < len(srs); i++ {
-		ok := <-doneChan
-		if ok {
-			close(doneChan)
-			return nil
+		select {
+		case ok := <-doneChan:
+			if ok {
+				return nil
+			}
+		case <-c.Done():
+			return c.Err()
 		}
 	}
+	if err := c.Err(); err != nil {
+		return err
+	}
 	return &SecurityRequirementsError{
 		SecurityRequirements: srs,
 		Errors:               errs,
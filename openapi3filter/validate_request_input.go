@@ -3,6 +3,8 @@ package openapi3filter
 import (
 	"net/http"
 	"net/url"
+
+	"github.com/getkin/kin-openapi/openapi3"
 )
 
 type RequestValidationInput struct {
@@ -11,6 +13,15 @@ type RequestValidationInput struct {
 	QueryParams url.Values
 	Route       *Route
 	Options     *Options
+
+	// IdempotencyKey is set by ValidateIdempotencyKey.
+	IdempotencyKey string
+
+	// ParameterValues are decoded values of valid parameters, set by ValidateParameter.
+	ParameterValues map[*openapi3.Parameter]interface{}
+
+	// Parts are parts of a multipart/form-data body, set by ValidateRequestBody.
+	Parts []*MultipartPart
 }
 
 func (input *RequestValidationInput) GetQueryParams() url.Values {
@@ -21,3 +32,10 @@ func (input *RequestValidationInput) GetQueryParams() url.Values {
 	}
 	return q
 }
+
+func (input *RequestValidationInput) setParameterValue(parameter *openapi3.Parameter, value interface{}) {
+	if input.ParameterValues == nil {
+		input.ParameterValues = make(map[*openapi3.Parameter]interface{})
+	}
+	input.ParameterValues[parameter] = value
+}
@@ -6,9 +6,30 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"sort"
+
+	"github.com/getkin/kin-openapi/openapi3"
 )
 
+// ValidateResponse validates a response.
+//
+// The function returns the error of the context if the context is done before validation completes.
 func ValidateResponse(c context.Context, input *ResponseValidationInput) error {
+	options := input.Options
+	if options == nil {
+		options = DefaultOptions
+	}
+	err := options.handleValidationError(c, validateResponse(options.contextWithRegexEngine(c), input, options))
+	if coverage := options.Coverage; coverage != nil && c.Err() == nil {
+		coverage.recordResponse(input, err == nil)
+	}
+	return err
+}
+
+func validateResponse(c context.Context, input *ResponseValidationInput, options *Options) error {
+	if err := c.Err(); err != nil {
+		return err
+	}
 	req := input.RequestValidationInput.Request
 	switch req.Method {
 	case "HEAD":
@@ -22,6 +43,11 @@ func ValidateResponse(c context.Context, input *ResponseValidationInput) error {
 			Err:    fmt.Errorf("Status %d", status),
 		}
 	}
+	if options.ValidateConditionalHeaders {
+		if err := validateETagHeader(input); err != nil {
+			return err
+		}
+	}
 
 	// These status codes will never be validated.
 	// TODO: The list is probably missing some.
@@ -33,20 +59,13 @@ func ValidateResponse(c context.Context, input *ResponseValidationInput) error {
 		return nil
 	}
 	route := input.RequestValidationInput.Route
-	options := input.Options
-	if options == nil {
-		options = DefaultOptions
-	}
 
 	// Find input for the current status
 	responses := route.Operation.Responses
 	if len(responses) == 0 {
 		return nil
 	}
-	responseRef := responses.Get(status) // Response
-	if responseRef == nil {
-		responseRef = responses.Default() // Default input
-	}
+	responseRef, _ := responses.ResolveStatus(status)
 	if responseRef == nil {
 		// By default, status that is not documented is allowed.
 		if !options.IncludeResponseStatus {
@@ -59,6 +78,24 @@ func ValidateResponse(c context.Context, input *ResponseValidationInput) error {
 	if response == nil {
 		return &ResponseError{Input: input, Reason: "response has not been resolved"}
 	}
+	if options.StrictContentEncoding {
+		if err := validateContentEncoding(c, input, response); err != nil {
+			return err
+		}
+	}
+	if options.StrictCacheHeaders {
+		if err := validateCacheControlHeader(input, response); err != nil {
+			return err
+		}
+		if err := validateExpiresHeader(input, response); err != nil {
+			return err
+		}
+	}
+	if options.IncludeResponseHeaders {
+		if err := validateResponseHeaders(c, input, response); err != nil {
+			return err
+		}
+	}
 
 	if options.ExcludeResponseBody {
 		// A user turned off validation of a response's body.
@@ -73,6 +110,10 @@ func ValidateResponse(c context.Context, input *ResponseValidationInput) error {
 
 	inputMIME := input.Header.Get("Content-Type")
 	mediaType := parseMediaType(inputMIME)
+	if options.ExcludeContentType && content[mediaType] == nil {
+		// The body can't be validated without a schema of its content type.
+		return nil
+	}
 	if mediaType == "" {
 		return &ResponseError{
 			Input:  input,
@@ -118,17 +159,40 @@ func ValidateResponse(c context.Context, input *ResponseValidationInput) error {
 	// Put the data back into the response.
 	input.SetBodyBytes(data)
 
-	value, err := decodeBody(data, mediaType)
+	value, err := decodeBodyWith(c, options, data, mediaType)
+	if err == nil {
+		value, err = coerceBodyRecords(mediaType, value, schema.Value)
+	}
 	if err != nil {
+		if err := c.Err(); err != nil {
+			return err
+		}
 		return &ResponseError{
 			Input:  input,
 			Reason: "failed to decode response body",
 			Err:    err,
 		}
 	}
+	if hook := options.AfterBodyDecode; hook != nil {
+		bodyInput := &BodyDecodeInput{
+			RequestValidationInput:  input.RequestValidationInput,
+			ResponseValidationInput: input,
+			MediaType:               mediaType,
+			Schema:                  schema,
+		}
+		if value, err = hook(c, bodyInput, value); err != nil {
+			return &ResponseError{
+				Input: input,
+				Err:   err,
+			}
+		}
+	}
 
 	// Validate data with the schema.
-	if err := schema.Value.VisitJSON(value); err != nil {
+	if err := visitBodyValue(c, mediaType, schema.Value, value); err != nil {
+		if err := c.Err(); err != nil {
+			return err
+		}
 		return &ResponseError{
 			Input:  input,
 			Reason: "response body doesn't match the schema",
@@ -137,3 +201,53 @@ func ValidateResponse(c context.Context, input *ResponseValidationInput) error {
 	}
 	return nil
 }
+
+// validateResponseHeaders validates values of headers that the response declares.
+// The header Content-Type is described by the content of the response,
+// and the header Content-Encoding is validated with the option StrictContentEncoding, so they're ignored.
+func validateResponseHeaders(c context.Context, input *ResponseValidationInput, response *openapi3.Response) error {
+	for _, name := range responseHeaderNames(response) {
+		if err := validateResponseHeader(c, input, name, response.Headers[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// responseHeaderNames returns sorted names of headers that validateResponseHeader validates.
+func responseHeaderNames(response *openapi3.Response) []string {
+	names := make([]string, 0, len(response.Headers))
+	for name := range response.Headers {
+		switch http.CanonicalHeaderKey(name) {
+		case "Content-Type", "Content-Encoding":
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func validateResponseHeader(c context.Context, input *ResponseValidationInput, name string, headerRef *openapi3.HeaderRef) error {
+	if headerRef == nil || headerRef.Value == nil || headerRef.Value.Schema == nil || headerRef.Value.Schema.Value == nil {
+		return nil
+	}
+	// Headers of the response are decoded like header parameters of a request.
+	headerInput := &RequestValidationInput{Request: &http.Request{Header: input.Header}}
+	schema := headerRef.Value.Schema
+	parameter := &openapi3.Parameter{Name: name, In: openapi3.ParameterInHeader, Schema: schema}
+	value, err := decodeParameter(parameter, headerInput)
+	if err != nil {
+		return &ResponseError{Input: input, Reason: fmt.Sprintf("response header '%s' is invalid", name), Err: err}
+	}
+	if value == nil {
+		return nil
+	}
+	if err := schema.Value.VisitJSONContext(c, value); err != nil {
+		if err := c.Err(); err != nil {
+			return err
+		}
+		return &ResponseError{Input: input, Reason: fmt.Sprintf("response header '%s' doesn't match the schema", name), Err: err}
+	}
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"io"
 	"io/ioutil"
 	"net/http"
@@ -280,3 +281,202 @@ func toJSON(v interface{}) io.Reader {
 	}
 	return bytes.NewReader(data)
 }
+
+func TestValidateRequestBodyContentType(t *testing.T) {
+	jsonOnly := openapi3.NewRequestBody().WithJSONSchema(openapi3.NewObjectSchema())
+	jsonAndOther := openapi3.NewRequestBody().WithContent(openapi3.Content{
+		"application/json": openapi3.NewMediaType().WithSchema(openapi3.NewObjectSchema()),
+		"application/*":    openapi3.NewMediaType().WithSchema(openapi3.NewObjectSchema()),
+	})
+	required := openapi3.NewRequestBody().WithRequired(true).WithJSONSchema(openapi3.NewObjectSchema())
+	for _, test := range []struct {
+		requestBody *openapi3.RequestBody
+		contentType string
+		body        string
+		status      int
+	}{
+		{jsonOnly, "", "", 0},
+		{jsonOnly, "application/json", "", 0},
+		{jsonOnly, "", `{}`, 0},
+		{jsonOnly, "", `[]`, http.StatusBadRequest},
+		{jsonOnly, "Application/JSON; charset=utf-8", `{}`, 0},
+		{jsonOnly, "application/xml", `<a/>`, http.StatusUnsupportedMediaType},
+		{jsonAndOther, "application/x-ndjson", "{}\n{}\n", 0},
+		{jsonAndOther, "", `{}`, http.StatusUnsupportedMediaType},
+		{required, "application/json", "", http.StatusBadRequest},
+	} {
+		req, err := http.NewRequest(http.MethodPost, "/items", nil)
+		require.NoError(t, err)
+		if test.body != "" {
+			req.Body = ioutil.NopCloser(strings.NewReader(test.body))
+		}
+		if test.contentType != "" {
+			req.Header.Set("Content-Type", test.contentType)
+		}
+		input := &openapi3filter.RequestValidationInput{Request: req}
+		err = openapi3filter.ValidateRequestBody(context.Background(), input, test.requestBody)
+		if test.status == 0 {
+			require.NoError(t, err, "%q %q", test.contentType, test.body)
+			continue
+		}
+		require.IsType(t, &openapi3filter.RequestError{}, err)
+		require.Equal(t, test.status, err.(*openapi3filter.RequestError).HTTPStatus(), "%q %q", test.contentType, test.body)
+		require.Equal(t, test.status == http.StatusUnsupportedMediaType, errors.Is(err, openapi3filter.ErrUnsupportedMediaType))
+	}
+}
+
+func TestRequestErrorFieldNames(t *testing.T) {
+	openapi3.ErrorMessages = openapi3.NewMessageCatalog().WithFieldNames(strings.ToUpper)
+	defer func() { openapi3.ErrorMessages = nil }()
+
+	err := &openapi3filter.RequestError{
+		Parameter: openapi3.NewQueryParameter("page_size"),
+		Err:       &openapi3filter.ParseError{Path: []interface{}{"limit", 1}, Reason: "invalid"},
+	}
+	require.Equal(t, "Parameter 'PAGE_SIZE' in query has an error: path [LIMIT 1]: invalid", err.Error())
+}
+
+func TestValidateParameterEmptyValue(t *testing.T) {
+	nullable := openapi3.NewIntegerSchema()
+	nullable.Nullable = true
+	for _, test := range []struct {
+		parameter *openapi3.Parameter
+		query     string
+		err       error
+	}{
+		{openapi3.NewQueryParameter("limit").WithSchema(openapi3.NewIntegerSchema()), "limit=", openapi3filter.KindEmptyValue},
+		{openapi3.NewQueryParameter("limit").WithSchema(openapi3.NewIntegerSchema()), "", nil},
+		{openapi3.NewQueryParameter("limit").WithSchema(openapi3.NewIntegerSchema()).WithRequired(true), "", openapi3filter.ErrInvalidRequired},
+		{openapi3.NewQueryParameter("limit").WithSchema(nullable).WithRequired(true), "limit=", nil},
+		{openapi3.NewQueryParameter("limit").WithSchema(nullable).WithRequired(true), "", openapi3filter.ErrInvalidRequired},
+		{openapi3.NewQueryParameter("limit").WithSchema(openapi3.NewIntegerSchema().WithEnum(10, 20)), "limit=30", openapi3filter.KindInvalidEnum},
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/items?"+test.query, nil)
+		input := &openapi3filter.RequestValidationInput{Request: req}
+		err := openapi3filter.ValidateParameter(context.Background(), input, test.parameter)
+		if test.err == nil {
+			require.NoError(t, err, test.query)
+			continue
+		}
+		require.IsType(t, &openapi3filter.RequestError{}, err, test.query)
+		require.True(t, errors.Is(err, test.err), "%s: %v", test.query, err)
+	}
+}
+
+func TestValidateArrayParameter(t *testing.T) {
+	ids := openapi3.NewArraySchema().WithItems(openapi3.NewIntegerSchema().WithMin(1)).WithMinItems(1).WithMaxItems(3).WithUniqueItems(true)
+	filter := openapi3.NewArraySchema().WithItems(openapi3.NewObjectSchema().
+		WithProperty("id", openapi3.NewStringSchema().WithMinLength(2)))
+	for _, test := range []struct {
+		parameter *openapi3.Parameter
+		query     string
+		err       string
+	}{
+		{openapi3.NewQueryParameter("ids").WithSchema(ids), "ids=1&ids=2", ""},
+		{openapi3.NewQueryParameter("ids").WithSchema(ids), "ids=1&ids=2&ids=3&ids=4", "Maximum number of items is 3"},
+		{openapi3.NewQueryParameter("ids").WithSchema(ids), "ids=1&ids=1", "Duplicate items found"},
+		{openapi3.NewQueryParameter("ids").WithSchema(ids), "ids=1&ids=0", `Error at "/1":Number must be at least 1`},
+		{&openapi3.Parameter{Name: "filter", In: "query", Content: openapi3.NewContentWithJSONSchema(filter)}, `filter=[{"id":"ab"}]`, ""},
+		{&openapi3.Parameter{Name: "filter", In: "query", Content: openapi3.NewContentWithJSONSchema(filter)}, `filter=[{"id":"ab"},{"id":"a"}]`, `Error at "/1/id":Minimum string length is 2`},
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/items?"+test.query, nil)
+		input := &openapi3filter.RequestValidationInput{Request: req}
+		err := openapi3filter.ValidateParameter(context.Background(), input, test.parameter)
+		if test.err == "" {
+			require.NoError(t, err, test.query)
+			continue
+		}
+		var schemaError *openapi3.SchemaError
+		require.True(t, errors.As(err, &schemaError), "%s: %v", test.query, err)
+		require.Equal(t, test.err, strings.SplitN(schemaError.Error(), "\n", 2)[0], test.query)
+	}
+}
+
+func TestParseErrorMessage(t *testing.T) {
+	catalog := openapi3.NewMessageCatalog().WithMessage("invalid integer", "'{{.Value}}' is not a whole number")
+	parameter := openapi3.NewQueryParameter("limit").WithSchema(openapi3.NewIntegerSchema())
+	input := &openapi3filter.RequestValidationInput{Request: httptest.NewRequest(http.MethodGet, "/items?limit=ten", nil)}
+	err := openapi3filter.ValidateParameter(context.Background(), input, parameter)
+	var parseError *openapi3filter.ParseError
+	require.True(t, errors.As(err, &parseError))
+	require.Equal(t, "'ten' is not a whole number", parseError.Message(catalog))
+	require.Equal(t, "an invalid interger", parseError.Message(nil))
+
+	openapi3.ErrorMessages = catalog
+	defer func() { openapi3.ErrorMessages = nil }()
+	require.Equal(t, `'ten' is not a whole number: strconv.ParseFloat: parsing "ten": invalid syntax`, parseError.Error())
+}
+
+func TestValidateResponseStatusRange(t *testing.T) {
+	operation := openapi3.NewOperation()
+	operation.Responses = openapi3.Responses{
+		"200": &openapi3.ResponseRef{Value: openapi3.NewResponse().WithJSONSchema(openapi3.NewStringSchema())},
+		"2XX": &openapi3.ResponseRef{Value: openapi3.NewResponse().WithJSONSchema(openapi3.NewIntegerSchema())},
+	}
+	route := &openapi3filter.Route{Method: http.MethodGet, Path: "/items", Operation: operation}
+	validate := func(status int, body string) error {
+		input := &openapi3filter.ResponseValidationInput{
+			RequestValidationInput: &openapi3filter.RequestValidationInput{
+				Request: httptest.NewRequest(http.MethodGet, "/items", nil),
+				Route:   route,
+			},
+			Status: status,
+			Header: http.Header{"Content-Type": {"application/json"}},
+		}
+		return openapi3filter.ValidateResponse(context.Background(), input.SetBodyBytes([]byte(body)))
+	}
+	require.NoError(t, validate(200, `"ok"`))
+	require.NoError(t, validate(201, `1`))
+	require.Error(t, validate(201, `"ok"`))
+}
+
+func TestValidateRequestPathItemParameters(t *testing.T) {
+	pathItem := &openapi3.PathItem{Parameters: openapi3.Parameters{
+		{Value: openapi3.NewQueryParameter("limit").WithSchema(openapi3.NewIntegerSchema())},
+		{Value: openapi3.NewQueryParameter("sort").WithSchema(openapi3.NewStringSchema().WithEnum("asc", "desc"))},
+	}}
+	operation := openapi3.NewOperation()
+	operation.Parameters = openapi3.Parameters{
+		{Value: openapi3.NewQueryParameter("sort").WithSchema(openapi3.NewStringSchema())},
+	}
+	route := &openapi3filter.Route{Method: http.MethodGet, Path: "/items", PathItem: pathItem, Operation: operation}
+	require.Equal(t, operation.Parameters[0], route.Parameters()[1])
+	validate := func(query string) error {
+		input := &openapi3filter.RequestValidationInput{
+			Request: httptest.NewRequest(http.MethodGet, "/items?"+query, nil),
+			Route:   route,
+		}
+		return openapi3filter.ValidateRequest(context.Background(), input)
+	}
+	require.NoError(t, validate("limit=10&sort=name"))
+	require.Error(t, validate("limit=ten"))
+
+	// Without parameters of the operation, parameters of the path item apply too.
+	operation.Parameters = nil
+	require.Error(t, validate("limit=ten"))
+	require.Error(t, validate("sort=name"))
+}
+
+func BenchmarkValidateRequestBody(b *testing.B) {
+	item := openapi3.NewObjectSchema().
+		WithProperty("id", openapi3.NewIntegerSchema()).
+		WithProperty("code", openapi3.NewStringSchema().WithPattern("^[A-Z]{3}-[0-9]{4}$"))
+	requestBody := openapi3.NewRequestBody().WithJSONSchema(openapi3.NewArraySchema().WithItems(item))
+	items := make([]interface{}, 0, 20)
+	for i := 0; i < 20; i++ {
+		items = append(items, map[string]interface{}{"id": i, "code": "ABC-1234"})
+	}
+	data, err := json.Marshal(items)
+	require.NoError(b, err)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		req := httptest.NewRequest(http.MethodPost, "/items", bytes.NewReader(data))
+		req.Header.Set("Content-Type", "application/json")
+		input := &openapi3filter.RequestValidationInput{Request: req}
+		if err := openapi3filter.ValidateRequestBody(context.Background(), input, requestBody); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,344 @@
+package openapi3filter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Workflow executes a sequence of operations, where inputs of each step are derived from
+// the previous step with runtime expressions of a link.
+// Every request and response is validated against the document.
+//
+// Workflow is experimental and intended for spec-driven smoke tests.
+type Workflow struct {
+	Swagger *openapi3.Swagger
+
+	// Server is the base URL that paths of operations are appended to.
+	// If empty, the base URL of the server selected by ServerSelection is used.
+	Server string
+
+	// ServerSelection selects a server of the document. If nil, the first server is selected.
+	ServerSelection *openapi3.ServerSelection
+
+	// Client sends requests. If nil, http.DefaultClient is used.
+	Client *http.Client
+
+	Options *Options
+}
+
+// WorkflowStep is a step of a workflow.
+//
+// The first step must have an operation ID. Next steps either follow a link of the response
+// of the previous step, or call an operation.
+type WorkflowStep struct {
+	OperationID string
+	Link        string
+
+	// Parameters override values of parameters that may be qualified like "path.id".
+	Parameters map[string]string
+
+	// Body overrides the request body.
+	Body        []byte
+	ContentType string
+
+	// SkipRequestValidation sends the request even if it is invalid,
+	// which is useful to test how a server handles invalid requests.
+	SkipRequestValidation bool
+}
+
+// WorkflowStepResult is a request and a response of a step.
+type WorkflowStepResult struct {
+	Step       *WorkflowStep
+	Route      *Route
+	Request    *http.Request
+	PathParams map[string]string
+	Status     int
+	Header     http.Header
+	Body       []byte
+}
+
+// WorkflowError describes a failed step of a workflow.
+type WorkflowError struct {
+	Step int
+	Err  error
+}
+
+func (err *WorkflowError) Error() string {
+	return fmt.Sprintf("Workflow step %d failed: %v", err.Step, err.Err)
+}
+
+func (err *WorkflowError) Unwrap() error {
+	return err.Err
+}
+
+// Run executes steps in order, and returns results of executed steps.
+// The function returns WorkflowError if a step fails.
+func (workflow *Workflow) Run(c context.Context, steps []*WorkflowStep) ([]*WorkflowStepResult, error) {
+	graph, err := openapi3.NewOperationGraph(workflow.Swagger)
+	if err != nil {
+		return nil, err
+	}
+	server := workflow.Server
+	if server == "" {
+		selection := workflow.ServerSelection
+		if selection == nil {
+			selection = &openapi3.ServerSelection{}
+		}
+		if server, err = selection.BaseURL(workflow.Swagger.Servers); err != nil {
+			return nil, err
+		}
+	}
+	var (
+		results  []*WorkflowStepResult
+		previous *WorkflowStepResult
+		node     *openapi3.OperationNode
+	)
+	for i, step := range steps {
+		var link *openapi3.Link
+		if node, link, err = workflow.nextNode(graph, node, previous, step); err != nil {
+			return results, &WorkflowError{Step: i, Err: err}
+		}
+		result, err := workflow.runStep(c, server, node, link, previous, step)
+		if result != nil {
+			results = append(results, result)
+		}
+		if err != nil {
+			return results, &WorkflowError{Step: i, Err: err}
+		}
+		previous = result
+	}
+	return results, nil
+}
+
+// nextNode returns the operation of a step, and the link that leads to it.
+func (workflow *Workflow) nextNode(graph *openapi3.OperationGraph, node *openapi3.OperationNode, previous *WorkflowStepResult, step *WorkflowStep) (*openapi3.OperationNode, *openapi3.Link, error) {
+	if step.Link == "" {
+		if next := graph.NodeByOperationID(step.OperationID); next != nil && next.Callback == "" {
+			return next, nil, nil
+		}
+		return nil, nil, fmt.Errorf("Operation '%s' doesn't exist", step.OperationID)
+	}
+	if previous == nil {
+		return nil, nil, fmt.Errorf("Link '%s' can't be followed by the first step", step.Link)
+	}
+	status := strconv.Itoa(previous.Status)
+	var fallback *openapi3.OperationEdge
+	for _, edge := range graph.Next(node) {
+		if edge.Kind != openapi3.OperationEdgeLink || edge.Name != step.Link {
+			continue
+		}
+		switch edge.Status {
+		case status:
+			return edge.To, edge.Link, nil
+		case "default":
+			fallback = edge
+		}
+	}
+	if fallback != nil {
+		return fallback.To, fallback.Link, nil
+	}
+	return nil, nil, fmt.Errorf("Response with status %d doesn't have link '%s'", previous.Status, step.Link)
+}
+
+func (workflow *Workflow) runStep(c context.Context, server string, node *openapi3.OperationNode, link *openapi3.Link, previous *WorkflowStepResult, step *WorkflowStep) (*WorkflowStepResult, error) {
+	route := &Route{
+		Swagger:   workflow.Swagger,
+		Path:      node.Path,
+		PathItem:  workflow.Swagger.Paths[node.Path],
+		Method:    node.Method,
+		Operation: node.Operation,
+	}
+	evaluate := func(value interface{}) (string, error) {
+		s, ok := value.(string)
+		if !ok {
+			data, err := json.Marshal(value)
+			return string(data), err
+		}
+		if previous == nil || !openapi3.IsLinkExpression(s) {
+			return s, nil
+		}
+		requestInput := &RequestValidationInput{
+			Request:    previous.Request,
+			PathParams: previous.PathParams,
+			Route:      previous.Route,
+		}
+		return openapi3.ExpandRuntimeExpressionTemplate(s, func(expression *openapi3.RuntimeExpression) (string, error) {
+			return evaluateRuntimeExpression(requestInput, previous.Status, previous.Header, previous.Body, expression)
+		})
+	}
+
+	// Collect values of parameters from the link and the step.
+	values := make(map[*openapi3.Parameter]string)
+	setParameter := func(key string, value string) error {
+		parameter := route.PathItem.FindLinkParameter(route.Operation, key)
+		if parameter == nil {
+			return fmt.Errorf("Operation %s %s doesn't have parameter '%s'", route.Method, route.Path, key)
+		}
+		values[parameter] = value
+		return nil
+	}
+	var body []byte
+	if link != nil {
+		for key, expression := range link.Parameters {
+			value, err := evaluate(expression)
+			if err != nil {
+				return nil, fmt.Errorf("Parameter '%s' of link '%s' can't be evaluated: %v", key, step.Link, err)
+			}
+			if err := setParameter(key, value); err != nil {
+				return nil, err
+			}
+		}
+		if link.RequestBody != nil {
+			value, err := evaluate(link.RequestBody)
+			if err != nil {
+				return nil, fmt.Errorf("Request body of link '%s' can't be evaluated: %v", step.Link, err)
+			}
+			body = []byte(value)
+		}
+		if link.Server != nil {
+			var err error
+			if server, err = link.Server.BaseURL(nil); err != nil {
+				return nil, fmt.Errorf("Server of link '%s' is invalid: %v", step.Link, err)
+			}
+		}
+	}
+	for key, value := range step.Parameters {
+		if err := setParameter(key, value); err != nil {
+			return nil, err
+		}
+	}
+	if step.Body != nil {
+		body = step.Body
+	}
+
+	req, err := newWorkflowRequest(c, server, route, values, body, step.ContentType)
+	if err != nil {
+		return nil, err
+	}
+	requestInput := &RequestValidationInput{
+		Request:    req,
+		PathParams: make(map[string]string),
+		Route:      route,
+		Options:    workflow.Options,
+	}
+	for parameter, value := range values {
+		if parameter.In == openapi3.ParameterInPath {
+			requestInput.PathParams[parameter.Name] = value
+		}
+	}
+	if !step.SkipRequestValidation {
+		if err := ValidateRequest(c, requestInput); err != nil {
+			return nil, err
+		}
+	}
+
+	client := workflow.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if req.GetBody != nil {
+		// Restore the body for expressions of next steps.
+		if req.Body, err = req.GetBody(); err != nil {
+			return nil, err
+		}
+	}
+	result := &WorkflowStepResult{
+		Step:       step,
+		Route:      route,
+		Request:    req,
+		PathParams: requestInput.PathParams,
+		Status:     resp.StatusCode,
+		Header:     resp.Header,
+		Body:       data,
+	}
+	responseInput := &ResponseValidationInput{
+		RequestValidationInput: requestInput,
+		Status:                 resp.StatusCode,
+		Header:                 resp.Header,
+		Options:                workflow.Options,
+	}
+	if err := ValidateResponse(c, responseInput.SetBodyBytes(data)); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// newWorkflowRequest returns a request of the operation with values of parameters.
+func newWorkflowRequest(c context.Context, server string, route *Route, values map[*openapi3.Parameter]string, body []byte, contentType string) (*http.Request, error) {
+	path := route.Path
+	query := url.Values{}
+	for parameter, value := range values {
+		switch parameter.In {
+		case openapi3.ParameterInPath:
+			path = strings.Replace(path, "{"+parameter.Name+"}", url.PathEscape(value), -1)
+		case openapi3.ParameterInQuery:
+			query.Set(parameter.Name, value)
+		}
+	}
+	u := strings.TrimSuffix(server, "/") + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	var bodyReader io.Reader
+	if body != nil {
+		bodyReader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequest(route.Method, u, bodyReader)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(c)
+	for parameter, value := range values {
+		switch parameter.In {
+		case openapi3.ParameterInHeader:
+			req.Header.Set(parameter.Name, value)
+		case openapi3.ParameterInCookie:
+			req.AddCookie(&http.Cookie{Name: parameter.Name, Value: value})
+		}
+	}
+	if body != nil {
+		if contentType == "" {
+			contentType = workflowContentType(route.Operation)
+		}
+		req.Header.Set("Content-Type", contentType)
+	}
+	return req, nil
+}
+
+// workflowContentType returns the content type of the request body of the operation,
+// preferring JSON.
+func workflowContentType(operation *openapi3.Operation) string {
+	if operation.RequestBody == nil || operation.RequestBody.Value == nil {
+		return "application/json"
+	}
+	content := operation.RequestBody.Value.Content
+	if content.Get("application/json") != nil || len(content) == 0 {
+		return "application/json"
+	}
+	var result string
+	for mime := range content {
+		if result == "" || mime < result {
+			result = mime
+		}
+	}
+	return result
+}
@@ -0,0 +1,143 @@
+package openapi3filter_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/stretchr/testify/require"
+)
+
+const workflowSpec = `
+openapi: 3.0.0
+info:
+  title: Users
+  version: "1.0"
+paths:
+  /users:
+    post:
+      operationId: createUser
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [name]
+              properties:
+                name:
+                  type: string
+      responses:
+        "201":
+          description: created
+          headers:
+            X-Request-ID:
+              schema:
+                type: string
+          content:
+            application/json:
+              schema:
+                type: object
+                required: [id]
+                properties:
+                  id:
+                    type: integer
+          links:
+            GetUser:
+              operationId: getUser
+              parameters:
+                id: $response.body#/id
+                header.x-request-id: $response.header.X-Request-ID
+  /users/{id}:
+    get:
+      operationId: getUser
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: integer
+        - name: X-Request-ID
+          in: header
+          schema:
+            type: string
+      responses:
+        "200":
+          description: user
+          content:
+            application/json:
+              schema:
+                type: object
+                required: [id, name]
+                properties:
+                  id:
+                    type: integer
+                  name:
+                    type: string
+`
+
+func TestWorkflow(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(workflowSpec))
+	require.NoError(t, err)
+	require.NoError(t, swagger.Validate(context.Background()))
+
+	var getUserBody string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/users", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		require.JSONEq(t, `{"name": "alice"}`, string(body))
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Request-ID", "r1")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id": 42}`))
+	})
+	mux.HandleFunc("/users/42", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "r1", r.Header.Get("X-Request-ID"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(getUserBody))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	workflow := &openapi3filter.Workflow{
+		Swagger: swagger,
+		Server:  server.URL,
+	}
+	steps := []*openapi3filter.WorkflowStep{
+		{OperationID: "createUser", Body: []byte(`{"name": "alice"}`)},
+		{Link: "GetUser"},
+	}
+
+	getUserBody = `{"id": 42, "name": "alice"}`
+	results, err := workflow.Run(context.Background(), steps)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	require.Equal(t, "/users/42", results[1].Request.URL.Path)
+	require.Equal(t, map[string]string{"id": "42"}, results[1].PathParams)
+	require.Equal(t, http.StatusOK, results[1].Status)
+
+	getUserBody = `{"id": 42}`
+	results, err = workflow.Run(context.Background(), steps)
+	require.Len(t, results, 2)
+	var workflowErr *openapi3filter.WorkflowError
+	require.True(t, errors.As(err, &workflowErr))
+	require.Equal(t, 1, workflowErr.Step)
+	require.IsType(t, &openapi3filter.ResponseError{}, workflowErr.Err)
+
+	_, err = workflow.Run(context.Background(), []*openapi3filter.WorkflowStep{
+		{OperationID: "createUser", Body: []byte(`{}`)},
+	})
+	require.True(t, errors.As(err, &workflowErr))
+	require.IsType(t, &openapi3filter.RequestError{}, workflowErr.Err)
+
+	_, err = workflow.Run(context.Background(), []*openapi3filter.WorkflowStep{
+		{OperationID: "createUser", Body: []byte(`{"name": "alice"}`)},
+		{Link: "DeleteUser"},
+	})
+	require.Error(t, err)
+}
@@ -0,0 +1,329 @@
+package openapi3gen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+const componentSchemaPrefix = "#/components/schemas/"
+
+// GenerateGoTypes returns Go source code of a package with a type declaration for each schema,
+// which is usually components.schemas of a document.
+//
+// Object schemas are structs with json tags, where properties that aren't required are omitted when empty,
+// and nullable properties are pointers. Schemas with enums are named types with a constant for each value.
+// References to components are names of declared types.
+// Schemas with oneOf or anyOf are interface{}, because Go doesn't have union types.
+func GenerateGoTypes(packageName string, schemas map[string]*openapi3.SchemaRef) ([]byte, error) {
+	g := &goTypeGenerator{
+		imports: make(map[string]bool),
+	}
+	names := make([]string, 0, len(schemas))
+	for name := range schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := g.declare(goName(name), schemas[name]); err != nil {
+			return nil, fmt.Errorf("Schema '%s' can't be generated: %v", name, err)
+		}
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "package %s\n\n", packageName)
+	if len(g.imports) > 0 {
+		imports := make([]string, 0, len(g.imports))
+		for path := range g.imports {
+			imports = append(imports, strconv.Quote(path))
+		}
+		sort.Strings(imports)
+		fmt.Fprintf(&buf, "import (\n%s\n)\n\n", strings.Join(imports, "\n"))
+	}
+	for _, decl := range g.decls {
+		buf.WriteString(decl)
+		buf.WriteString("\n")
+	}
+	return format.Source(buf.Bytes())
+}
+
+type goTypeGenerator struct {
+	decls   []string
+	imports map[string]bool
+}
+
+// declare adds a declaration of the type with the name.
+// Declarations of nested types follow the declaration.
+func (g *goTypeGenerator) declare(name string, ref *openapi3.SchemaRef) error {
+	if ref == nil || (ref.Value == nil && ref.Ref == "") {
+		return fmt.Errorf("Type '%s' doesn't have a schema", name)
+	}
+	i := len(g.decls)
+	g.decls = append(g.decls, "")
+	var buf strings.Builder
+	if ref.Ref != "" {
+		// An alias of another component
+		expr, err := g.typeExpr(name, ref)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&buf, "type %s = %s\n", name, expr)
+		g.decls[i] = buf.String()
+		return nil
+	}
+	schema := ref.Value
+	writeComment(&buf, schema.Description)
+	if len(schema.Enum) > 0 {
+		if err := g.writeEnum(&buf, name, schema); err != nil {
+			return err
+		}
+		g.decls[i] = buf.String()
+		return nil
+	}
+	expr, err := g.schemaExpr(name, schema)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(&buf, "type %s %s\n", name, expr)
+	g.decls[i] = buf.String()
+	return nil
+}
+
+func (g *goTypeGenerator) writeEnum(buf *strings.Builder, name string, schema *openapi3.Schema) error {
+	base, err := primitiveType(schema)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(buf, "type %s %s\n\nconst (\n", name, base)
+	for _, value := range schema.Enum {
+		switch value := value.(type) {
+		case string:
+			suffix := goName(value)
+			if suffix == "" {
+				suffix = "Empty"
+			}
+			fmt.Fprintf(buf, "\t%s%s %s = %s\n", name, suffix, name, strconv.Quote(value))
+		case float64:
+			literal := strconv.FormatFloat(value, 'f', -1, 64)
+			suffix := strings.NewReplacer("-", "Minus", ".", "_").Replace(literal)
+			fmt.Fprintf(buf, "\t%s%s %s = %s\n", name, suffix, name, literal)
+		case bool:
+			fmt.Fprintf(buf, "\t%s%s %s = %t\n", name, goName(strconv.FormatBool(value)), name, value)
+		default:
+			return fmt.Errorf("Enum value %v of type '%s' is not supported", value, name)
+		}
+	}
+	buf.WriteString(")\n")
+	return nil
+}
+
+// typeExpr returns the Go type of the schema.
+// The name is used for types of nested enums and structs.
+func (g *goTypeGenerator) typeExpr(name string, ref *openapi3.SchemaRef) (string, error) {
+	if ref == nil {
+		return "interface{}", nil
+	}
+	if ref.Ref != "" {
+		if !strings.HasPrefix(ref.Ref, componentSchemaPrefix) {
+			return "", fmt.Errorf("Reference '%s' is not supported", ref.Ref)
+		}
+		return goName(ref.Ref[len(componentSchemaPrefix):]), nil
+	}
+	schema := ref.Value
+	if schema == nil {
+		return "interface{}", nil
+	}
+	if len(schema.Enum) > 0 {
+		if err := g.declare(name, ref); err != nil {
+			return "", err
+		}
+		return name, nil
+	}
+	return g.schemaExpr(name, schema)
+}
+
+func (g *goTypeGenerator) schemaExpr(name string, schema *openapi3.Schema) (string, error) {
+	if len(schema.OneOf) > 0 || len(schema.AnyOf) > 0 {
+		return "interface{}", nil
+	}
+	if len(schema.AllOf) > 0 {
+		return g.structExpr(name, schema)
+	}
+	switch schema.Type {
+	case "array":
+		item, err := g.typeExpr(name+"Item", schema.Items)
+		if err != nil {
+			return "", err
+		}
+		return "[]" + item, nil
+	case "object", "":
+		if len(schema.Properties) > 0 {
+			return g.structExpr(name, schema)
+		}
+		if schema.AdditionalProperties != nil {
+			value, err := g.typeExpr(name+"Value", schema.AdditionalProperties)
+			if err != nil {
+				return "", err
+			}
+			return "map[string]" + value, nil
+		}
+		if schema.Type == "" {
+			return "interface{}", nil
+		}
+		return "map[string]interface{}", nil
+	}
+	expr, err := primitiveType(schema)
+	if expr == "time.Time" {
+		g.imports["time"] = true
+	}
+	return expr, err
+}
+
+// structExpr returns a struct with properties of the schema.
+// Components in allOf are embedded, and properties of inline schemas in allOf are fields of the struct.
+func (g *goTypeGenerator) structExpr(name string, schema *openapi3.Schema) (string, error) {
+	var buf strings.Builder
+	buf.WriteString("struct {\n")
+	schemas := []*openapi3.Schema{schema}
+	for _, ref := range schema.AllOf {
+		if ref == nil {
+			continue
+		}
+		if ref.Ref != "" {
+			embedded, err := g.typeExpr(name, ref)
+			if err != nil {
+				return "", err
+			}
+			fmt.Fprintf(&buf, "%s\n", embedded)
+			continue
+		}
+		if ref.Value == nil || (ref.Value.Type != "" && ref.Value.Type != "object") {
+			return "", fmt.Errorf("Schemas in allOf of type '%s' must be objects", name)
+		}
+		schemas = append(schemas, ref.Value)
+	}
+	for _, schema := range schemas {
+		required := make(map[string]bool, len(schema.Required))
+		for _, property := range schema.Required {
+			required[property] = true
+		}
+		for _, property := range schema.OrderedPropertyNames() {
+			ref := schema.Properties[property]
+			fieldName := goName(property)
+			expr, err := g.typeExpr(name+fieldName, ref)
+			if err != nil {
+				return "", err
+			}
+			if isGoPointer(ref) {
+				expr = "*" + expr
+			}
+			tag := property
+			if !required[property] {
+				tag += ",omitempty"
+			}
+			if ref != nil && ref.Ref == "" && ref.Value != nil {
+				writeComment(&buf, ref.Value.Description)
+			}
+			fmt.Fprintf(&buf, "%s %s `json:%s`\n", fieldName, expr, strconv.Quote(tag))
+		}
+	}
+	buf.WriteString("}")
+	return buf.String(), nil
+}
+
+// isGoPointer returns true if the Go type of a property is a pointer,
+// which is the case for nullable properties unless null is already the zero value of the Go type.
+func isGoPointer(ref *openapi3.SchemaRef) bool {
+	if ref == nil || ref.Value == nil || !ref.Value.Nullable {
+		return false
+	}
+	schema := ref.Value
+	if len(schema.OneOf) > 0 || len(schema.AnyOf) > 0 {
+		return false
+	}
+	switch schema.Type {
+	case "array":
+		return false
+	case "object", "":
+		return len(schema.Properties) > 0 || len(schema.AllOf) > 0
+	case "string":
+		return schema.Format != "byte"
+	}
+	return true
+}
+
+// primitiveType returns the Go type of a schema of a string, a number, an integer, or a boolean.
+func primitiveType(schema *openapi3.Schema) (string, error) {
+	switch schema.Type {
+	case "string":
+		switch schema.Format {
+		case "date-time":
+			return "time.Time", nil
+		case "byte":
+			return "[]byte", nil
+		}
+		return "string", nil
+	case "integer":
+		if schema.Format == "int32" {
+			return "int32", nil
+		}
+		return "int64", nil
+	case "number":
+		if schema.Format == "float" {
+			return "float32", nil
+		}
+		return "float64", nil
+	case "boolean":
+		return "bool", nil
+	}
+	return "", fmt.Errorf("Type '%s' is not supported", schema.Type)
+}
+
+func writeComment(buf *strings.Builder, description string) {
+	description = strings.TrimSpace(description)
+	if description == "" {
+		return
+	}
+	for _, line := range strings.Split(description, "\n") {
+		fmt.Fprintf(buf, "// %s\n", strings.TrimSpace(line))
+	}
+}
+
+// goInitialisms are words that Go names spell in upper case.
+var goInitialisms = map[string]bool{
+	"API":  true,
+	"HTTP": true,
+	"ID":   true,
+	"JSON": true,
+	"URI":  true,
+	"URL":  true,
+	"UUID": true,
+}
+
+// goName returns an exported Go name for a name like "user_id", "user-id", or "userId".
+func goName(name string) string {
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var buf strings.Builder
+	for _, word := range words {
+		if upper := strings.ToUpper(word); goInitialisms[upper] {
+			buf.WriteString(upper)
+			continue
+		}
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		buf.WriteString(string(runes))
+	}
+	result := buf.String()
+	if result != "" && unicode.IsDigit([]rune(result)[0]) {
+		result = "N" + result
+	}
+	return result
+}
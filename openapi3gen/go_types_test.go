@@ -0,0 +1,124 @@
+package openapi3gen_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3gen"
+	"github.com/stretchr/testify/require"
+)
+
+const goTypesSpec = `
+openapi: 3.0.0
+info:
+  title: Users
+  version: "1.0"
+paths: {}
+components:
+  schemas:
+    user:
+      description: A user of the service.
+      type: object
+      required: [id, name]
+      properties:
+        id:
+          type: integer
+        name:
+          type: string
+        status:
+          $ref: "#/components/schemas/Status"
+        createdAt:
+          type: string
+          format: date-time
+        nickname:
+          type: string
+          nullable: true
+        role:
+          type: string
+          enum: [admin, member]
+        labels:
+          type: object
+          additionalProperties:
+            type: string
+        address:
+          type: object
+          properties:
+            city:
+              type: string
+        friends:
+          type: array
+          items:
+            $ref: "#/components/schemas/user"
+    Status:
+      type: string
+      enum: [active, blocked]
+    Admin:
+      allOf:
+        - $ref: "#/components/schemas/user"
+        - type: object
+          properties:
+            level:
+              type: integer
+              format: int32
+    Pet:
+      oneOf:
+        - type: string
+        - type: integer
+    Users:
+      $ref: "#/components/schemas/user"
+`
+
+func TestGenerateGoTypes(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(goTypesSpec))
+	require.NoError(t, err)
+	source, err := openapi3gen.GenerateGoTypes("users", swagger.Components.Schemas)
+	require.NoError(t, err)
+	// Struct tags are quoted with ' in the expected source.
+	require.Equal(t, strings.Replace(expectedGoTypes, "'", "`", -1), string(source))
+}
+
+const expectedGoTypes = `package users
+
+import (
+	"time"
+)
+
+type Admin struct {
+	User
+	Level int32 'json:"level,omitempty"'
+}
+
+type Pet interface{}
+
+type Status string
+
+const (
+	StatusActive  Status = "active"
+	StatusBlocked Status = "blocked"
+)
+
+type Users = User
+
+// A user of the service.
+type User struct {
+	ID        int64             'json:"id"'
+	Name      string            'json:"name"'
+	Status    Status            'json:"status,omitempty"'
+	CreatedAt time.Time         'json:"createdAt,omitempty"'
+	Nickname  *string           'json:"nickname,omitempty"'
+	Role      UserRole          'json:"role,omitempty"'
+	Labels    map[string]string 'json:"labels,omitempty"'
+	Address   struct {
+		City string 'json:"city,omitempty"'
+	} 'json:"address,omitempty"'
+	Friends []User 'json:"friends,omitempty"'
+}
+
+type UserRole string
+
+const (
+	UserRoleAdmin  UserRole = "admin"
+	UserRoleMember UserRole = "member"
+)
+`
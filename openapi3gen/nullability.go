@@ -0,0 +1,172 @@
+package openapi3gen
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// PropertyNullability describes how GenerateGoTypes represents a property.
+type PropertyNullability struct {
+	// Pointer is the JSON pointer of the property like "#/components/schemas/User/properties/nickname".
+	Pointer string
+
+	Required bool
+	Nullable bool
+
+	// GoPointer is true if the Go field is a pointer.
+	GoPointer bool
+
+	// GoNilable is true if the Go field can be nil, because it's a pointer, a slice, a map, or an interface,
+	// so null can be told apart from other values.
+	GoNilable bool
+}
+
+// IsAmbiguous returns true if the Go field can't tell apart values that the schema treats differently:
+// an optional property is omitted when its field has the zero value,
+// and null of a nullable property is decoded as the zero value, unless the field can be nil.
+func (property *PropertyNullability) IsAmbiguous() bool {
+	return (property.Nullable && !property.GoNilable) || (!property.Required && !property.GoNilable)
+}
+
+// AuditNullability lists properties of the schemas, which are usually components.schemas of a document,
+// and of schemas nested in them.
+func AuditNullability(schemas map[string]*openapi3.SchemaRef) []*PropertyNullability {
+	var result []*PropertyNullability
+	visitProperties(schemas, func(pointer string, schema *openapi3.Schema, name string, ref *openapi3.SchemaRef) {
+		result = append(result, &PropertyNullability{
+			Pointer:   pointer,
+			Required:  isRequired(schema, name),
+			Nullable:  ref.Value != nil && ref.Value.Nullable,
+			GoPointer: isGoPointer(ref),
+			GoNilable: isGoNilable(ref),
+		})
+	})
+	return result
+}
+
+// NullabilityConvention is a convention of nullability of properties that NormalizeNullability enforces.
+type NullabilityConvention int
+
+const (
+	// OptionalIsNullable makes properties that aren't required nullable,
+	// so their Go fields are pointers, and required properties not nullable.
+	OptionalIsNullable NullabilityConvention = iota
+
+	// NullableIsRequired makes nullable properties required, so absent properties are invalid
+	// and null is the only way to omit a value.
+	NullableIsRequired
+)
+
+// NormalizeNullability changes properties of the schemas to follow the convention,
+// and returns JSON pointers of changed properties.
+//
+// Properties that refer to components aren't changed, because the components may be used elsewhere.
+func NormalizeNullability(schemas map[string]*openapi3.SchemaRef, convention NullabilityConvention) []string {
+	var changed []string
+	visitProperties(schemas, func(pointer string, schema *openapi3.Schema, name string, ref *openapi3.SchemaRef) {
+		if ref.Ref != "" || ref.Value == nil {
+			return
+		}
+		property := ref.Value
+		required := isRequired(schema, name)
+		switch convention {
+		case OptionalIsNullable:
+			if property.Nullable == required {
+				property.Nullable = !required
+				property.ResetCompiled()
+				changed = append(changed, pointer)
+			}
+		case NullableIsRequired:
+			if property.Nullable && !required {
+				schema.Required = append(schema.Required, name)
+				schema.ResetCompiled()
+				changed = append(changed, pointer)
+			}
+		}
+	})
+	return changed
+}
+
+// isGoNilable returns true if the Go type of a property can be nil.
+func isGoNilable(ref *openapi3.SchemaRef) bool {
+	if isGoPointer(ref) {
+		return true
+	}
+	if ref.Value == nil {
+		return true
+	}
+	schema := ref.Value
+	if len(schema.OneOf) > 0 || len(schema.AnyOf) > 0 {
+		return true
+	}
+	switch schema.Type {
+	case "array":
+		return true
+	case "object", "":
+		return len(schema.Properties) == 0 && len(schema.AllOf) == 0
+	case "string":
+		return schema.Format == "byte"
+	}
+	return false
+}
+
+func isRequired(schema *openapi3.Schema, name string) bool {
+	for _, required := range schema.Required {
+		if required == name {
+			return true
+		}
+	}
+	return false
+}
+
+// visitProperties calls the function with every property of the schemas and of inline schemas nested in them.
+func visitProperties(schemas map[string]*openapi3.SchemaRef, f func(pointer string, schema *openapi3.Schema, name string, ref *openapi3.SchemaRef)) {
+	visited := make(map[*openapi3.Schema]bool)
+	var visit func(pointer string, ref *openapi3.SchemaRef)
+	visit = func(pointer string, ref *openapi3.SchemaRef) {
+		if ref == nil || ref.Value == nil || visited[ref.Value] {
+			return
+		}
+		schema := ref.Value
+		visited[schema] = true
+		for _, name := range schema.OrderedPropertyNames() {
+			property := schema.Properties[name]
+			if property == nil {
+				continue
+			}
+			propertyPointer := pointer + "/properties/" + escapeJSONPointer(name)
+			f(propertyPointer, schema, name, property)
+			if property.Ref == "" {
+				visit(propertyPointer, property)
+			}
+		}
+		for i, item := range schema.AllOf {
+			if item != nil && item.Ref == "" {
+				visit(pointer+"/allOf/"+strconv.Itoa(i), item)
+			}
+		}
+		if items := schema.Items; items != nil && items.Ref == "" {
+			visit(pointer+"/items", items)
+		}
+		if value := schema.AdditionalProperties; value != nil && value.Ref == "" {
+			visit(pointer+"/additionalProperties", value)
+		}
+	}
+	names := make([]string, 0, len(schemas))
+	for name := range schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if ref := schemas[name]; ref != nil && ref.Ref == "" {
+			visit(componentSchemaPrefix+escapeJSONPointer(name), ref)
+		}
+	}
+}
+
+func escapeJSONPointer(token string) string {
+	return strings.Replace(strings.Replace(token, "~", "~0", -1), "/", "~1", -1)
+}
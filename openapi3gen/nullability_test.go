@@ -0,0 +1,83 @@
+package openapi3gen_test
+
+import (
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3gen"
+	"github.com/stretchr/testify/require"
+)
+
+const nullabilitySpec = `
+openapi: 3.0.0
+info:
+  title: Users
+  version: "1.0"
+paths: {}
+components:
+  schemas:
+    User:
+      type: object
+      required: [id, manager]
+      properties:
+        id:
+          type: integer
+        manager:
+          type: string
+          nullable: true
+        nickname:
+          type: string
+        tags:
+          type: array
+          items:
+            type: string
+        address:
+          $ref: "#/components/schemas/Address"
+    Address:
+      type: object
+      properties:
+        city:
+          type: string
+          nullable: true
+`
+
+func TestNullability(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(nullabilitySpec))
+	require.NoError(t, err)
+	schemas := swagger.Components.Schemas
+
+	audit := openapi3gen.AuditNullability(schemas)
+	require.Equal(t, []*openapi3gen.PropertyNullability{
+		{Pointer: "#/components/schemas/Address/properties/city", Nullable: true, GoPointer: true, GoNilable: true},
+		{Pointer: "#/components/schemas/User/properties/id", Required: true},
+		{Pointer: "#/components/schemas/User/properties/manager", Required: true, Nullable: true, GoPointer: true, GoNilable: true},
+		{Pointer: "#/components/schemas/User/properties/nickname"},
+		{Pointer: "#/components/schemas/User/properties/tags", GoNilable: true},
+		{Pointer: "#/components/schemas/User/properties/address"},
+	}, audit)
+	var ambiguous []string
+	for _, property := range audit {
+		if property.IsAmbiguous() {
+			ambiguous = append(ambiguous, property.Pointer)
+		}
+	}
+	require.Equal(t, []string{
+		"#/components/schemas/User/properties/nickname",
+		"#/components/schemas/User/properties/address",
+	}, ambiguous)
+
+	changed := openapi3gen.NormalizeNullability(schemas, openapi3gen.NullableIsRequired)
+	require.Equal(t, []string{"#/components/schemas/Address/properties/city"}, changed)
+	require.Equal(t, []string{"city"}, schemas["Address"].Value.Required)
+
+	changed = openapi3gen.NormalizeNullability(schemas, openapi3gen.OptionalIsNullable)
+	require.Equal(t, []string{
+		"#/components/schemas/Address/properties/city",
+		"#/components/schemas/User/properties/manager",
+		"#/components/schemas/User/properties/nickname",
+		"#/components/schemas/User/properties/tags",
+	}, changed)
+	user := schemas["User"].Value
+	require.NoError(t, user.VisitJSON(map[string]interface{}{"id": 1.0, "manager": "bob", "nickname": nil}))
+	require.Error(t, user.VisitJSON(map[string]interface{}{"id": 1.0, "manager": nil}))
+}
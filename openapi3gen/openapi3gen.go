@@ -1,4 +1,4 @@
-// Package openapi3gen generates OpenAPI 3 schemas for Go types.
+// Package openapi3gen generates OpenAPI 3 schemas for Go types, draft documents for routes of services, and Go types for schemas.
 package openapi3gen
 
 import (
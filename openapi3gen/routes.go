@@ -0,0 +1,241 @@
+package openapi3gen
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Routes collects routes of a service, so a draft document can be synthesized from them.
+//
+// Routes of http.ServeMux can be registered with Handle and HandleFunc.
+// Routes of other routers can be registered with Add, for example when walking routes of chi:
+//
+//	chi.Walk(router, func(method string, route string, handler http.Handler, middlewares ...func(http.Handler) http.Handler) error {
+//		routes.Add(method, route)
+//		return nil
+//	})
+type Routes struct {
+	routes []*Route
+}
+
+// Route is a registered route with metadata of its operation.
+type Route struct {
+	Method  string
+	Pattern string
+
+	OperationID string
+	Summary     string
+	Tags        []string
+
+	// RequestBody and values of Responses are Go values whose types describe JSON bodies.
+	RequestBody interface{}
+	Responses   map[int]interface{}
+}
+
+func NewRoutes() *Routes {
+	return &Routes{}
+}
+
+// Add registers a route with a pattern like "/users/{id}", "/users/{id:[0-9]+}", or "/users/:id".
+// An empty method is GET.
+func (routes *Routes) Add(method string, pattern string) *Route {
+	if method == "" {
+		method = http.MethodGet
+	}
+	route := &Route{
+		Method:    strings.ToUpper(method),
+		Pattern:   pattern,
+		Responses: make(map[int]interface{}),
+	}
+	routes.routes = append(routes.routes, route)
+	return route
+}
+
+// Handle registers the handler in the mux, and the route with the pattern of the mux,
+// like "GET /users/{id}".
+func (routes *Routes) Handle(mux *http.ServeMux, pattern string, handler http.Handler) *Route {
+	mux.Handle(pattern, handler)
+	method, path := "", pattern
+	if i := strings.IndexAny(pattern, " \t"); i >= 0 {
+		method, path = pattern[:i], strings.TrimLeft(pattern[i:], " \t")
+	}
+	// Ignore the host of the pattern.
+	if i := strings.IndexByte(path, '/'); i > 0 {
+		path = path[i:]
+	}
+	return routes.Add(method, path)
+}
+
+// HandleFunc registers the handler function in the mux like Handle.
+func (routes *Routes) HandleFunc(mux *http.ServeMux, pattern string, handler func(http.ResponseWriter, *http.Request)) *Route {
+	return routes.Handle(mux, pattern, http.HandlerFunc(handler))
+}
+
+func (route *Route) WithOperationID(value string) *Route {
+	route.OperationID = value
+	return route
+}
+
+func (route *Route) WithSummary(value string) *Route {
+	route.Summary = value
+	return route
+}
+
+func (route *Route) WithTags(values ...string) *Route {
+	route.Tags = append(route.Tags, values...)
+	return route
+}
+
+func (route *Route) WithRequestBody(value interface{}) *Route {
+	route.RequestBody = value
+	return route
+}
+
+// WithResponse declares a response with the status. A nil value declares a response without a body.
+func (route *Route) WithResponse(status int, value interface{}) *Route {
+	route.Responses[status] = value
+	return route
+}
+
+// Swagger returns a draft document with operations of the routes.
+// Path parameters are inferred from patterns.
+func (routes *Routes) Swagger(title string, version string) (*openapi3.Swagger, error) {
+	swagger := &openapi3.Swagger{
+		OpenAPI: "3.0.0",
+		Info: openapi3.Info{
+			Title:   title,
+			Version: version,
+		},
+		Paths: make(openapi3.Paths),
+	}
+	for _, route := range routes.routes {
+		path, parameters, err := toPathTemplate(route.Pattern)
+		if err != nil {
+			return nil, err
+		}
+		pathItem := swagger.Paths[path]
+		if pathItem == nil {
+			pathItem = &openapi3.PathItem{}
+			swagger.Paths[path] = pathItem
+		}
+		if pathItem.GetOperation(route.Method) != nil {
+			return nil, fmt.Errorf("Route %s %s is registered twice", route.Method, route.Pattern)
+		}
+		operation, err := route.operation(parameters)
+		if err != nil {
+			return nil, fmt.Errorf("Route %s %s can't be described: %v", route.Method, route.Pattern, err)
+		}
+		pathItem.SetOperation(route.Method, operation)
+	}
+	return swagger, nil
+}
+
+func (route *Route) operation(parameters openapi3.Parameters) (*openapi3.Operation, error) {
+	operation := &openapi3.Operation{
+		OperationID: route.OperationID,
+		Summary:     route.Summary,
+		Tags:        route.Tags,
+		Parameters:  parameters,
+		Responses:   make(openapi3.Responses),
+	}
+	if value := route.RequestBody; value != nil {
+		schemaRef, _, err := NewSchemaRefForValue(value)
+		if err != nil {
+			return nil, err
+		}
+		operation.RequestBody = &openapi3.RequestBodyRef{
+			Value: openapi3.NewRequestBody().WithJSONSchemaRef(schemaRef),
+		}
+	}
+	statuses := make([]int, 0, len(route.Responses))
+	for status := range route.Responses {
+		statuses = append(statuses, status)
+	}
+	sort.Ints(statuses)
+	for _, status := range statuses {
+		response := openapi3.NewResponse().WithDescription(http.StatusText(status))
+		if value := route.Responses[status]; value != nil {
+			schemaRef, _, err := NewSchemaRefForValue(value)
+			if err != nil {
+				return nil, err
+			}
+			response.WithJSONSchemaRef(schemaRef)
+		}
+		operation.Responses[fmt.Sprint(status)] = &openapi3.ResponseRef{Value: response}
+	}
+	if len(operation.Responses) == 0 {
+		operation.Responses["default"] = &openapi3.ResponseRef{
+			Value: openapi3.NewResponse().WithDescription("Default response"),
+		}
+	}
+	return operation, nil
+}
+
+// toPathTemplate converts a pattern of a router to a path template of OpenAPI,
+// and returns parameters of variables of the pattern.
+func toPathTemplate(pattern string) (string, openapi3.Parameters, error) {
+	var parameters openapi3.Parameters
+	addParameter := func(name string, re string) {
+		schema := openapi3.NewStringSchema()
+		if re != "" {
+			schema.Pattern = "^" + re + "$"
+		}
+		parameters = append(parameters, &openapi3.ParameterRef{
+			Value: openapi3.NewPathParameter(name).WithSchema(schema),
+		})
+	}
+	segments := strings.Split(pattern, "/")
+	for i, segment := range segments {
+		switch {
+		case strings.HasPrefix(segment, ":") && len(segment) > 1:
+			// Routers like gin, echo, and httprouter
+			addParameter(segment[1:], "")
+			segments[i] = "{" + segment[1:] + "}"
+			continue
+		case strings.HasPrefix(segment, "*"):
+			name := segment[1:]
+			if name == "" {
+				name = "wildcard"
+			}
+			addParameter(name, "")
+			segments[i] = "{" + name + "}"
+			continue
+		}
+		var buf strings.Builder
+		for rest := segment; rest != ""; {
+			start := strings.IndexByte(rest, '{')
+			if start < 0 {
+				buf.WriteString(rest)
+				break
+			}
+			end := strings.IndexByte(rest[start:], '}')
+			if end < 0 {
+				return "", nil, fmt.Errorf("Pattern '%s' is missing '}'", pattern)
+			}
+			end += start
+			buf.WriteString(rest[:start])
+			variable := rest[start+1 : end]
+			rest = rest[end+1:]
+			if variable == "$" {
+				// The end of a path in http.ServeMux
+				continue
+			}
+			name, re := variable, ""
+			if j := strings.IndexByte(variable, ':'); j >= 0 {
+				name, re = variable[:j], variable[j+1:]
+			}
+			name = strings.TrimSuffix(name, "...")
+			if name == "" {
+				return "", nil, fmt.Errorf("Pattern '%s' has a variable without a name", pattern)
+			}
+			addParameter(name, re)
+			buf.WriteString("{" + name + "}")
+		}
+		segments[i] = buf.String()
+	}
+	return strings.Join(segments, "/"), parameters, nil
+}
@@ -0,0 +1,57 @@
+package openapi3gen_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3gen"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoutes(t *testing.T) {
+	type User struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+	mux := http.NewServeMux()
+	handler := func(w http.ResponseWriter, r *http.Request) {}
+	routes := openapi3gen.NewRoutes()
+	routes.HandleFunc(mux, "POST /users", handler).
+		WithOperationID("createUser").
+		WithRequestBody(User{}).
+		WithResponse(http.StatusCreated, User{})
+	routes.HandleFunc(mux, "GET example.com/users/{id}", handler).
+		WithResponse(http.StatusOK, User{}).
+		WithResponse(http.StatusNotFound, nil)
+	routes.HandleFunc(mux, "/files/{path...}", handler)
+	routes.Add("DELETE", "/users/{id:[0-9]+}")
+	routes.Add("GET", "/teams/:team/members/*member").WithTags("teams")
+
+	swagger, err := routes.Swagger("Legacy", "0.1")
+	require.NoError(t, err)
+	require.NoError(t, swagger.Validate(context.Background()))
+	require.Len(t, swagger.Paths, 4)
+
+	create := swagger.Paths["/users"].Post
+	require.Equal(t, "createUser", create.OperationID)
+	require.Equal(t, "object", create.RequestBody.Value.Content.Get("application/json").Schema.Value.Type)
+	require.Contains(t, create.Responses, "201")
+
+	get := swagger.Paths["/users/{id}"].Get
+	require.Len(t, get.Parameters, 1)
+	require.Equal(t, "id", get.Parameters[0].Value.Name)
+	require.Nil(t, get.Responses["404"].Value.Content)
+
+	remove := swagger.Paths["/users/{id}"].Delete
+	require.Equal(t, "^[0-9]+$", remove.Parameters[0].Value.Schema.Value.Pattern)
+
+	require.NotNil(t, swagger.Paths["/files/{path}"].Get.Responses.Default())
+	members := swagger.Paths["/teams/{team}/members/{member}"].Get
+	require.Len(t, members.Parameters, 2)
+	require.Equal(t, []string{"teams"}, members.Tags)
+
+	routes.Add("GET", "/users/{id}")
+	_, err = routes.Swagger("Legacy", "0.1")
+	require.Error(t, err)
+}
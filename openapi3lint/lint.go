@@ -0,0 +1,149 @@
+// Package openapi3lint checks OpenAPI 3 documents with rules of style and completeness
+// that structurally valid documents may break.
+package openapi3lint
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Severity is the importance of a finding.
+type Severity int
+
+const (
+	SeverityInfo Severity = iota
+	SeverityWarning
+	SeverityError
+)
+
+func (severity Severity) String() string {
+	switch severity {
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	default:
+		return fmt.Sprintf("severity %d", int(severity))
+	}
+}
+
+// Finding describes a location of a document that breaks a rule.
+type Finding struct {
+	Rule     string
+	Severity Severity
+	// Pointer is the JSON pointer of the location, like "#/paths/~1pets/get".
+	Pointer string
+	Message string
+}
+
+func (finding *Finding) String() string {
+	return fmt.Sprintf("%s: %s: %s (%s)", finding.Severity, finding.Pointer, finding.Message, finding.Rule)
+}
+
+// Reporter reports a location of a document that breaks a rule.
+type Reporter func(pointer string, message string)
+
+// Rule checks a document, and reports locations that break the rule.
+type Rule struct {
+	// Name identifies the rule, like "operation-operationId".
+	Name string
+	// Severity is the default severity of findings of the rule.
+	Severity Severity
+	Check    func(swagger *openapi3.Swagger, report Reporter)
+}
+
+// RuleSet is a set of rules with severities that may differ from defaults of the rules.
+type RuleSet struct {
+	rules      []*Rule
+	severities map[string]Severity
+}
+
+// NewRuleSet returns a set of the rules.
+func NewRuleSet(rules ...*Rule) *RuleSet {
+	ruleSet := &RuleSet{severities: make(map[string]Severity)}
+	for _, rule := range rules {
+		ruleSet.Add(rule)
+	}
+	return ruleSet
+}
+
+// DefaultRuleSet returns a set of all rules of the package.
+func DefaultRuleSet() *RuleSet {
+	return NewRuleSet(
+		RuleOperationID,
+		RuleOperationDescription,
+		RuleOperationIDStyle,
+		RuleClientErrorResponse,
+		RulePathParameterStyle,
+		RuleUnusedComponent,
+	)
+}
+
+// Add adds the rule, or replaces a rule with the same name.
+func (ruleSet *RuleSet) Add(rule *Rule) *RuleSet {
+	for i, existing := range ruleSet.rules {
+		if existing.Name == rule.Name {
+			ruleSet.rules[i] = rule
+			return ruleSet
+		}
+	}
+	ruleSet.rules = append(ruleSet.rules, rule)
+	return ruleSet
+}
+
+// Disable removes the rule with the name.
+func (ruleSet *RuleSet) Disable(name string) *RuleSet {
+	for i, rule := range ruleSet.rules {
+		if rule.Name == name {
+			ruleSet.rules = append(ruleSet.rules[:i], ruleSet.rules[i+1:]...)
+			break
+		}
+	}
+	return ruleSet
+}
+
+// WithSeverity changes the severity of findings of the rule with the name.
+func (ruleSet *RuleSet) WithSeverity(name string, severity Severity) *RuleSet {
+	ruleSet.severities[name] = severity
+	return ruleSet
+}
+
+// Rules returns the rules of the set.
+func (ruleSet *RuleSet) Rules() []*Rule {
+	return append([]*Rule(nil), ruleSet.rules...)
+}
+
+// Lint returns findings of the rules, in the order of the rules and locations of the document.
+func (ruleSet *RuleSet) Lint(swagger *openapi3.Swagger) []*Finding {
+	var findings []*Finding
+	for _, rule := range ruleSet.rules {
+		severity := rule.Severity
+		if v, ok := ruleSet.severities[rule.Name]; ok {
+			severity = v
+		}
+		rule.Check(swagger, func(pointer string, message string) {
+			findings = append(findings, &Finding{
+				Rule:     rule.Name,
+				Severity: severity,
+				Pointer:  pointer,
+				Message:  message,
+			})
+		})
+	}
+	return findings
+}
+
+// Lint returns findings of rules of DefaultRuleSet.
+func Lint(swagger *openapi3.Swagger) []*Finding {
+	return DefaultRuleSet().Lint(swagger)
+}
+
+var jsonPointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+func escapeJSONPointer(token string) string {
+	return jsonPointerEscaper.Replace(token)
+}
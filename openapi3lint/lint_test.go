@@ -0,0 +1,119 @@
+package openapi3lint_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3lint"
+	"github.com/stretchr/testify/require"
+)
+
+const lintSpec = `
+openapi: 3.0.0
+info:
+  title: Pets
+  version: "1.0"
+security:
+  - apiKey: []
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      summary: List pets
+      responses:
+        "200":
+          description: pets
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: "#/components/schemas/Pet"
+        "4XX":
+          description: client error
+  /pets/{pet_id}:
+    parameters:
+      - name: pet_id
+        in: path
+        required: true
+        schema:
+          type: string
+    get:
+      operationId: get_pet
+      responses:
+        "200":
+          description: pet
+    delete:
+      responses:
+        default:
+          description: error
+components:
+  schemas:
+    Pet:
+      type: object
+    Owner:
+      type: object
+  securitySchemes:
+    apiKey:
+      type: apiKey
+      in: header
+      name: X-API-Key
+    basic:
+      type: http
+      scheme: basic
+`
+
+func TestLint(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(lintSpec))
+	require.NoError(t, err)
+
+	var findings []string
+	for _, finding := range openapi3lint.Lint(swagger) {
+		findings = append(findings, finding.String())
+	}
+	require.Equal(t, []string{
+		"warning: #/paths/~1pets~1{pet_id}/delete: Operation doesn't have operationId (operation-operationId)",
+		"info: #/paths/~1pets~1{pet_id}/delete: Operation doesn't have a summary or description (operation-description)",
+		"info: #/paths/~1pets~1{pet_id}/get: Operation doesn't have a summary or description (operation-description)",
+		"warning: #/paths/~1pets~1{pet_id}/get: OperationId 'get_pet' isn't camelCase like most operationIds (operation-operationId-style)",
+		"warning: #/paths/~1pets~1{pet_id}/get: Operation doesn't have a 4XX or default response (operation-4xx-response)",
+		"warning: #/paths/~1pets~1{pet_id}/parameters/0: Path parameter 'pet_id' isn't camelCase (path-parameter-style)",
+		"warning: #/components/schemas/Owner: Component 'Owner' is unused (unused-component)",
+		"warning: #/components/securitySchemes/basic: Security scheme 'basic' is unused (unused-component)",
+	}, findings)
+}
+
+func TestRuleSet(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(lintSpec))
+	require.NoError(t, err)
+
+	tagged := &openapi3lint.Rule{
+		Name:     "operation-tags",
+		Severity: openapi3lint.SeverityWarning,
+		Check: func(swagger *openapi3.Swagger, report openapi3lint.Reporter) {
+			openapi3.Walk(swagger, &openapi3.Visitor{
+				Operation: func(pointer string, method string, operation *openapi3.Operation) error {
+					if len(operation.Tags) == 0 {
+						report(pointer, "Operation doesn't have tags")
+					}
+					return nil
+				},
+			})
+		},
+	}
+	ruleSet := openapi3lint.NewRuleSet(openapi3lint.RuleOperationID, tagged).
+		WithSeverity("operation-operationId", openapi3lint.SeverityError)
+	require.Len(t, ruleSet.Rules(), 2)
+	findings := ruleSet.Lint(swagger)
+	require.Len(t, findings, 4)
+	require.Equal(t, &openapi3lint.Finding{
+		Rule:     "operation-operationId",
+		Severity: openapi3lint.SeverityError,
+		Pointer:  "#/paths/~1pets~1{pet_id}/delete",
+		Message:  "Operation doesn't have operationId",
+	}, findings[0])
+	require.True(t, strings.HasSuffix(findings[1].String(), "(operation-tags)"))
+
+	require.Len(t, ruleSet.Disable("operation-operationId").Lint(swagger), 3)
+}
@@ -0,0 +1,225 @@
+package openapi3lint
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// RuleOperationID reports operations without operationId.
+var RuleOperationID = &Rule{
+	Name:     "operation-operationId",
+	Severity: SeverityWarning,
+	Check: func(swagger *openapi3.Swagger, report Reporter) {
+		walkOperations(swagger, func(pointer string, operation *openapi3.Operation) {
+			if operation.OperationID == "" {
+				report(pointer, "Operation doesn't have operationId")
+			}
+		})
+	},
+}
+
+// RuleOperationDescription reports operations without a summary or description.
+var RuleOperationDescription = &Rule{
+	Name:     "operation-description",
+	Severity: SeverityInfo,
+	Check: func(swagger *openapi3.Swagger, report Reporter) {
+		walkOperations(swagger, func(pointer string, operation *openapi3.Operation) {
+			if operation.Summary == "" && operation.Description == "" {
+				report(pointer, "Operation doesn't have a summary or description")
+			}
+		})
+	},
+}
+
+// RuleOperationIDStyle reports operationIds that don't follow the naming style of most operationIds,
+// which is camelCase, PascalCase, snake_case, or kebab-case.
+var RuleOperationIDStyle = &Rule{
+	Name:     "operation-operationId-style",
+	Severity: SeverityWarning,
+	Check: func(swagger *openapi3.Swagger, report Reporter) {
+		var pointers, ids []string
+		walkOperations(swagger, func(pointer string, operation *openapi3.Operation) {
+			if id := operation.OperationID; id != "" {
+				pointers = append(pointers, pointer)
+				ids = append(ids, id)
+			}
+		})
+		style := mostCommonStyle(ids)
+		for i, id := range ids {
+			if !style.pattern.MatchString(id) {
+				report(pointers[i], fmt.Sprintf("OperationId '%s' isn't %s like most operationIds", id, style.name))
+			}
+		}
+	},
+}
+
+// RuleClientErrorResponse reports operations that declare neither a 4XX response nor a default response.
+var RuleClientErrorResponse = &Rule{
+	Name:     "operation-4xx-response",
+	Severity: SeverityWarning,
+	Check: func(swagger *openapi3.Swagger, report Reporter) {
+		walkOperations(swagger, func(pointer string, operation *openapi3.Operation) {
+			for status := range operation.Responses {
+				if status == "default" || strings.HasPrefix(status, "4") {
+					return
+				}
+			}
+			report(pointer, "Operation doesn't have a 4XX or default response")
+		})
+	},
+}
+
+// RulePathParameterStyle reports path parameters whose names aren't camelCase.
+var RulePathParameterStyle = &Rule{
+	Name:     "path-parameter-style",
+	Severity: SeverityWarning,
+	Check: func(swagger *openapi3.Swagger, report Reporter) {
+		openapi3.Walk(swagger, &openapi3.Visitor{
+			Parameter: func(pointer string, ref *openapi3.ParameterRef) error {
+				// Parameters of references are checked as components.
+				if ref.Ref != "" || ref.Value == nil {
+					return openapi3.SkipChildren
+				}
+				if parameter := ref.Value; parameter.In == openapi3.ParameterInPath && !camelCase.pattern.MatchString(parameter.Name) {
+					report(pointer, fmt.Sprintf("Path parameter '%s' isn't camelCase", parameter.Name))
+				}
+				return openapi3.SkipChildren
+			},
+		})
+	},
+}
+
+// RuleUnusedComponent reports components that the document doesn't refer to.
+// Security schemes are used by security requirements instead of references.
+var RuleUnusedComponent = &Rule{
+	Name:     "unused-component",
+	Severity: SeverityWarning,
+	Check: func(swagger *openapi3.Swagger, report Reporter) {
+		used := referencedComponents(swagger)
+		components := swagger.Components
+		check := func(kind string, m interface{}) {
+			for _, name := range sortedKeys(m) {
+				pointer := "#/components/" + kind + "/" + escapeJSONPointer(name)
+				if !used[pointer] {
+					report(pointer, fmt.Sprintf("Component '%s' is unused", name))
+				}
+			}
+		}
+		check("schemas", components.Schemas)
+		check("parameters", components.Parameters)
+		check("headers", components.Headers)
+		check("requestBodies", components.RequestBodies)
+		check("responses", components.Responses)
+		check("examples", components.Examples)
+		check("links", components.Links)
+		check("callbacks", components.Callbacks)
+
+		schemes := make(map[string]bool)
+		addSchemes := func(requirements openapi3.SecurityRequirements) {
+			for _, requirement := range requirements {
+				for name := range requirement {
+					schemes[name] = true
+				}
+			}
+		}
+		addSchemes(swagger.Security)
+		walkOperations(swagger, func(pointer string, operation *openapi3.Operation) {
+			if operation.Security != nil {
+				addSchemes(*operation.Security)
+			}
+		})
+		for _, name := range sortedKeys(components.SecuritySchemes) {
+			pointer := "#/components/securitySchemes/" + escapeJSONPointer(name)
+			if !schemes[name] && !used[pointer] {
+				report(pointer, fmt.Sprintf("Security scheme '%s' is unused", name))
+			}
+		}
+	},
+}
+
+// walkOperations calls the function with operations of the document, including operations of callbacks.
+func walkOperations(swagger *openapi3.Swagger, f func(pointer string, operation *openapi3.Operation)) {
+	openapi3.Walk(swagger, &openapi3.Visitor{
+		Operation: func(pointer string, method string, operation *openapi3.Operation) error {
+			f(pointer, operation)
+			return nil
+		},
+	})
+}
+
+// referencedComponents returns values of references of the document, like "#/components/schemas/Pet".
+func referencedComponents(swagger *openapi3.Swagger) map[string]bool {
+	refs := make(map[string]bool)
+	add := func(ref string) error {
+		if ref != "" {
+			refs[ref] = true
+		}
+		return nil
+	}
+	openapi3.Walk(swagger, &openapi3.Visitor{
+		Parameter:      func(pointer string, ref *openapi3.ParameterRef) error { return add(ref.Ref) },
+		RequestBody:    func(pointer string, ref *openapi3.RequestBodyRef) error { return add(ref.Ref) },
+		Response:       func(pointer string, ref *openapi3.ResponseRef) error { return add(ref.Ref) },
+		Header:         func(pointer string, ref *openapi3.HeaderRef) error { return add(ref.Ref) },
+		Example:        func(pointer string, ref *openapi3.ExampleRef) error { return add(ref.Ref) },
+		Link:           func(pointer string, ref *openapi3.LinkRef) error { return add(ref.Ref) },
+		Callback:       func(pointer string, ref *openapi3.CallbackRef) error { return add(ref.Ref) },
+		SecurityScheme: func(pointer string, ref *openapi3.SecuritySchemeRef) error { return add(ref.Ref) },
+		Schema: func(pointer string, ref *openapi3.SchemaRef) error {
+			if ref.Ref == "" && ref.Value != nil && ref.Value.Discriminator != nil {
+				for _, mapped := range ref.Value.Discriminator.Mapping {
+					add(mapped)
+				}
+			}
+			return add(ref.Ref)
+		},
+	})
+	return refs
+}
+
+// sortedKeys returns sorted keys of a map with string keys.
+func sortedKeys(m interface{}) []string {
+	keys := reflect.ValueOf(m).MapKeys()
+	result := make([]string, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, key.String())
+	}
+	sort.Strings(result)
+	return result
+}
+
+type namingStyle struct {
+	name    string
+	pattern *regexp.Regexp
+}
+
+var (
+	camelCase  = &namingStyle{"camelCase", regexp.MustCompile(`^[a-z][a-zA-Z0-9]*$`)}
+	pascalCase = &namingStyle{"PascalCase", regexp.MustCompile(`^[A-Z][a-zA-Z0-9]*$`)}
+	snakeCase  = &namingStyle{"snake_case", regexp.MustCompile(`^[a-z][a-z0-9]*(_[a-z0-9]+)*$`)}
+	kebabCase  = &namingStyle{"kebab-case", regexp.MustCompile(`^[a-z][a-z0-9]*(-[a-z0-9]+)*$`)}
+)
+
+// mostCommonStyle returns the style that most names follow.
+// Names like "list" follow several styles.
+func mostCommonStyle(names []string) *namingStyle {
+	var result *namingStyle
+	max := -1
+	for _, style := range []*namingStyle{camelCase, pascalCase, snakeCase, kebabCase} {
+		n := 0
+		for _, name := range names {
+			if style.pattern.MatchString(name) {
+				n++
+			}
+		}
+		if n > max {
+			result, max = style, n
+		}
+	}
+	return result
+}